@@ -0,0 +1,148 @@
+// Package bench measures the throughput of the xxHash implementations of this module
+// for a range of input sizes, returning structured results so that performance
+// can be tracked in any environment, without the go test tooling.
+package bench
+
+import (
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// DefaultMinTime is the default minimum duration of a measurement.
+const DefaultMinTime = 100 * time.Millisecond
+
+// Sizes are the default input sizes, from 1 byte to 1GB.
+var Sizes = []int{1, 4, 8, 16, 32, 64, 128, 256, 1 << 10, 4 << 10, 64 << 10, 1 << 20, 16 << 20, 256 << 20, 1 << 30}
+
+// Sinks keep the compiler from optimizing the hashing away.
+var (
+	sink32 uint32
+	sink64 uint64
+)
+
+// Kernel is a hashing function to measure.
+type Kernel struct {
+	// Name identifies the kernel in results.
+	Name string
+	// Func hashes data.
+	Func func(data []byte)
+}
+
+// Kernels returns the default kernels: one-shot and streaming hashing for each variant,
+// and parallel hashing for XXH64.
+func Kernels() []Kernel {
+	xxh32 := xxHash32.New(0)
+	xxh64 := xxHash64.New(0)
+	return []Kernel{
+		{"XXH32", func(data []byte) { sink32 = xxHash32.Checksum(data, 0) }},
+		{"XXH32/stream", func(data []byte) {
+			xxh32.Reset()
+			xxh32.Write(data)
+			sink32 = xxh32.Sum32()
+		}},
+		{"XXH64", func(data []byte) { sink64 = xxHash64.Checksum(data, 0) }},
+		{"XXH64/stream", func(data []byte) {
+			xxh64.Reset()
+			xxh64.Write(data)
+			sink64 = xxh64.Sum64()
+		}},
+		{"XXH64/parallel", func(data []byte) { sink64 = xxHash64.ParallelChecksum(data, 0, 0) }},
+	}
+}
+
+// Options configures a benchmark run.
+type Options struct {
+	// Kernels to measure, Kernels() if empty.
+	Kernels []Kernel
+	// Sizes of the inputs, Sizes if empty.
+	Sizes []int
+	// MaxSize, if positive, skips the sizes above it.
+	MaxSize int
+	// MinTime is the minimum duration of a measurement, DefaultMinTime if not positive.
+	MinTime time.Duration
+}
+
+// Result is the measurement of a kernel for an input size.
+type Result struct {
+	Kernel     string
+	Size       int
+	Iterations int
+	Duration   time.Duration // total duration of all the iterations
+}
+
+// NsPerOp returns the average duration of an iteration in nanoseconds.
+func (r Result) NsPerOp() float64 {
+	return float64(r.Duration.Nanoseconds()) / float64(r.Iterations)
+}
+
+// BytesPerSec returns the throughput in bytes per second.
+func (r Result) BytesPerSec() float64 {
+	return float64(r.Size) * float64(r.Iterations) / r.Duration.Seconds()
+}
+
+// Run measures every kernel for every size according to opts, in that order.
+// Inputs of all the sizes are prefixes of a single buffer allocated once.
+func Run(opts Options) []Result {
+	kernels := opts.Kernels
+	if len(kernels) == 0 {
+		kernels = Kernels()
+	}
+	sizes := opts.Sizes
+	if len(sizes) == 0 {
+		sizes = Sizes
+	}
+	max := 0
+	for _, n := range sizes {
+		if n > max && (opts.MaxSize <= 0 || n <= opts.MaxSize) {
+			max = n
+		}
+	}
+	data := make([]byte, max)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+
+	var res []Result
+	for _, k := range kernels {
+		for _, n := range sizes {
+			if opts.MaxSize > 0 && n > opts.MaxSize {
+				continue
+			}
+			res = append(res, Measure(k, data[:n], opts.MinTime))
+		}
+	}
+	return res
+}
+
+// Measure runs k over data for at least minTime, DefaultMinTime if not positive,
+// increasing the number of iterations until the run is long enough.
+func Measure(k Kernel, data []byte, minTime time.Duration) Result {
+	if minTime <= 0 {
+		minTime = DefaultMinTime
+	}
+	r := Result{Kernel: k.Name, Size: len(data)}
+	for n := 1; ; {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			k.Func(data)
+		}
+		d := time.Since(start)
+		if d >= minTime || n >= 1e9 {
+			r.Iterations, r.Duration = n, d
+			return r
+		}
+		// Aim for 1.2x minTime, growing at most 100x at a time.
+		next := n * 100
+		if d > 0 {
+			if m := int(float64(n) * 1.2 * float64(minTime) / float64(d)); m < next {
+				next = m
+			}
+		}
+		if next <= n {
+			next = n + 1
+		}
+		n = next
+	}
+}
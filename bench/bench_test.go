@@ -0,0 +1,37 @@
+package bench_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/bench"
+)
+
+func TestRun(t *testing.T) {
+	opts := bench.Options{Sizes: []int{1, 1000, 1 << 20}, MaxSize: 1000, MinTime: time.Millisecond}
+	res := bench.Run(opts)
+	kernels := bench.Kernels()
+	if len(res) != 2*len(kernels) {
+		t.Fatalf("got %d results expected %d", len(res), 2*len(kernels))
+	}
+	for i, r := range res {
+		if r.Kernel != kernels[i/2].Name || r.Size != opts.Sizes[i%2] {
+			t.Errorf("unexpected result %+v", r)
+		}
+		if r.Iterations < 1 || r.Duration < opts.MinTime || r.NsPerOp() <= 0 || r.BytesPerSec() <= 0 {
+			t.Errorf("invalid result %+v", r)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	var calls int
+	k := bench.Kernel{Name: "sleep", Func: func([]byte) {
+		calls++
+		time.Sleep(time.Millisecond)
+	}}
+	r := bench.Measure(k, nil, 5*time.Millisecond)
+	if r.Iterations < 5 || r.Duration < 5*time.Millisecond || calls < r.Iterations {
+		t.Errorf("invalid result %+v after %d calls", r, calls)
+	}
+}
@@ -0,0 +1,141 @@
+// Package bloom implements a Bloom filter (https://en.wikipedia.org/wiki/Bloom_filter)
+// based on xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// The k bit positions of a key are derived by double hashing from two xxHash64 Checksum of the key,
+// with seeds 0 and 1: position i is (h1 + i*h2) mod m, m being the number of bits of the filter.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// ErrInvalid is returned when unmarshaling invalid data.
+var ErrInvalid = errors.New("bloom: invalid filter data")
+
+// version of the binary encoding.
+const version = 1
+
+// Filter is a Bloom filter.
+type Filter struct {
+	m    uint64
+	k    int
+	bits []uint64
+}
+
+// New returns a Filter of m bits using k hash functions.
+// It panics if m or k are not positive.
+func New(m uint64, k int) *Filter {
+	if m == 0 || k <= 0 {
+		panic("bloom: invalid filter parameters")
+	}
+	return &Filter{
+		m:    m,
+		k:    k,
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// NewWithEstimates returns a Filter sized for n keys at a false positive rate of fpr.
+func NewWithEstimates(n uint64, fpr float64) *Filter {
+	return New(EstimateParameters(n, fpr))
+}
+
+// EstimateParameters returns the number of bits m and hash functions k
+// of a Filter holding n keys at a false positive rate of fpr.
+func EstimateParameters(n uint64, fpr float64) (m uint64, k int) {
+	if n == 0 {
+		n = 1
+	}
+	if fpr <= 0 || fpr >= 1 {
+		panic("bloom: false positive rate must be in ]0,1[")
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(fpr) / (math.Ln2 * math.Ln2)))
+	k = int(math.Ceil(float64(m) / float64(n) * math.Ln2))
+	return m, k
+}
+
+// FalsePositiveRate returns the expected false positive rate of a Filter
+// of m bits using k hash functions holding n keys.
+func FalsePositiveRate(m uint64, k int, n uint64) float64 {
+	return math.Pow(1-math.Exp(-float64(k)*float64(n)/float64(m)), float64(k))
+}
+
+// Cap returns the number of bits of the filter.
+func (f *Filter) Cap() uint64 {
+	return f.m
+}
+
+// K returns the number of hash functions of the filter.
+func (f *Filter) K() int {
+	return f.k
+}
+
+// Add adds key to the filter.
+func (f *Filter) Add(key []byte) {
+	h1, h2 := hashes(key)
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+// Test reports whether key may have been added to the filter.
+// False positives are possible, false negatives are not.
+func (f *Filter) Test(key []byte) bool {
+	h1, h2 := hashes(key)
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset removes all the keys from the filter.
+func (f *Filter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
+
+func hashes(key []byte) (uint64, uint64) {
+	return xxHash64.Checksum(key, 0), xxHash64.Checksum(key, 1)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 13+8*len(f.bits))
+	buf[0] = version
+	binary.LittleEndian.PutUint64(buf[1:], f.m)
+	binary.LittleEndian.PutUint32(buf[9:], uint32(f.k))
+	for i, w := range f.bits {
+		binary.LittleEndian.PutUint64(buf[13+8*i:], w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 13 || data[0] != version {
+		return ErrInvalid
+	}
+	m := binary.LittleEndian.Uint64(data[1:])
+	k := binary.LittleEndian.Uint32(data[9:])
+	data = data[13:]
+	// The number of words is checked without computing (m+63)/64*8, which overflows for large m.
+	if m == 0 || k == 0 || k > math.MaxInt32 || len(data)%8 != 0 || (m-1)/64+1 != uint64(len(data)/8) {
+		return ErrInvalid
+	}
+	f.m = m
+	f.k = int(k)
+	f.bits = make([]uint64, len(data)/8)
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package bloom_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pierrec/xxHash/bloom"
+)
+
+func key(i int) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(i))
+	return b[:]
+}
+
+func TestEstimateParameters(t *testing.T) {
+	m, k := bloom.EstimateParameters(1000, 0.01)
+	if m != 9586 || k != 7 {
+		t.Errorf("got m=%d k=%d expected m=9586 k=7", m, k)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const n, fpr = 10000, 0.01
+	f := bloom.NewWithEstimates(n, fpr)
+	for i := 0; i < n; i++ {
+		f.Add(key(i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.Test(key(i)) {
+			t.Fatalf("key %d not found", i)
+		}
+	}
+	var fp int
+	for i := n; i < 11*n; i++ {
+		if f.Test(key(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / (10 * n); rate > 2*fpr {
+		t.Errorf("false positive rate %f too high", rate)
+	}
+
+	f.Reset()
+	if f.Test(key(0)) {
+		t.Errorf("key found after Reset")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	f := bloom.New(1000, 3)
+	for i := 0; i < 100; i++ {
+		f.Add(key(i))
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g bloom.Filter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Cap() != f.Cap() || g.K() != f.K() {
+		t.Fatalf("got m=%d k=%d expected m=%d k=%d", g.Cap(), g.K(), f.Cap(), f.K())
+	}
+	for i := 0; i < 1000; i++ {
+		if f.Test(key(i)) != g.Test(key(i)) {
+			t.Fatalf("key %d: filters differ", i)
+		}
+	}
+
+	if err := g.UnmarshalBinary(data[:len(data)-1]); err != bloom.ErrInvalid {
+		t.Errorf("got error %v expected %v", err, bloom.ErrInvalid)
+	}
+
+	// A huge number of bits used to overflow the length check and pass with no data.
+	huge := []byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1, 0, 0, 0}
+	if err := g.UnmarshalBinary(huge); err != bloom.ErrInvalid {
+		t.Errorf("got error %v expected %v", err, bloom.ErrInvalid)
+	}
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	data, _ := bloom.New(100, 3).MarshalBinary()
+	f.Add(data)
+	f.Add([]byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1, 0, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var g bloom.Filter
+		if g.UnmarshalBinary(data) != nil {
+			return
+		}
+		// A valid filter must be usable.
+		g.Add(key(1))
+		if !g.Test(key(1)) {
+			t.Error("key not found after Add")
+		}
+	})
+}
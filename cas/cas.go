@@ -0,0 +1,159 @@
+// Package cas generates content addressable storage keys with xxHash64 (https://github.com/Cyan4973/xxHash/)
+// and verifies content on retrieval.
+//
+// XXH3-128 is not implemented by this module: a Key is made of two 64bits Hash values
+// of the content with distinct seeds, giving 128 bits keys whose collisions are unlikely
+// for non adversarial content. Keys are not cryptographic and must not be used to
+// address content provided by untrusted parties.
+package cas
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Seeds of the two halves of a Key.
+const (
+	seed1 = 0
+	seed2 = 11400714785074694797
+)
+
+var (
+	// ErrKey is returned when parsing an invalid key.
+	ErrKey = errors.New("cas: invalid key")
+	// ErrMismatch is returned when reading content that does not match its key.
+	ErrMismatch = errors.New("cas: content does not match its key")
+)
+
+// Key is the key of some content: the big endian 64bits Hash values of the content
+// with two distinct seeds.
+type Key [16]byte
+
+// Sum returns the Key of data.
+func Sum(data []byte) Key {
+	return makeKey(xxHash64.Checksum(data, seed1), xxHash64.Checksum(data, seed2))
+}
+
+// SumReader returns the Key of the data read from r until io.EOF and its size.
+func SumReader(r io.Reader) (Key, int64, error) {
+	h := NewHasher()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return Key{}, n, err
+	}
+	return h.Key(), n, nil
+}
+
+func makeKey(h1, h2 uint64) Key {
+	var k Key
+	binary.BigEndian.PutUint64(k[:], h1)
+	binary.BigEndian.PutUint64(k[8:], h2)
+	return k
+}
+
+// String returns the key as 32 lower case hexadecimal digits.
+func (k Key) String() string {
+	return hex.EncodeToString(k[:])
+}
+
+// Base64 returns the key as 22 characters of unpadded base64 with the URL and file name safe alphabet.
+func (k Key) Base64() string {
+	return base64.RawURLEncoding.EncodeToString(k[:])
+}
+
+// Path returns the hexadecimal key prefixed with depth levels of directories
+// named after its first pairs of hexadecimal digits, to spread keys over directories.
+// For instance, with a depth of 2:
+//
+//	ab/cd/abcdef0123456789abcdef0123456789
+//
+// It panics if depth is negative or larger than 16.
+func (k Key) Path(depth int) string {
+	if depth < 0 || depth > len(k) {
+		panic("cas: invalid path depth")
+	}
+	s := k.String()
+	var b strings.Builder
+	b.Grow(3*depth + len(s))
+	for i := 0; i < depth; i++ {
+		b.WriteString(s[2*i : 2*i+2])
+		b.WriteByte('/')
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// ParseKey parses a key in either its hexadecimal or its base64 form.
+func ParseKey(s string) (Key, error) {
+	var k Key
+	var err error
+	switch len(s) {
+	case 2 * len(k):
+		_, err = hex.Decode(k[:], []byte(s))
+	case base64.RawURLEncoding.EncodedLen(len(k)):
+		_, err = base64.RawURLEncoding.Decode(k[:], []byte(s))
+	default:
+		err = ErrKey
+	}
+	if err != nil {
+		return Key{}, ErrKey
+	}
+	return k, nil
+}
+
+// Hasher computes the Key of the data written to it.
+type Hasher struct {
+	h1, h2 hash.Hash64
+}
+
+// NewHasher returns a new Hasher.
+func NewHasher() *Hasher {
+	return &Hasher{xxHash64.New(seed1), xxHash64.New(seed2)}
+}
+
+// Write adds data to the Hasher.
+// It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	h.h1.Write(p)
+	return h.h2.Write(p)
+}
+
+// Key returns the Key of the data written so far.
+func (h *Hasher) Key() Key {
+	return makeKey(h.h1.Sum64(), h.h2.Sum64())
+}
+
+// Reset resets the Hasher to its initial state.
+func (h *Hasher) Reset() {
+	h.h1.Reset()
+	h.h2.Reset()
+}
+
+// NewReader returns a reader returning the data read from r and checking it against key
+// when reaching its end: it then returns ErrMismatch instead of io.EOF if the content does not match.
+// The data returned before the end is not verified yet.
+func NewReader(r io.Reader, key Key) io.Reader {
+	return &reader{r: r, key: key, h: NewHasher()}
+}
+
+type reader struct {
+	r   io.Reader
+	key Key
+	h   *Hasher
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	if err == io.EOF && r.h.Key() != r.key {
+		err = ErrMismatch
+	}
+	return n, err
+}
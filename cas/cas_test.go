@@ -0,0 +1,73 @@
+package cas_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/cas"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestSum(t *testing.T) {
+	data := []byte("hello world")
+	k := cas.Sum(data)
+	if got, want := k.String()[:16], xxHash64.Hash64(xxHash64.Checksum(data, 0)).String(); got != want {
+		t.Errorf("got first half %s expected %s", got, want)
+	}
+	if k == cas.Sum(data[1:]) {
+		t.Error("distinct content with the same key")
+	}
+
+	sk, n, err := cas.SumReader(iotest.HalfReader(bytes.NewReader(data)))
+	if err != nil || sk != k || n != int64(len(data)) {
+		t.Errorf("got %v, %d, %v expected %v", sk, n, err, k)
+	}
+
+	h := cas.NewHasher()
+	h.Write([]byte("garbage"))
+	h.Reset()
+	h.Write(data[:5])
+	h.Write(data[5:])
+	if h.Key() != k {
+		t.Errorf("got %v expected %v", h.Key(), k)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	k := cas.Sum([]byte("hello world"))
+	s, b := k.String(), k.Base64()
+	if len(s) != 32 || len(b) != 22 {
+		t.Fatalf("got %q and %q", s, b)
+	}
+	for _, v := range []string{s, b} {
+		if pk, err := cas.ParseKey(v); err != nil || pk != k {
+			t.Errorf("%q: got %v, %v expected %v", v, pk, err, k)
+		}
+	}
+	for _, v := range []string{"", s[1:], "z" + s[1:], b[1:] + "!"} {
+		if _, err := cas.ParseKey(v); err != cas.ErrKey {
+			t.Errorf("%q: got error %v expected %v", v, err, cas.ErrKey)
+		}
+	}
+
+	if got := k.Path(0); got != s {
+		t.Errorf("got %q expected %q", got, s)
+	}
+	if got, want := k.Path(2), s[:2]+"/"+s[2:4]+"/"+s; got != want {
+		t.Errorf("got %q expected %q", got, want)
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := []byte("hello world")
+	k := cas.Sum(data)
+	got, err := io.ReadAll(cas.NewReader(bytes.NewReader(data), k))
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := io.ReadAll(cas.NewReader(bytes.NewReader(data[1:]), k)); err != cas.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, cas.ErrMismatch)
+	}
+}
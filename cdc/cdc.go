@@ -0,0 +1,238 @@
+// Package cdc implements FastCDC style content-defined chunking
+// (https://www.usenix.org/conference/atc16/technical-sessions/presentation/xia)
+// whose gear table is derived from xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// Chunk boundaries only depend on the content around them, so that inserting or
+// removing data only changes the chunks around the modification.
+// An Index maps the chunks to their storage location for deduplication.
+package cdc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// ErrConfig is returned for invalid chunk sizes.
+var ErrConfig = errors.New("cdc: invalid configuration")
+
+// Config defines the chunk sizes.
+type Config struct {
+	// MinSize is the minimum size of a chunk, except for the last one.
+	MinSize int
+	// AvgSize is the targeted average size of a chunk.
+	AvgSize int
+	// MaxSize is the maximum size of a chunk.
+	MaxSize int
+	// Normalization is the FastCDC normalization level, up to 3, which narrows
+	// the distribution of the chunk sizes around AvgSize.
+	// Zero selects the default level 2 and a negative value disables normalization.
+	Normalization int
+	// Seed derives the gear table, as returned by GenerateGearTable.
+	Seed uint64
+}
+
+// DefaultConfig produces chunks of 8KiB on average.
+var DefaultConfig = Config{
+	MinSize: 2 << 10,
+	AvgSize: 8 << 10,
+	MaxSize: 64 << 10,
+}
+
+func (cfg Config) valid() bool {
+	return cfg.MinSize > 0 && cfg.MinSize <= cfg.AvgSize && cfg.AvgSize <= cfg.MaxSize &&
+		cfg.Normalization <= 3
+}
+
+// normalization is the default FastCDC normalization level.
+const normalization = 2
+
+// level returns the normalization level of cfg.
+func (cfg Config) level() int {
+	switch {
+	case cfg.Normalization == 0:
+		return normalization
+	case cfg.Normalization < 0:
+		return 0
+	}
+	return cfg.Normalization
+}
+
+// Chunk is a piece of the chunked data.
+type Chunk struct {
+	// Offset of the chunk in the data.
+	Offset int64
+	// Data of the chunk, only valid until the next call to Next.
+	Data []byte
+	// Hash is the xxHash64 Checksum of the chunk data with a zero seed.
+	Hash uint64
+}
+
+// Chunker splits data read from an io.Reader into chunks.
+type Chunker struct {
+	r   io.Reader
+	d   Detector
+	off int64
+}
+
+// New returns a Chunker reading from r.
+func New(r io.Reader, cfg Config) (*Chunker, error) {
+	c := &Chunker{r: r}
+	if err := c.d.init(cfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Next returns the next chunk.
+// It returns io.EOF once all the data has been returned.
+func (c *Chunker) Next() (Chunk, error) {
+	return c.NextContext(context.Background())
+}
+
+// NextContext is like Next but returns ctx.Err() if ctx is done
+// before the chunk is read. ctx is checked before every read.
+func (c *Chunker) NextContext(ctx context.Context) (Chunk, error) {
+	data, err := c.d.NextContext(ctx, c.r)
+	if err != nil {
+		return Chunk{}, err
+	}
+	chunk := Chunk{
+		Offset: c.off,
+		Data:   data,
+		Hash:   xxHash64.Checksum(data, 0),
+	}
+	c.off += int64(len(data))
+	return chunk, nil
+}
+
+// Detector finds the chunk boundaries of a stream.
+type Detector struct {
+	cfg   Config
+	gear  *[256]uint64
+	maskS uint64
+	maskL uint64
+	buf   []byte
+	start int
+	end   int
+	eof   bool
+}
+
+// NewDetector returns a Detector for the chunk sizes and gear table defined by cfg.
+func NewDetector(cfg Config) (*Detector, error) {
+	d := new(Detector)
+	if err := d.init(cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Detector) init(cfg Config) error {
+	if !cfg.valid() {
+		return ErrConfig
+	}
+	b := bits.Len(uint(cfg.AvgSize)) - 1
+	*d = Detector{
+		cfg:   cfg,
+		gear:  gear,
+		maskS: mask(b + cfg.level()),
+		maskL: mask(b - cfg.level()),
+		buf:   make([]byte, cfg.MaxSize),
+	}
+	if cfg.Seed != 0 {
+		d.gear = gearTable(cfg.Seed)
+	}
+	return nil
+}
+
+// mask returns a mask of the n most significant bits.
+func mask(n int) uint64 {
+	if n <= 0 {
+		return 0
+	}
+	if n > 64 {
+		n = 64
+	}
+	return ^uint64(0) << (64 - n)
+}
+
+// Next returns the next chunk of the data read from r, only valid until the next call to Next.
+// It returns io.EOF once r is exhausted and all its data has been returned,
+// after which the Detector can be used with another reader.
+//
+// Data read from r beyond the chunk is kept for the following calls,
+// which must be passed the same reader until io.EOF.
+func (d *Detector) Next(r io.Reader) (chunk []byte, err error) {
+	return d.NextContext(context.Background(), r)
+}
+
+// NextContext is like Next but returns ctx.Err() if ctx is done
+// before the chunk is read. ctx is checked before every read.
+func (d *Detector) NextContext(ctx context.Context, r io.Reader) (chunk []byte, err error) {
+	if err := d.fill(ctx, r); err != nil {
+		return nil, err
+	}
+	if d.start == d.end {
+		d.eof = false
+		return nil, io.EOF
+	}
+	data := d.buf[d.start:d.end]
+	data = data[:d.cut(data)]
+	d.start += len(data)
+	return data, nil
+}
+
+// fill reads data until the buffer is full or the reader is exhausted.
+func (d *Detector) fill(ctx context.Context, r io.Reader) error {
+	if d.start > 0 {
+		d.end = copy(d.buf, d.buf[d.start:d.end])
+		d.start = 0
+	}
+	for !d.eof && d.end < len(d.buf) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := r.Read(d.buf[d.end:])
+		d.end += n
+		switch err {
+		case nil:
+		case io.EOF:
+			d.eof = true
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// cut returns the size of the chunk at the start of data.
+func (d *Detector) cut(data []byte) int {
+	n := len(data)
+	if n <= d.cfg.MinSize {
+		return n
+	}
+	normal := d.cfg.AvgSize
+	if n < normal {
+		normal = n
+	}
+
+	gear := d.gear
+	var fp uint64
+	i := d.cfg.MinSize
+	for ; i < normal; i++ {
+		fp = fp<<1 + gear[data[i]]
+		if fp&d.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = fp<<1 + gear[data[i]]
+		if fp&d.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,214 @@
+package cdc_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/cdc"
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func chunks(t *testing.T, r io.Reader, cfg cdc.Config) []cdc.Chunk {
+	c, err := cdc.New(r, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []cdc.Chunk
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunk.Data = append([]byte(nil), chunk.Data...)
+		res = append(res, chunk)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	cfg := cdc.DefaultConfig
+	data := testvectors.Input(1 << 20)
+	res := chunks(t, iotest.HalfReader(bytes.NewReader(data)), cfg)
+
+	var off int64
+	for i, chunk := range res {
+		if chunk.Offset != off {
+			t.Fatalf("chunk %d: got offset %d expected %d", i, chunk.Offset, off)
+		}
+		n := len(chunk.Data)
+		if n > cfg.MaxSize || n < cfg.MinSize && i < len(res)-1 {
+			t.Errorf("chunk %d: invalid size %d", i, n)
+		}
+		if !bytes.Equal(chunk.Data, data[off:off+int64(n)]) {
+			t.Fatalf("chunk %d: invalid data", i)
+		}
+		if h := xxHash64.Checksum(chunk.Data, 0); chunk.Hash != h {
+			t.Errorf("chunk %d: got hash 0x%x expected 0x%x", i, chunk.Hash, h)
+		}
+		off += int64(n)
+	}
+	if off != int64(len(data)) {
+		t.Errorf("got %d bytes expected %d", off, len(data))
+	}
+	if avg := len(data) / len(res); avg < cfg.AvgSize/2 || avg > 2*cfg.AvgSize {
+		t.Errorf("average chunk size %d too far from %d", avg, cfg.AvgSize)
+	}
+}
+
+func TestChunkerShift(t *testing.T) {
+	data := testvectors.Input(1 << 20)
+	orig := chunks(t, bytes.NewReader(data), cdc.DefaultConfig)
+	shifted := chunks(t, bytes.NewReader(append([]byte("some inserted data"), data...)), cdc.DefaultConfig)
+
+	seen := make(map[uint64]bool)
+	for _, chunk := range orig {
+		seen[chunk.Hash] = true
+	}
+	var shared int
+	for _, chunk := range shifted {
+		if seen[chunk.Hash] {
+			shared++
+		}
+	}
+	if shared < len(orig)-2 {
+		t.Errorf("only %d out of %d chunks preserved", shared, len(orig))
+	}
+}
+
+func TestConfig(t *testing.T) {
+	for _, cfg := range []cdc.Config{
+		{},
+		{MinSize: 10, AvgSize: 5, MaxSize: 20},
+		{MinSize: 10, AvgSize: 20, MaxSize: 15},
+		{MinSize: 10, AvgSize: 20, MaxSize: 30, Normalization: 4},
+	} {
+		if _, err := cdc.New(nil, cfg); err != cdc.ErrConfig {
+			t.Errorf("%+v: got error %v expected %v", cfg, err, cdc.ErrConfig)
+		}
+		if _, err := cdc.NewDetector(cfg); err != cdc.ErrConfig {
+			t.Errorf("%+v: got error %v expected %v", cfg, err, cdc.ErrConfig)
+		}
+	}
+}
+
+func sizes(t *testing.T, d *cdc.Detector, r io.Reader) []int {
+	var res []int
+	for {
+		chunk, err := d.Next(r)
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, len(chunk))
+	}
+}
+
+func TestDetector(t *testing.T) {
+	data := testvectors.Input(1 << 20)
+	d, err := cdc.NewDetector(cdc.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sizes(t, d, bytes.NewReader(data))
+	var want []int
+	for _, chunk := range chunks(t, bytes.NewReader(data), cdc.DefaultConfig) {
+		want = append(want, len(chunk.Data))
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got sizes %v expected %v", got, want)
+	}
+	// The Detector is reusable once a reader is exhausted.
+	if again := sizes(t, d, bytes.NewReader(data)); fmt.Sprint(again) != fmt.Sprint(want) {
+		t.Errorf("got sizes %v on reuse expected %v", again, want)
+	}
+
+	cfg := cdc.DefaultConfig
+	cfg.Seed = 1
+	d1, _ := cdc.NewDetector(cfg)
+	seeded := sizes(t, d1, bytes.NewReader(data))
+	if fmt.Sprint(seeded) == fmt.Sprint(want) {
+		t.Error("the seed did not change the boundaries")
+	}
+	d2, _ := cdc.NewDetector(cfg)
+	if again := sizes(t, d2, bytes.NewReader(data)); fmt.Sprint(again) != fmt.Sprint(seeded) {
+		t.Error("the boundaries are not deterministic")
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	data := testvectors.Input(4 << 20)
+	// Higher levels get the chunk sizes closer to the average.
+	prev := math.Inf(1)
+	for _, level := range []int{-1, 1, 2, 3} {
+		cfg := cdc.DefaultConfig
+		cfg.Normalization = level
+		d, err := cdc.NewDetector(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dev float64
+		res := sizes(t, d, bytes.NewReader(data))
+		for _, n := range res {
+			dev += math.Abs(float64(n - cfg.AvgSize))
+		}
+		dev /= float64(len(res))
+		if dev >= prev {
+			t.Errorf("level %d: mean deviation %.0f not below %.0f", level, dev, prev)
+		}
+		prev = dev
+	}
+}
+
+func TestGenerateGearTable(t *testing.T) {
+	for _, tc := range []struct {
+		seed        uint64
+		first, last uint64
+	}{
+		{0, 0xe934a84adb052768, 0x95634172a60b7544},
+		{1, 0x771917c7f6ee2451, 0x0d3fb8190a01a270},
+	} {
+		g := cdc.GenerateGearTable(tc.seed)
+		if g[0] != tc.first || g[255] != tc.last {
+			t.Errorf("seed %d: got %#x..%#x expected %#x..%#x", tc.seed, g[0], g[255], tc.first, tc.last)
+		}
+		for b := range g {
+			if h := xxHash64.Checksum([]byte{byte(b)}, tc.seed); g[b] != h {
+				t.Fatalf("seed %d: byte %d: got %#x expected %#x", tc.seed, b, g[b], h)
+			}
+		}
+	}
+}
+
+func TestNextContext(t *testing.T) {
+	data := testvectors.Input(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := cdc.New(iotest.HalfReader(bytes.NewReader(data)), cdc.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.NextContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := c.NextContext(ctx); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+
+	d, err := cdc.NewDetector(cdc.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.NextContext(ctx, bytes.NewReader(data)); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+}
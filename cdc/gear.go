@@ -0,0 +1,23 @@
+package cdc
+
+import "github.com/pierrec/xxHash/xxHash64"
+
+// gear holds the per byte values of the gear rolling hash.
+var gear = gearTable(0)
+
+// GenerateGearTable returns the gear table derived from seed:
+// the value for byte b is the xxHash64 Checksum of the single byte b with the seed.
+// Any xxHash implementation produces the same table, so that chunkers
+// written in other languages can share the chunk boundaries.
+func GenerateGearTable(seed uint64) [256]uint64 {
+	var t [256]uint64
+	for i := range t {
+		t[i] = xxHash64.Checksum([]byte{byte(i)}, seed)
+	}
+	return t
+}
+
+func gearTable(seed uint64) *[256]uint64 {
+	t := GenerateGearTable(seed)
+	return &t
+}
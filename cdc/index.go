@@ -0,0 +1,148 @@
+package cdc
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// ErrIndex is returned when unmarshaling invalid index data.
+var ErrIndex = errors.New("cdc: invalid index data")
+
+// version of the binary encoding of Index.
+const version = 1
+
+// indexEntrySize is the size of an encoded entry: hash, container, offset and size.
+const indexEntrySize = 8 + 8 + 8 + 4
+
+// Location is where a chunk is stored.
+type Location struct {
+	// Container identifies the storage unit holding the chunk, such as a pack file.
+	Container uint64
+	// Offset of the chunk in the container.
+	Offset uint64
+	// Size of the chunk.
+	Size uint32
+}
+
+// Index maps the chunks, by their xxHash64 Checksum, to their Location.
+// It is the backing store of deduplicating tools: a chunk returned by Chunker
+// only needs to be stored if it is not in the Index yet.
+//
+// Chunks with the same Checksum are considered identical unless Verify is set,
+// in which case the chunks that fail verification are stored alongside each other.
+//
+// The zero Index is empty and ready for use.
+type Index struct {
+	// Verify, if not nil, reports whether the chunk stored at loc holds data.
+	// It is called for every indexed chunk with the same Checksum as data.
+	Verify func(loc Location, data []byte) (bool, error)
+
+	locs map[uint64]Location
+	// more holds the locations of the chunks colliding with the one in locs.
+	more map[uint64][]Location
+	n    int
+}
+
+// Len returns the number of chunks in the index.
+func (x *Index) Len() int {
+	return x.n
+}
+
+// Lookup returns the location of chunk, and whether it was found.
+// It only returns an error if Verify does.
+func (x *Index) Lookup(chunk Chunk) (Location, bool, error) {
+	loc, ok := x.locs[chunk.Hash]
+	if !ok {
+		return Location{}, false, nil
+	}
+	if x.Verify == nil {
+		return loc, true, nil
+	}
+	if ok, err := x.Verify(loc, chunk.Data); ok || err != nil {
+		return loc, ok, err
+	}
+	for _, loc := range x.more[chunk.Hash] {
+		if ok, err := x.Verify(loc, chunk.Data); ok || err != nil {
+			return loc, ok, err
+		}
+	}
+	return Location{}, false, nil
+}
+
+// Add adds chunk stored at loc to the index, unless it already is,
+// and returns the location of the chunk and whether it was already indexed.
+// It only returns an error if Verify does.
+func (x *Index) Add(chunk Chunk, loc Location) (Location, bool, error) {
+	if old, ok, err := x.Lookup(chunk); ok || err != nil {
+		return old, ok, err
+	}
+	x.add(chunk.Hash, loc)
+	return loc, false, nil
+}
+
+func (x *Index) add(h uint64, loc Location) {
+	if x.locs == nil {
+		x.locs = map[uint64]Location{}
+	}
+	if _, ok := x.locs[h]; !ok {
+		x.locs[h] = loc
+	} else {
+		if x.more == nil {
+			x.more = map[uint64][]Location{}
+		}
+		x.more[h] = append(x.more[h], loc)
+	}
+	x.n++
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The entries are sorted by Checksum so that equal indexes have the same encoding.
+func (x *Index) MarshalBinary() ([]byte, error) {
+	hashes := make([]uint64, 0, len(x.locs))
+	for h := range x.locs {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	buf := make([]byte, 9, 9+indexEntrySize*x.n)
+	buf[0] = version
+	binary.LittleEndian.PutUint64(buf[1:], uint64(x.n))
+	put := func(h uint64, loc Location) {
+		buf = binary.LittleEndian.AppendUint64(buf, h)
+		buf = binary.LittleEndian.AppendUint64(buf, loc.Container)
+		buf = binary.LittleEndian.AppendUint64(buf, loc.Offset)
+		buf = binary.LittleEndian.AppendUint32(buf, loc.Size)
+	}
+	for _, h := range hashes {
+		put(h, x.locs[h])
+		for _, loc := range x.more[h] {
+			put(h, loc)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It keeps Verify.
+func (x *Index) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 || data[0] != version {
+		return ErrIndex
+	}
+	n := binary.LittleEndian.Uint64(data[1:])
+	data = data[9:]
+	if uint64(len(data))/indexEntrySize != n || len(data)%indexEntrySize != 0 {
+		return ErrIndex
+	}
+	x.locs = make(map[uint64]Location, n)
+	x.more = nil
+	x.n = 0
+	for ; len(data) > 0; data = data[indexEntrySize:] {
+		x.add(binary.LittleEndian.Uint64(data), Location{
+			Container: binary.LittleEndian.Uint64(data[8:]),
+			Offset:    binary.LittleEndian.Uint64(data[16:]),
+			Size:      binary.LittleEndian.Uint32(data[24:]),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cdc_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pierrec/xxHash/cdc"
+	"github.com/pierrec/xxHash/testvectors"
+)
+
+func TestIndex(t *testing.T) {
+	data := testvectors.Input(1 << 20)
+	// The second half repeats the first one.
+	data = append(data, data...)
+
+	var x cdc.Index
+	var stored, dups int
+	for _, chunk := range chunks(t, bytes.NewReader(data), cdc.DefaultConfig) {
+		loc := cdc.Location{Offset: uint64(chunk.Offset), Size: uint32(len(chunk.Data))}
+		got, ok, err := x.Add(chunk, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			stored++
+			continue
+		}
+		dups++
+		if !bytes.Equal(data[got.Offset:got.Offset+uint64(got.Size)], chunk.Data) {
+			t.Fatalf("chunk at %d: invalid location %+v", chunk.Offset, got)
+		}
+	}
+	if x.Len() != stored {
+		t.Errorf("got length %d expected %d", x.Len(), stored)
+	}
+	// Only the chunks around the junction of the halves differ.
+	if dups < stored*9/10 {
+		t.Errorf("only %d duplicates for %d chunks", dups, stored)
+	}
+}
+
+func TestIndexVerify(t *testing.T) {
+	store := map[uint64][]byte{}
+	fail := errors.New("fail")
+	var x cdc.Index
+	x.Verify = func(loc cdc.Location, data []byte) (bool, error) {
+		if loc.Container == 99 {
+			return false, fail
+		}
+		return bytes.Equal(store[loc.Container], data), nil
+	}
+	// a and b have the same (forged) Checksum.
+	a := cdc.Chunk{Data: []byte("a"), Hash: 1}
+	b := cdc.Chunk{Data: []byte("b"), Hash: 1}
+	store[1], store[2] = a.Data, b.Data
+
+	if _, ok, _ := x.Add(a, cdc.Location{Container: 1}); ok {
+		t.Fatal("a already indexed")
+	}
+	if _, ok, _ := x.Add(b, cdc.Location{Container: 2}); ok {
+		t.Fatal("b confused with a")
+	}
+	for _, c := range []cdc.Chunk{a, b} {
+		loc, ok, err := x.Lookup(c)
+		if err != nil || !ok || !bytes.Equal(store[loc.Container], c.Data) {
+			t.Errorf("%s: got %+v, %v, %v", c.Data, loc, ok, err)
+		}
+	}
+	if x.Len() != 2 {
+		t.Errorf("got length %d expected 2", x.Len())
+	}
+
+	if _, ok, _ := x.Lookup(cdc.Chunk{Data: []byte("c"), Hash: 1}); ok {
+		t.Error("c found")
+	}
+	x.Add(cdc.Chunk{Data: []byte("d"), Hash: 2}, cdc.Location{Container: 99})
+	if _, _, err := x.Lookup(cdc.Chunk{Data: []byte("d"), Hash: 2}); err != fail {
+		t.Errorf("got error %v expected %v", err, fail)
+	}
+}
+
+func TestIndexMarshal(t *testing.T) {
+	golden := uint64(0x9e3779b97f4a7c15)
+	var x cdc.Index
+	for i := uint64(0); i < 100; i++ {
+		x.Add(cdc.Chunk{Hash: i * golden}, cdc.Location{Container: i % 3, Offset: i << 10, Size: uint32(i)})
+	}
+	// A collision, kept without Verify by unmarshaling.
+	x.Verify = func(cdc.Location, []byte) (bool, error) { return false, nil }
+	x.Add(cdc.Chunk{Hash: 0}, cdc.Location{Container: 7})
+
+	data, err := x.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var y cdc.Index
+	if err := y.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if y.Len() != x.Len() {
+		t.Errorf("got length %d expected %d", y.Len(), x.Len())
+	}
+	if again, _ := y.MarshalBinary(); !bytes.Equal(again, data) {
+		t.Error("encodings differ")
+	}
+	if loc, ok, _ := y.Lookup(cdc.Chunk{Hash: golden * 5}); !ok || loc.Offset != 5<<10 || loc.Size != 5 {
+		t.Errorf("got %+v, %v", loc, ok)
+	}
+
+	for _, bad := range [][]byte{nil, {2}, data[:len(data)-1], append(data[:len(data):len(data)], 0)} {
+		if err := y.UnmarshalBinary(bad); err != cdc.ErrIndex {
+			t.Errorf("got error %v expected %v", err, cdc.ErrIndex)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package example is used to test the code generated by xxhashgen.
+package example
+
+import "time"
+
+//go:generate go run github.com/pierrec/xxHash/cmd/xxhashgen
+
+// Mode is a named integer type.
+type Mode uint8
+
+// Blob is a named byte type.
+type Blob byte
+
+// Tags is a named slice type.
+type Tags []Name
+
+// Name is a named string type.
+type Name string
+
+// Config has fields of most kinds.
+//
+//xxhash:generate
+type Config struct {
+	Endpoint
+	Name     string
+	Port     uint16
+	Enabled  bool
+	Ratio    float32
+	Mode     Mode
+	Tags     Tags
+	Data     []byte
+	Blobs    []Blob
+	Sum      [4]byte
+	Limit    *int
+	Backends []*Endpoint
+	Matrix   [][2]float64
+	Labels   map[string]string
+	Extra    any
+	Timeout  time.Duration
+	Renamed  int       `xxhash:"Other"`
+	Updated  time.Time `xxhash:"-"`
+	A, B     int
+	private  int
+}
+
+// Endpoint is embedded in Config.
+//
+//xxhash:generate
+type Endpoint struct {
+	Host string
+	C    complex64
+}
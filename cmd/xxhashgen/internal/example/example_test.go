@@ -0,0 +1,115 @@
+package example_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/cmd/xxhashgen/internal/example"
+	"github.com/pierrec/xxHash/xxhstruct"
+)
+
+// endpoint and config mirror Endpoint and Config without their HashXX methods,
+// so that xxhstruct.Hash encodes them by reflection instead of calling the generated code.
+type endpoint struct {
+	Host string
+	C    complex64
+}
+
+type config struct {
+	Endpoint endpoint
+	Name     string
+	Port     uint16
+	Enabled  bool
+	Ratio    float32
+	Mode     example.Mode
+	Tags     example.Tags
+	Data     []byte
+	Blobs    []example.Blob
+	Sum      [4]byte
+	Limit    *int
+	Backends []*endpoint
+	Matrix   [][2]float64
+	Labels   map[string]string
+	Extra    any
+	Timeout  time.Duration
+	Renamed  int       `xxhash:"Other"`
+	Updated  time.Time `xxhash:"-"`
+	A, B     int
+}
+
+func mirror(c example.Config) config {
+	m := config{
+		Endpoint: endpoint(c.Endpoint),
+		Name:     c.Name,
+		Port:     c.Port,
+		Enabled:  c.Enabled,
+		Ratio:    c.Ratio,
+		Mode:     c.Mode,
+		Tags:     c.Tags,
+		Data:     c.Data,
+		Blobs:    c.Blobs,
+		Sum:      c.Sum,
+		Limit:    c.Limit,
+		Matrix:   c.Matrix,
+		Labels:   c.Labels,
+		Extra:    c.Extra,
+		Timeout:  c.Timeout,
+		Renamed:  c.Renamed,
+		Updated:  c.Updated,
+		A:        c.A,
+		B:        c.B,
+	}
+	for _, e := range c.Backends {
+		m.Backends = append(m.Backends, (*endpoint)(e))
+	}
+	return m
+}
+
+func TestGenerated(t *testing.T) {
+	limit := 10
+	for i, c := range []example.Config{
+		{},
+		{
+			Endpoint: example.Endpoint{Host: "localhost", C: 1 + 2i},
+			Name:     "srv",
+			Port:     8080,
+			Enabled:  true,
+			Ratio:    0.25,
+			Mode:     3,
+			Tags:     example.Tags{"a", "b"},
+			Data:     []byte("data"),
+			Blobs:    []example.Blob{1, 2, 3},
+			Sum:      [4]byte{1, 2, 3, 4},
+			Limit:    &limit,
+			Backends: []*example.Endpoint{{Host: "a"}, nil},
+			Matrix:   [][2]float64{{1, 2}, {3, 4}},
+			Labels:   map[string]string{"k": "v", "x": "y"},
+			Extra:    []int{1},
+			Timeout:  time.Second,
+			Renamed:  7,
+			Updated:  time.Now(),
+			A:        1,
+			B:        2,
+		},
+	} {
+		for _, seed := range []uint64{0, 0xCAFE} {
+			want, err := xxhstruct.Hash(mirror(c), seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Hash64(seed); got != want {
+				t.Errorf("config %d seed %d: got 0x%x expected 0x%x", i, seed, got, want)
+			}
+		}
+	}
+}
+
+func TestGeneratedUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	c := example.Config{Extra: func() {}}
+	c.Hash64(0)
+}
@@ -0,0 +1,102 @@
+// Code generated by xxhashgen; DO NOT EDIT.
+
+package example
+
+import "github.com/pierrec/xxHash/xxhstruct"
+
+// HashXX adds the canonical encoding of x to d.
+func (x *Config) HashXX(d *xxhstruct.Digest) {
+	d.AddField("Endpoint")
+	x.Endpoint.HashXX(d)
+	d.AddField("Name")
+	d.AddString(x.Name)
+	d.AddField("Port")
+	d.AddUint(uint64(x.Port))
+	d.AddField("Enabled")
+	d.AddBool(x.Enabled)
+	d.AddField("Ratio")
+	d.AddFloat(float64(x.Ratio))
+	d.AddField("Mode")
+	d.AddUint(uint64(x.Mode))
+	d.AddField("Tags")
+	d.AddLen(len(x.Tags))
+	for i1 := range x.Tags {
+		d.AddString(string(x.Tags[i1]))
+	}
+	d.AddField("Data")
+	d.AddBytes(x.Data)
+	d.AddField("Blobs")
+	d.AddValue(&x.Blobs)
+	d.AddField("Sum")
+	d.AddLen(len(x.Sum))
+	for i0 := range x.Sum {
+		d.AddUint(uint64(x.Sum[i0]))
+	}
+	d.AddField("Limit")
+	if x.Limit == nil {
+		d.AddNil(true)
+	} else {
+		d.AddNil(false)
+		d.AddInt(int64(*x.Limit))
+	}
+	d.AddField("Backends")
+	d.AddLen(len(x.Backends))
+	for i0 := range x.Backends {
+		if x.Backends[i0] == nil {
+			d.AddNil(true)
+		} else {
+			d.AddNil(false)
+			x.Backends[i0].HashXX(d)
+		}
+	}
+	d.AddField("Matrix")
+	d.AddLen(len(x.Matrix))
+	for i0 := range x.Matrix {
+		d.AddLen(len(x.Matrix[i0]))
+		for i1 := range x.Matrix[i0] {
+			d.AddFloat(x.Matrix[i0][i1])
+		}
+	}
+	d.AddField("Labels")
+	d.AddValue(&x.Labels)
+	d.AddField("Extra")
+	d.AddValue(&x.Extra)
+	d.AddField("Timeout")
+	d.AddValue(&x.Timeout)
+	d.AddField("Other")
+	d.AddInt(int64(x.Renamed))
+	d.AddField("A")
+	d.AddInt(int64(x.A))
+	d.AddField("B")
+	d.AddInt(int64(x.B))
+}
+
+// Hash64 returns the 64bits Hash value of x, equal to xxhstruct.Hash(*x, seed).
+// It panics if a field cannot be hashed.
+func (x *Config) Hash64(seed uint64) uint64 {
+	d := xxhstruct.NewDigest(seed)
+	x.HashXX(d)
+	if err := d.Err(); err != nil {
+		panic(err)
+	}
+	return d.Sum64()
+}
+
+// HashXX adds the canonical encoding of x to d.
+func (x *Endpoint) HashXX(d *xxhstruct.Digest) {
+	d.AddField("Host")
+	d.AddString(x.Host)
+	d.AddField("C")
+	d.AddComplex(complex128(x.C))
+}
+
+// Hash64 returns the 64bits Hash value of x, equal to xxhstruct.Hash(*x, seed).
+// It panics if a field cannot be hashed.
+func (x *Endpoint) Hash64(seed uint64) uint64 {
+	d := xxhstruct.NewDigest(seed)
+	x.HashXX(d)
+	if err := d.Err(); err != nil {
+		panic(err)
+	}
+	return d.Sum64()
+}
@@ -0,0 +1,357 @@
+// Command xxhashgen generates reflection free hashing methods for structs,
+// producing the same hashes as the xxhstruct package.
+// Usage:
+//
+//	xxhashgen [-type T1,T2] [-output file] [dir]
+//
+// where
+//
+//	type: comma separated list of the struct types to generate methods for
+//	      (default: the structs whose documentation contains a //xxhash:generate line)
+//	output: name of the generated file (default: <package>_xxhash.go)
+//	dir: directory of the package (default: current directory)
+//
+// For each struct type T, it generates the methods:
+//
+//	// HashXX adds the canonical encoding of x to d.
+//	func (x *T) HashXX(d *xxhstruct.Digest)
+//	// Hash64 returns the 64bits Hash value of x, equal to xxhstruct.Hash(*x, seed).
+//	func (x *T) Hash64(seed uint64) uint64
+//
+// Fields of basic types, of the other generated structs, and pointers, arrays and slices of them
+// are hashed without reflection. Other fields, such as maps, interfaces or types from other packages,
+// are hashed with xxhstruct.Digest.AddValue, which uses reflection.
+// Unlike xxhstruct.Hash, the generated code does not detect cycles made of pointers
+// to generated structs, so it must not be used on such cyclic data structures.
+//
+// It is meant to be used with go generate:
+//
+//	//go:generate go run github.com/pierrec/xxHash/cmd/xxhashgen -type=Config
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// annotation marks the structs to generate methods for when no type is specified.
+const annotation = "//xxhash:generate"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("xxhashgen: ")
+	types := flag.String("type", "", "comma separated list of struct `types` (default: annotated structs)")
+	output := flag.String("output", "", "output `file` name (default: <package>_xxhash.go)")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	var names []string
+	if *types != "" {
+		names = strings.Split(*types, ",")
+	}
+
+	pkg, src, err := generate(dir, names, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		*output = pkg + "_xxhash.go"
+	}
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the package name and the generated source for the given struct types
+// of the package in dir, ignoring the output file.
+func generate(dir string, names []string, output string) (string, []byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	g := &generator{decls: map[string]*ast.TypeSpec{}}
+	var pkg string
+	for _, file := range files {
+		base := filepath.Base(file)
+		if strings.HasSuffix(base, "_test.go") || base == output || strings.HasSuffix(base, "_xxhash.go") && output == "" {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				g.decls[ts.Name.Name] = ts
+				if names == nil && isAnnotated(gd, ts) {
+					g.types = append(g.types, ts.Name.Name)
+				}
+			}
+		}
+	}
+	if pkg == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	if names != nil {
+		g.types = names
+	}
+	if len(g.types) == 0 {
+		return "", nil, fmt.Errorf("no struct types to generate methods for in %s", dir)
+	}
+	sort.Strings(g.types)
+
+	fmt.Fprintf(&g.buf, "// Code generated by xxhashgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&g.buf, "import \"github.com/pierrec/xxHash/xxhstruct\"\n")
+	for _, name := range g.types {
+		if err := g.genType(name); err != nil {
+			return "", nil, err
+		}
+	}
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid generated code: %v", err)
+	}
+	return pkg, src, nil
+}
+
+// isAnnotated reports whether the type documentation contains the annotation line.
+func isAnnotated(gd *ast.GenDecl, ts *ast.TypeSpec) bool {
+	for _, doc := range []*ast.CommentGroup{gd.Doc, ts.Doc} {
+		if doc == nil {
+			continue
+		}
+		for _, c := range doc.List {
+			if strings.TrimSpace(c.Text) == annotation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type generator struct {
+	buf   bytes.Buffer
+	decls map[string]*ast.TypeSpec // type declarations of the package
+	types []string                 // struct types to generate methods for
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) isGenerated(name string) bool {
+	for _, t := range g.types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) genType(name string) error {
+	ts, ok := g.decls[name]
+	if !ok {
+		return fmt.Errorf("type %s not found", name)
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("type %s is not a struct", name)
+	}
+	if ts.TypeParams != nil {
+		return fmt.Errorf("generic type %s is not supported", name)
+	}
+
+	g.printf("\n// HashXX adds the canonical encoding of x to d.\n")
+	g.printf("func (x *%s) HashXX(d *xxhstruct.Digest) {\n", name)
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			s, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(s).Get("xxhash")
+		}
+		if tag == "-" {
+			continue
+		}
+		fieldNames := f.Names
+		if fieldNames == nil {
+			// Embedded field.
+			fieldNames = []*ast.Ident{ast.NewIdent(embeddedName(f.Type))}
+		}
+		for _, id := range fieldNames {
+			if !id.IsExported() {
+				continue
+			}
+			hashedName := id.Name
+			if tag != "" {
+				hashedName = tag
+			}
+			g.printf("d.AddField(%q)\n", hashedName)
+			if err := g.genValue(f.Type, "x."+id.Name, 0, false); err != nil {
+				return fmt.Errorf("%s.%s: %v", name, id.Name, err)
+			}
+		}
+	}
+	g.printf("}\n")
+
+	g.printf("\n// Hash64 returns the 64bits Hash value of x, equal to xxhstruct.Hash(*x, seed).\n")
+	g.printf("// It panics if a field cannot be hashed.\n")
+	g.printf("func (x *%s) Hash64(seed uint64) uint64 {\n", name)
+	g.printf("d := xxhstruct.NewDigest(seed)\n")
+	g.printf("x.HashXX(d)\n")
+	g.printf("if err := d.Err(); err != nil {\npanic(err)\n}\n")
+	g.printf("return d.Sum64()\n")
+	g.printf("}\n")
+	return nil
+}
+
+// embeddedName returns the field name of an embedded type.
+func embeddedName(t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// basicAdders maps the predeclared types to the Digest method and conversion hashing them.
+var basicAdders = map[string][2]string{
+	"bool":       {"AddBool", "bool"},
+	"int":        {"AddInt", "int64"},
+	"int8":       {"AddInt", "int64"},
+	"int16":      {"AddInt", "int64"},
+	"int32":      {"AddInt", "int64"},
+	"int64":      {"AddInt", "int64"},
+	"rune":       {"AddInt", "int64"},
+	"uint":       {"AddUint", "uint64"},
+	"uint8":      {"AddUint", "uint64"},
+	"uint16":     {"AddUint", "uint64"},
+	"uint32":     {"AddUint", "uint64"},
+	"uint64":     {"AddUint", "uint64"},
+	"uintptr":    {"AddUint", "uint64"},
+	"byte":       {"AddUint", "uint64"},
+	"float32":    {"AddFloat", "float64"},
+	"float64":    {"AddFloat", "float64"},
+	"complex64":  {"AddComplex", "complex128"},
+	"complex128": {"AddComplex", "complex128"},
+	"string":     {"AddString", "string"},
+}
+
+// maxDepth limits the resolution of nested local type declarations.
+const maxDepth = 16
+
+// genValue generates the code adding the value of type t accessed by the expression x.
+// named reports whether t is the underlying type of a named type, requiring conversions.
+func (g *generator) genValue(t ast.Expr, x string, depth int, named bool) error {
+	if depth > maxDepth {
+		g.printf("d.AddValue(%s)\n", addr(x))
+		return nil
+	}
+	switch t := t.(type) {
+	case *ast.ParenExpr:
+		return g.genValue(t.X, x, depth, named)
+	case *ast.Ident:
+		if a, ok := basicAdders[t.Name]; ok {
+			if named || t.Name != a[1] {
+				x = a[1] + "(" + x + ")"
+			}
+			g.printf("d.%s(%s)\n", a[0], x)
+			return nil
+		}
+		ts, ok := g.decls[t.Name]
+		if !ok || ts.TypeParams != nil {
+			g.printf("d.AddValue(%s)\n", addr(x))
+			return nil
+		}
+		if _, ok := ts.Type.(*ast.StructType); ok {
+			if g.isGenerated(t.Name) {
+				// HashXX has a pointer receiver.
+				g.printf("%s.HashXX(d)\n", strings.TrimPrefix(x, "*"))
+			} else {
+				g.printf("d.AddValue(%s)\n", addr(x))
+			}
+			return nil
+		}
+		return g.genValue(ts.Type, x, depth+1, true)
+	case *ast.StarExpr:
+		g.printf("if %s == nil {\nd.AddNil(true)\n} else {\nd.AddNil(false)\n", x)
+		if err := g.genValue(t.X, "*"+x, depth+1, false); err != nil {
+			return err
+		}
+		g.printf("}\n")
+		return nil
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && (id.Name == "byte" || id.Name == "uint8") {
+			g.printf("d.AddBytes(%s)\n", x)
+			return nil
+		}
+		if t.Len == nil && g.isByteKind(t.Elt, depth) {
+			// Slices of named byte types are encoded as raw bytes, let reflection handle them.
+			g.printf("d.AddValue(%s)\n", addr(x))
+			return nil
+		}
+		if strings.HasPrefix(x, "*") {
+			x = "(" + x + ")"
+		}
+		i := fmt.Sprintf("i%d", depth)
+		g.printf("d.AddLen(len(%s))\n", x)
+		g.printf("for %s := range %s {\n", i, x)
+		if err := g.genValue(t.Elt, x+"["+i+"]", depth+1, false); err != nil {
+			return err
+		}
+		g.printf("}\n")
+		return nil
+	case *ast.FuncType, *ast.ChanType:
+		return fmt.Errorf("unsupported type")
+	}
+	// Maps, interfaces and types from other packages.
+	g.printf("d.AddValue(%s)\n", addr(x))
+	return nil
+}
+
+// addr returns the expression of the address of x.
+func addr(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return x[1:]
+	}
+	return "&" + x
+}
+
+// isByteKind reports whether t is a named type whose underlying type is byte.
+func (g *generator) isByteKind(t ast.Expr, depth int) bool {
+	id, ok := t.(*ast.Ident)
+	if !ok || depth > maxDepth {
+		return false
+	}
+	if id.Name == "byte" || id.Name == "uint8" {
+		return true
+	}
+	ts, ok := g.decls[id.Name]
+	return ok && g.isByteKind(ts.Type, depth+1)
+}
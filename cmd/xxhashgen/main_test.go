@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerate checks that the generated code of the example package is up to date.
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	pkg, src, err := generate(dir, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, pkg+"_xxhash.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("generated code is out of date, run go generate in %s", dir)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir := t.TempDir()
+	src := `package p
+
+type S struct {
+	F func()
+}
+
+type I int
+`
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, names := range [][]string{nil, {"S"}, {"I"}, {"Missing"}} {
+		if _, _, err := generate(dir, names, ""); err == nil {
+			t.Errorf("%v: expected an error", names)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package consistent implements key to node assignment with minimal key movement
+// when nodes are added or removed, using xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// Three schemes are provided: a consistent hashing Ring with virtual nodes,
+// Rendezvous (highest random weight) hashing and the jump consistent hash
+// of numbered buckets, which needs no memory.
+// All are deterministic: the same nodes and keys yield the same assignments in every process.
+//
+// Neither Ring nor Rendezvous is safe for concurrent use if nodes are added or removed.
+package consistent
+
+import (
+	"sort"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Ring is a consistent hashing ring.
+//
+// Each node is placed at replicas points on the ring, the i-th one being
+// the xxHash64 Checksum of the node name with seed i.
+// A key is assigned to the node of the first point following its Checksum with a zero seed.
+type Ring struct {
+	replicas int
+	points   []point
+	nodes    map[string]bool
+}
+
+type point struct {
+	hash uint64
+	node string
+}
+
+// NewRing returns an empty Ring placing each node at replicas points.
+// It panics if replicas is not positive.
+func NewRing(replicas int) *Ring {
+	if replicas <= 0 {
+		panic("consistent: invalid number of replicas")
+	}
+	return &Ring{
+		replicas: replicas,
+		nodes:    make(map[string]bool),
+	}
+}
+
+// Add adds nodes to the ring.
+func (r *Ring) Add(nodes ...string) {
+	for _, node := range nodes {
+		if r.nodes[node] {
+			continue
+		}
+		r.nodes[node] = true
+		for i := 0; i < r.replicas; i++ {
+			r.points = append(r.points, point{xxHash64.Checksum([]byte(node), uint64(i)), node})
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		pi, pj := r.points[i], r.points[j]
+		return pi.hash < pj.hash || pi.hash == pj.hash && pi.node < pj.node
+	})
+}
+
+// Remove removes a node from the ring.
+func (r *Ring) Remove(node string) {
+	if !r.nodes[node] {
+		return
+	}
+	delete(r.nodes, node)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if p.node != node {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+}
+
+// Nodes returns the sorted list of nodes in the ring.
+func (r *Ring) Nodes() []string {
+	return sortedNodes(r.nodes)
+}
+
+// Get returns the node assigned to key, or an empty string if the ring is empty.
+func (r *Ring) Get(key []byte) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := xxHash64.Checksum(key, 0)
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].node
+}
+
+// Rendezvous implements highest random weight hashing.
+//
+// The weight of a node for a key is the xxHash64 Checksum of the key
+// seeded with the Checksum of the node name with a zero seed.
+// A key is assigned to the node with the highest weight.
+type Rendezvous struct {
+	nodes map[string]uint64
+}
+
+// NewRendezvous returns a Rendezvous with the given nodes.
+func NewRendezvous(nodes ...string) *Rendezvous {
+	r := &Rendezvous{nodes: make(map[string]uint64)}
+	r.Add(nodes...)
+	return r
+}
+
+// Add adds nodes.
+func (r *Rendezvous) Add(nodes ...string) {
+	for _, node := range nodes {
+		r.nodes[node] = xxHash64.Checksum([]byte(node), 0)
+	}
+}
+
+// Remove removes a node.
+func (r *Rendezvous) Remove(node string) {
+	delete(r.nodes, node)
+}
+
+// Nodes returns the sorted list of nodes.
+func (r *Rendezvous) Nodes() []string {
+	nodes := make(map[string]bool, len(r.nodes))
+	for node := range r.nodes {
+		nodes[node] = true
+	}
+	return sortedNodes(nodes)
+}
+
+// Get returns the node assigned to key, or an empty string if there are no nodes.
+func (r *Rendezvous) Get(key []byte) string {
+	var (
+		best   string
+		weight uint64
+		found  bool
+	)
+	for node, seed := range r.nodes {
+		w := xxHash64.Checksum(key, seed)
+		if !found || w > weight || w == weight && node < best {
+			best, weight, found = node, w, true
+		}
+	}
+	return best
+}
+
+func sortedNodes(nodes map[string]bool) []string {
+	res := make([]string, 0, len(nodes))
+	for node := range nodes {
+		res = append(res, node)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,65 @@
+package consistent_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pierrec/xxHash/consistent"
+)
+
+type getter interface {
+	Get(key []byte) string
+	Add(nodes ...string)
+	Remove(node string)
+	Nodes() []string
+}
+
+func testMovement(t *testing.T, g getter) {
+	if node := g.Get([]byte("key")); node != "" {
+		t.Errorf("got node %q for an empty set", node)
+	}
+	g.Add("a", "b", "c", "d")
+	if nodes := g.Nodes(); !reflect.DeepEqual(nodes, []string{"a", "b", "c", "d"}) {
+		t.Errorf("got nodes %v", nodes)
+	}
+
+	const n = 10000
+	before := make([]string, n)
+	counts := make(map[string]int)
+	for i := range before {
+		before[i] = g.Get([]byte(fmt.Sprint(i)))
+		counts[before[i]]++
+	}
+	for node, c := range counts {
+		if c < n/8 {
+			t.Errorf("node %s only got %d keys", node, c)
+		}
+	}
+
+	g.Remove("b")
+	for i, node := range before {
+		got := g.Get([]byte(fmt.Sprint(i)))
+		if node != "b" && got != node {
+			t.Fatalf("key %d moved from %s to %s", i, node, got)
+		}
+		if got == "b" {
+			t.Fatalf("key %d assigned to removed node", i)
+		}
+	}
+
+	g.Add("b")
+	for i, node := range before {
+		if got := g.Get([]byte(fmt.Sprint(i))); got != node {
+			t.Fatalf("key %d: got node %s expected %s", i, got, node)
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	testMovement(t, consistent.NewRing(100))
+}
+
+func TestRendezvous(t *testing.T) {
+	testMovement(t, consistent.NewRendezvous())
+}
@@ -0,0 +1,30 @@
+package consistent
+
+import "github.com/pierrec/xxHash/xxHash64"
+
+// JumpHash returns the bucket of key among buckets, in [0, buckets),
+// with the jump consistent hash of Lamping and Veach (https://arxiv.org/abs/1406.2294).
+// It panics if buckets is not positive.
+//
+// When the number of buckets grows from n to n+1, only the keys moving
+// to the new bucket change assignment. Buckets can only be added or removed at the end:
+// use Ring or Rendezvous to remove arbitrary nodes.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		panic("consistent: invalid number of buckets")
+	}
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// Jump returns the bucket of key among buckets with JumpHash,
+// key being hashed by its xxHash64 Checksum with a zero seed.
+// It panics if buckets is not positive.
+func Jump(key []byte, buckets int) int {
+	return JumpHash(xxHash64.Checksum(key, 0), buckets)
+}
@@ -0,0 +1,56 @@
+package consistent_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/consistent"
+)
+
+func TestJumpHash(t *testing.T) {
+	// Values of the reference implementation.
+	for _, tc := range []struct {
+		key     uint64
+		buckets int
+		want    int
+	}{
+		{1, 1, 0},
+		{42, 57, 43},
+		{0xDEAD10CC, 1, 0},
+		{0xDEAD10CC, 666, 361},
+		{256, 1024, 520},
+	} {
+		if got := consistent.JumpHash(tc.key, tc.buckets); got != tc.want {
+			t.Errorf("JumpHash(%d, %d) = %d expected %d", tc.key, tc.buckets, got, tc.want)
+		}
+	}
+
+	const keys = 10000
+	for n := 1; n < 20; n++ {
+		counts := make([]int, n+1)
+		for i := 0; i < keys; i++ {
+			key := []byte(fmt.Sprint(i))
+			b := consistent.Jump(key, n)
+			if b < 0 || b >= n {
+				t.Fatalf("n=%d: got bucket %d", n, b)
+			}
+			// Keys only move to the new bucket.
+			if b2 := consistent.Jump(key, n+1); b2 != b && b2 != n {
+				t.Fatalf("n=%d: key %d moved from bucket %d to %d", n, i, b, b2)
+			}
+			counts[b]++
+		}
+		for b, c := range counts[:n] {
+			if want := keys / n; c < want*8/10 || c > want*12/10 {
+				t.Errorf("n=%d: bucket %d got %d keys expected about %d", n, b, c, want)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	consistent.JumpHash(1, 0)
+}
@@ -0,0 +1,185 @@
+// Package cuckoo implements a cuckoo filter (https://www.cs.cmu.edu/~dga/papers/cuckoo-conext2014.pdf),
+// a membership test structure supporting deletions, based on xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// The xxHash64 Checksum of a key with a zero seed provides its first bucket (low bits)
+// and its fingerprint (high bits). The alternate bucket is the first one xor'ed
+// with the Checksum of the fingerprint, encoded as 4 little endian bytes.
+package cuckoo
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+const (
+	// bucketSize is the number of fingerprints per bucket.
+	bucketSize = 4
+	// maxKicks is the maximum number of relocations when inserting.
+	maxKicks = 500
+)
+
+// Filter is a cuckoo filter.
+type Filter struct {
+	fpBits  uint
+	mask    uint64 // bucket index mask
+	buckets [][bucketSize]uint32
+	count   int
+	rnd     uint64
+	// victim holds the fingerprint evicted by a failed insertion.
+	victim struct {
+		fp    uint32
+		index uint64
+		used  bool
+	}
+}
+
+// New returns a Filter able to hold about capacity keys with fingerprints of fpBits bits.
+// The false positive rate is about 8/2^fpBits.
+// It panics if fpBits is not within [1, 32].
+func New(capacity int, fpBits int) *Filter {
+	if fpBits < 1 || fpBits > 32 {
+		panic("cuckoo: invalid fingerprint size")
+	}
+	n := (capacity + bucketSize - 1) / bucketSize
+	if n < 1 {
+		n = 1
+	}
+	n = 1 << bits.Len(uint(n-1))
+	return &Filter{
+		fpBits:  uint(fpBits),
+		mask:    uint64(n - 1),
+		buckets: make([][bucketSize]uint32, n),
+		rnd:     uint64(n),
+	}
+}
+
+// Count returns the number of keys in the filter.
+func (f *Filter) Count() int {
+	return f.count
+}
+
+// Insert adds key to the filter.
+// It returns false if the filter is full.
+func (f *Filter) Insert(key []byte) bool {
+	if f.victim.used {
+		return false
+	}
+	i1, fp := f.hash(key)
+	if f.insert(i1, fp) || f.insert(f.alt(i1, fp), fp) {
+		f.count++
+		return true
+	}
+
+	i := i1
+	if f.next()&1 == 1 {
+		i = f.alt(i1, fp)
+	}
+	for k := 0; k < maxKicks; k++ {
+		j := f.next() % bucketSize
+		fp, f.buckets[i][j] = f.buckets[i][j], fp
+		i = f.alt(i, fp)
+		if f.insert(i, fp) {
+			f.count++
+			return true
+		}
+	}
+	// The key was inserted but another one was evicted: keep it aside.
+	f.victim.fp = fp
+	f.victim.index = i
+	f.victim.used = true
+	f.count++
+	return true
+}
+
+// Lookup reports whether key may be in the filter.
+// False positives are possible, false negatives are not.
+func (f *Filter) Lookup(key []byte) bool {
+	i1, fp := f.hash(key)
+	i2 := f.alt(i1, fp)
+	if f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2) {
+		return true
+	}
+	return f.find(i1, fp) >= 0 || f.find(i2, fp) >= 0
+}
+
+// Delete removes key from the filter and reports whether it was found.
+// Only keys that were inserted must be deleted.
+func (f *Filter) Delete(key []byte) bool {
+	i1, fp := f.hash(key)
+	i2 := f.alt(i1, fp)
+	for _, i := range [2]uint64{i1, i2} {
+		if j := f.find(i, fp); j >= 0 {
+			f.buckets[i][j] = 0
+			f.count--
+			f.reinsertVictim()
+			return true
+		}
+	}
+	if f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2) {
+		f.victim.used = false
+		f.count--
+		return true
+	}
+	return false
+}
+
+// reinsertVictim tries to move the victim back into the buckets.
+func (f *Filter) reinsertVictim() {
+	if !f.victim.used {
+		return
+	}
+	v := f.victim
+	if f.insert(v.index, v.fp) || f.insert(f.alt(v.index, v.fp), v.fp) {
+		f.victim.used = false
+	}
+}
+
+// hash returns the first bucket and the fingerprint of key.
+func (f *Filter) hash(key []byte) (uint64, uint32) {
+	h := xxHash64.Checksum(key, 0)
+	fp := uint32(h >> (64 - f.fpBits))
+	if fp == 0 {
+		// Zero marks empty slots.
+		fp = 1
+	}
+	return h & f.mask, fp
+}
+
+// alt returns the alternate bucket of fingerprint fp in bucket i.
+func (f *Filter) alt(i uint64, fp uint32) uint64 {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], fp)
+	return (i ^ xxHash64.Checksum(b[:], 0)) & f.mask
+}
+
+// insert stores fp in the first empty slot of bucket i.
+func (f *Filter) insert(i uint64, fp uint32) bool {
+	b := &f.buckets[i]
+	for j, v := range b {
+		if v == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the slot of fp in bucket i or -1.
+func (f *Filter) find(i uint64, fp uint32) int {
+	for j, v := range f.buckets[i] {
+		if v == fp {
+			return j
+		}
+	}
+	return -1
+}
+
+// next returns the next value of the xorshift generator used to pick eviction victims.
+func (f *Filter) next() uint64 {
+	f.rnd ^= f.rnd << 13
+	f.rnd ^= f.rnd >> 7
+	f.rnd ^= f.rnd << 17
+	return f.rnd
+}
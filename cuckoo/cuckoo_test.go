@@ -0,0 +1,72 @@
+package cuckoo_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pierrec/xxHash/cuckoo"
+)
+
+func key(i int) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(i))
+	return b[:]
+}
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	f := cuckoo.New(n, 16)
+	for i := 0; i < n; i++ {
+		if !f.Insert(key(i)) {
+			t.Fatalf("filter full after %d keys", i)
+		}
+	}
+	if c := f.Count(); c != n {
+		t.Errorf("got count %d expected %d", c, n)
+	}
+	for i := 0; i < n; i++ {
+		if !f.Lookup(key(i)) {
+			t.Fatalf("key %d not found", i)
+		}
+	}
+	var fp int
+	for i := n; i < 11*n; i++ {
+		if f.Lookup(key(i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / (10 * n); rate > 0.001 {
+		t.Errorf("false positive rate %f too high", rate)
+	}
+
+	for i := 0; i < n; i += 2 {
+		if !f.Delete(key(i)) {
+			t.Fatalf("key %d not deleted", i)
+		}
+	}
+	if c := f.Count(); c != n/2 {
+		t.Errorf("got count %d expected %d", c, n/2)
+	}
+	for i := 1; i < n; i += 2 {
+		if !f.Lookup(key(i)) {
+			t.Fatalf("key %d not found after deletions", i)
+		}
+	}
+}
+
+func TestFull(t *testing.T) {
+	f := cuckoo.New(64, 8)
+	var i int
+	for f.Insert(key(i)) {
+		i++
+		if i > 1000 {
+			t.Fatal("filter never full")
+		}
+	}
+	// All inserted keys must still be found.
+	for j := 0; j < i; j++ {
+		if !f.Lookup(key(j)) {
+			t.Fatalf("key %d not found", j)
+		}
+	}
+}
@@ -1 +1,3 @@
 module github.com/pierrec/xxHash
+
+go 1.22
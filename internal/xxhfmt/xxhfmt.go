@@ -0,0 +1,50 @@
+// Package xxhfmt implements the fmt.Formatter interface of the hash value types of this module.
+package xxhfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format prints the hash value v to f: hex, its canonical hexadecimal form,
+// with the %v, %s, %x, %X and %q verbs, the # flag adding the 0x prefix to %v, %x and %X,
+// and its decimal value with %d. name identifies the hash for the other verbs.
+func Format(f fmt.State, verb rune, name, hex string, v uint64) {
+	var s string
+	switch verb {
+	case 'v', 's', 'x', 'X', 'q':
+		s = hex
+		if verb == 'X' {
+			s = strings.ToUpper(s)
+		}
+		if f.Flag('#') && verb != 'q' && verb != 's' {
+			s = "0x" + s
+		}
+		if verb == 'q' {
+			s = strconv.Quote(s)
+		}
+	case 'd':
+		s = strconv.FormatUint(v, 10)
+	default:
+		fmt.Fprintf(f, "%%!%c(%s=%s)", verb, name, hex)
+		return
+	}
+	pad(f, s)
+}
+
+// pad writes s to f, padded with spaces up to the width of f.
+func pad(f fmt.State, s string) {
+	w, ok := f.Width()
+	if !ok || w <= len(s) {
+		f.Write([]byte(s))
+		return
+	}
+	sp := strings.Repeat(" ", w-len(s))
+	if f.Flag('-') {
+		s += sp
+	} else {
+		s = sp + s
+	}
+	f.Write([]byte(s))
+}
@@ -0,0 +1,11 @@
+// Package xxhref binds the reference C implementation of xxHash (https://github.com/Cyan4973/xxHash/)
+// to check this module against it.
+//
+// The binding requires cgo and the libxxhash library and headers,
+// and is only built with the xxhref build tag:
+//
+//	go test -tags xxhref ./internal/xxhref
+//	go test -tags xxhref -fuzz FuzzXXH64 ./internal/xxhref
+//
+// CGO_CFLAGS and CGO_LDFLAGS locate the library if it is not installed in a standard location.
+package xxhref
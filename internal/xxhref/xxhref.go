@@ -0,0 +1,28 @@
+//go:build cgo && xxhref
+
+package xxhref
+
+/*
+#cgo LDFLAGS: -lxxhash
+#include <xxhash.h>
+*/
+import "C"
+
+import "unsafe"
+
+// XXH32 returns the 32bits Hash value of data computed by the reference implementation.
+func XXH32(data []byte, seed uint32) uint32 {
+	return uint32(C.XXH32(ptr(data), C.size_t(len(data)), C.XXH32_hash_t(seed)))
+}
+
+// XXH64 returns the 64bits Hash value of data computed by the reference implementation.
+func XXH64(data []byte, seed uint64) uint64 {
+	return uint64(C.XXH64(ptr(data), C.size_t(len(data)), C.XXH64_hash_t(seed)))
+}
+
+func ptr(data []byte) unsafe.Pointer {
+	if len(data) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&data[0])
+}
@@ -0,0 +1,94 @@
+//go:build cgo && xxhref
+
+package xxhref_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/internal/xxhref"
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var seeds = []uint64{0, 1, 0xCAFE, testvectors.Prime32, testvectors.Prime64, math.MaxUint32, math.MaxUint64}
+
+// sizes returns the sizes to check: all the ones up to 300 to cover every tail length, then larger ones.
+func sizes() []int {
+	var s []int
+	for n := 0; n <= 300; n++ {
+		s = append(s, n)
+	}
+	return append(s, 1000, 1024, 4096, 65537, 1<<20+3)
+}
+
+func TestXXH32(t *testing.T) {
+	data := testvectors.Input(1<<20 + 3)
+	for _, seed := range seeds {
+		seed := uint32(seed)
+		for _, n := range sizes() {
+			want := xxhref.XXH32(data[:n], seed)
+			if h := xxHash32.Checksum(data[:n], seed); h != want {
+				t.Errorf("size %d seed 0x%x: got 0x%08x expected 0x%08x", n, seed, h, want)
+			}
+			xxh := xxHash32.New(seed)
+			xxh.Write(data[:n/3])
+			xxh.Write(data[n/3 : n])
+			if h := xxh.Sum32(); h != want {
+				t.Errorf("size %d seed 0x%x: got 0x%08x expected 0x%08x when streaming", n, seed, h, want)
+			}
+		}
+	}
+}
+
+func TestXXH64(t *testing.T) {
+	data := testvectors.Input(1<<20 + 3)
+	for _, seed := range seeds {
+		for _, n := range sizes() {
+			want := xxhref.XXH64(data[:n], seed)
+			if h := xxHash64.Checksum(data[:n], seed); h != want {
+				t.Errorf("size %d seed 0x%x: got 0x%016x expected 0x%016x", n, seed, h, want)
+			}
+			xxh := xxHash64.New(seed)
+			xxh.Write(data[:n/3])
+			xxh.Write(data[n/3 : n])
+			if h := xxh.Sum64(); h != want {
+				t.Errorf("size %d seed 0x%x: got 0x%016x expected 0x%016x when streaming", n, seed, h, want)
+			}
+		}
+	}
+}
+
+func TestTestVectors(t *testing.T) {
+	for _, v := range testvectors.XXH32 {
+		if h := xxhref.XXH32(testvectors.Input(v.Len), v.Seed); h != v.Sum {
+			t.Errorf("XXH32 len %d seed 0x%x: got 0x%08x expected 0x%08x", v.Len, v.Seed, h, v.Sum)
+		}
+	}
+	for _, v := range testvectors.XXH64 {
+		if h := xxhref.XXH64(testvectors.Input(v.Len), v.Seed); h != v.Sum {
+			t.Errorf("XXH64 len %d seed 0x%x: got 0x%016x expected 0x%016x", v.Len, v.Seed, h, v.Sum)
+		}
+	}
+}
+
+func FuzzXXH32(f *testing.F) {
+	f.Add([]byte("abc"), uint32(0))
+	f.Add(testvectors.Input(100), uint32(testvectors.Prime32))
+	f.Fuzz(func(t *testing.T, data []byte, seed uint32) {
+		if h, want := xxHash32.Checksum(data, seed), xxhref.XXH32(data, seed); h != want {
+			t.Errorf("got 0x%08x expected 0x%08x", h, want)
+		}
+	})
+}
+
+func FuzzXXH64(f *testing.F) {
+	f.Add([]byte("abc"), uint64(0))
+	f.Add(testvectors.Input(100), uint64(testvectors.Prime64))
+	f.Fuzz(func(t *testing.T, data []byte, seed uint64) {
+		if h, want := xxHash64.Checksum(data, seed), xxhref.XXH64(data, seed); h != want {
+			t.Errorf("got 0x%016x expected 0x%016x", h, want)
+		}
+	})
+}
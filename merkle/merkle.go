@@ -0,0 +1,196 @@
+// Package merkle builds hash trees over data using xxHash64 (https://github.com/Cyan4973/xxHash/),
+// allowing piecewise verification of the data.
+//
+// The leaves are the Checksum of each leaf size bytes piece of the data, the last one being possibly shorter.
+// Each parent node is the Checksum of up to fanout of its children hashes, encoded in order as 8 little endian bytes.
+// All hashes use the same seed.
+//
+// With a leaf size of xxHash64.ParallelChunkSize and a fanout greater than the number of leaves,
+// the root is the xxHash64.ParallelChecksum of the data.
+package merkle
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// ErrIndex is returned when requesting the proof of a leaf that does not exist.
+var ErrIndex = errors.New("merkle: leaf index out of range")
+
+// Tree is a hash tree.
+type Tree struct {
+	fanout int
+	seed   uint64
+	// levels[0] holds the leaves, the last level holds the root.
+	levels [][]uint64
+}
+
+// Build reads r until io.EOF and returns the hash tree of its content.
+// It panics if leafSize is not positive or fanout is lower than 2.
+func Build(r io.Reader, leafSize, fanout int, seed uint64) (*Tree, error) {
+	return BuildContext(context.Background(), r, leafSize, fanout, seed)
+}
+
+// BuildContext is like Build but returns ctx.Err() if ctx is done
+// before io.EOF is reached. ctx is checked before every read.
+func BuildContext(ctx context.Context, r io.Reader, leafSize, fanout int, seed uint64) (*Tree, error) {
+	t, _, err := build(ctx, r, leafSize, fanout, seed)
+	return t, err
+}
+
+// BuildReaderAt returns the hash tree of the first size bytes of r.
+func BuildReaderAt(r io.ReaderAt, size int64, leafSize, fanout int, seed uint64) (*Tree, error) {
+	return BuildReaderAtContext(context.Background(), r, size, leafSize, fanout, seed)
+}
+
+// BuildReaderAtContext is like BuildReaderAt but returns ctx.Err() if ctx is done
+// before size bytes are read. ctx is checked before every read.
+func BuildReaderAtContext(ctx context.Context, r io.ReaderAt, size int64, leafSize, fanout int, seed uint64) (*Tree, error) {
+	t, n, err := build(ctx, io.NewSectionReader(r, 0, size), leafSize, fanout, seed)
+	if err == nil && n < size {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func build(ctx context.Context, r io.Reader, leafSize, fanout int, seed uint64) (*Tree, int64, error) {
+	if leafSize <= 0 {
+		panic("merkle: invalid leaf size")
+	}
+	if fanout < 2 {
+		panic("merkle: invalid fanout")
+	}
+	c := xxHash64.NewChunked(seed, leafSize)
+	if ctx.Done() != nil {
+		r = &ctxReader{ctx, r}
+	}
+	n, err := io.Copy(c, r)
+	if err != nil {
+		return nil, n, err
+	}
+	leaves := c.Chunks()
+	if len(leaves) == 0 {
+		leaves = append(leaves, c.Sum64())
+	}
+
+	t := &Tree{
+		fanout: fanout,
+		seed:   seed,
+		levels: [][]uint64{leaves},
+	}
+	for level := leaves; len(level) > 1; {
+		parents := make([]uint64, (len(level)+fanout-1)/fanout)
+		for i := range parents {
+			parents[i] = node(group(level, i, fanout), seed)
+		}
+		t.levels = append(t.levels, parents)
+		level = parents
+	}
+	return t, n, nil
+}
+
+// ctxReader checks ctx before every read from r.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() uint64 {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// NumLeaves returns the number of leaves in the tree.
+func (t *Tree) NumLeaves() int {
+	return len(t.levels[0])
+}
+
+// Leaf returns the hash of the i-th leaf.
+func (t *Tree) Leaf(i int) uint64 {
+	return t.levels[0][i]
+}
+
+// Proof returns the proof that the i-th leaf belongs to the tree.
+func (t *Tree) Proof(i int) (*Proof, error) {
+	if i < 0 || i >= t.NumLeaves() {
+		return nil, ErrIndex
+	}
+	p := &Proof{
+		Index:  i,
+		Fanout: t.fanout,
+		Seed:   t.seed,
+	}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		g := group(level, i/t.fanout, t.fanout)
+		p.Groups = append(p.Groups, append([]uint64(nil), g...))
+		i /= t.fanout
+	}
+	return p, nil
+}
+
+// Proof is the list of hashes required to recompute the root of a tree from one of its leaves.
+type Proof struct {
+	// Index of the leaf in the tree.
+	Index int
+	// Fanout of the tree.
+	Fanout int
+	// Seed used by the tree.
+	Seed uint64
+	// Groups holds, from the leaves up, the hashes of the siblings
+	// of the node on the path to the root, including the node itself.
+	Groups [][]uint64
+}
+
+// Verify reports whether the leaf with the given hash is at the proof index in the tree with the given root.
+func (p *Proof) Verify(leaf, root uint64) bool {
+	if p.Fanout < 2 {
+		return false
+	}
+	h, i := leaf, p.Index
+	for _, g := range p.Groups {
+		pos := i % p.Fanout
+		if pos >= len(g) || g[pos] != h {
+			return false
+		}
+		h = node(g, p.Seed)
+		i /= p.Fanout
+	}
+	return i == 0 && h == root
+}
+
+// VerifyData reports whether data is the content of the leaf at the proof index in the tree with the given root.
+func (p *Proof) VerifyData(data []byte, root uint64) bool {
+	return p.Verify(xxHash64.Checksum(data, p.Seed), root)
+}
+
+// group returns the i-th group of fanout hashes in level.
+func group(level []uint64, i, fanout int) []uint64 {
+	p := i * fanout
+	if len(level)-p < fanout {
+		return level[p:]
+	}
+	return level[p : p+fanout]
+}
+
+// node returns the hash of the parent of children.
+func node(children []uint64, seed uint64) uint64 {
+	buf := make([]byte, 8*len(children))
+	for i, h := range children {
+		binary.LittleEndian.PutUint64(buf[8*i:], h)
+	}
+	return xxHash64.Checksum(buf, seed)
+}
@@ -0,0 +1,115 @@
+package merkle_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/merkle"
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestEmpty(t *testing.T) {
+	tree, err := merkle.Build(bytes.NewReader(nil), 16, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tree.Root(), xxHash64.Checksum(nil, 0); got != want {
+		t.Errorf("got root 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	const leafSize, seed = 16, 0xCAFE
+	for _, n := range []int{1, 16, 17, 100, 1000} {
+		for _, fanout := range []int{2, 3, 16} {
+			data := testvectors.Input(n)
+			tree, err := merkle.Build(bytes.NewReader(data), leafSize, fanout, seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := tree.NumLeaves(), (n+leafSize-1)/leafSize; got != want {
+				t.Fatalf("got %d leaves expected %d", got, want)
+			}
+			root := tree.Root()
+			for i := 0; i < tree.NumLeaves(); i++ {
+				p, err := tree.Proof(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				leaf := data[i*leafSize:]
+				if len(leaf) > leafSize {
+					leaf = leaf[:leafSize]
+				}
+				if !p.VerifyData(leaf, root) {
+					t.Errorf("size %d fanout %d: leaf %d does not verify", n, fanout, i)
+				}
+				if p.VerifyData(append([]byte{0}, leaf...), root) {
+					t.Errorf("size %d fanout %d: altered leaf %d verifies", n, fanout, i)
+				}
+			}
+			if _, err := tree.Proof(tree.NumLeaves()); err != merkle.ErrIndex {
+				t.Errorf("got error %v expected %v", err, merkle.ErrIndex)
+			}
+		}
+	}
+}
+
+func TestParallelChecksum(t *testing.T) {
+	data := testvectors.Input(3*xxHash64.ParallelChunkSize + 1)
+	tree, err := merkle.BuildReaderAt(bytes.NewReader(data), int64(len(data)), xxHash64.ParallelChunkSize, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tree.Root(), xxHash64.ParallelChecksum(data, 0, 0); got != want {
+		t.Errorf("got root 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestBuildReaderAtShort(t *testing.T) {
+	data := testvectors.Input(100)
+	_, err := merkle.BuildReaderAt(bytes.NewReader(data), 101, 16, 2, 0)
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("got error %v expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+// cancelReader cancels its context after a number of reads.
+type cancelReader struct {
+	r      io.Reader
+	reads  int
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	if r.reads--; r.reads == 0 {
+		r.cancel()
+	}
+	return r.r.Read(p)
+}
+
+func TestBuildContext(t *testing.T) {
+	data := testvectors.Input(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &cancelReader{iotest.HalfReader(bytes.NewReader(data)), 2, cancel}
+	if _, err := merkle.BuildContext(ctx, r, 1024, 4, 0); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+	if _, err := merkle.BuildReaderAtContext(ctx, bytes.NewReader(data), int64(len(data)), 1024, 4, 0); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+
+	want, err := merkle.Build(bytes.NewReader(data), 1024, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	tree, err := merkle.BuildReaderAtContext(ctx, bytes.NewReader(data), int64(len(data)), 1024, 4, 0)
+	if err != nil || tree.Root() != want.Root() {
+		t.Errorf("got %v expected root 0x%x", err, want.Root())
+	}
+}
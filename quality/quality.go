@@ -0,0 +1,306 @@
+// Package quality measures the statistical quality of hash functions,
+// in the spirit of the SMHasher test suite (https://github.com/rurban/smhasher/),
+// so that seeds and variants can be evaluated against a given key distribution
+// without external tooling.
+//
+// Avalanche and BitIndependence measure how output bits react to flipping input bits,
+// Distribution how keys spread over buckets.
+// Results are statistics, not verdicts: compare them between hashes and with the
+// values expected from a random function, given as guidance in each result.
+package quality
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Hash is a hash function under test.
+type Hash struct {
+	// Name identifies the hash in reports.
+	Name string
+	// Bits is the number of significant low bits of the values returned by Func, between 1 and 64.
+	Bits int
+	// Func hashes data.
+	Func func(data []byte) uint64
+}
+
+// XXH32 returns the xxHash32 Hash with the given seed.
+func XXH32(seed uint32) Hash {
+	return Hash{
+		Name: "XXH32",
+		Bits: 32,
+		Func: func(data []byte) uint64 { return uint64(xxHash32.Checksum(data, seed)) },
+	}
+}
+
+// XXH64 returns the xxHash64 Hash with the given seed.
+func XXH64(seed uint64) Hash {
+	return Hash{
+		Name: "XXH64",
+		Bits: 64,
+		Func: func(data []byte) uint64 { return xxHash64.Checksum(data, seed) },
+	}
+}
+
+func (h Hash) check() {
+	if h.Bits < 1 || h.Bits > 64 || h.Func == nil {
+		panic("quality: invalid hash")
+	}
+}
+
+func checkSamples(keyLen, trials int) {
+	if keyLen < 1 || trials < 2 {
+		panic("quality: invalid key length or number of trials")
+	}
+}
+
+// AvalancheResult holds the result of the avalanche test.
+//
+// The bias of a pair of input and output bits is |2p-1|, p being the probability that flipping
+// the input bit flips the output bit. It is 0 for an ideal hash and 1 for a fully biased one.
+// For a random function, it is about 1/sqrt(trials) on average, and the maximum
+// over all pairs rarely exceeds 5/sqrt(trials).
+type AvalancheResult struct {
+	// Matrix holds the flip probability of every output bit when flipping every input bit,
+	// indexed by input bit then output bit.
+	Matrix [][]float64
+	// MaxBias and MeanBias are the maximum and mean bias over all pairs of input and output bits.
+	MaxBias, MeanBias float64
+}
+
+// Avalanche runs the strict avalanche test over trials random keys of keyLen bytes,
+// generated from seed.
+func Avalanche(h Hash, keyLen, trials int, seed int64) AvalancheResult {
+	h.check()
+	checkSamples(keyLen, trials)
+	rnd := rand.New(rand.NewSource(seed))
+	key := make([]byte, keyLen)
+	counts := make([][]int, 8*keyLen)
+	for i := range counts {
+		counts[i] = make([]int, h.Bits)
+	}
+	for t := 0; t < trials; t++ {
+		rnd.Read(key)
+		h0 := h.Func(key)
+		for i, c := range counts {
+			d := flip(h, key, i, h0)
+			for j := range c {
+				c[j] += int(d >> uint(j) & 1)
+			}
+		}
+	}
+
+	var res AvalancheResult
+	res.Matrix = make([][]float64, len(counts))
+	var sum float64
+	for i, c := range counts {
+		res.Matrix[i] = make([]float64, len(c))
+		for j, n := range c {
+			p := float64(n) / float64(trials)
+			res.Matrix[i][j] = p
+			b := math.Abs(2*p - 1)
+			sum += b
+			if b > res.MaxBias {
+				res.MaxBias = b
+			}
+		}
+	}
+	res.MeanBias = sum / float64(len(counts)*h.Bits)
+	return res
+}
+
+// flip returns the output bits that change when flipping the input bit i of key,
+// whose hash is h0. key is left unchanged.
+func flip(h Hash, key []byte, i int, h0 uint64) uint64 {
+	key[i/8] ^= 1 << uint(i%8)
+	d := (h.Func(key) ^ h0) & mask(h.Bits)
+	key[i/8] ^= 1 << uint(i%8)
+	return d
+}
+
+func mask(bits int) uint64 {
+	return math.MaxUint64 >> uint(64-bits)
+}
+
+// BICResult holds the result of the bit independence criterion test.
+//
+// The correlation of a pair of output bits is the absolute Pearson correlation of their flips
+// when flipping an input bit. It is 0 for an ideal hash and 1 for fully dependent bits.
+// For a random function, it is about 1/sqrt(trials) on average.
+type BICResult struct {
+	// MaxCorrelation and MeanCorrelation are the maximum and mean correlation
+	// over all input bits and pairs of output bits.
+	MaxCorrelation, MeanCorrelation float64
+	// InputBit and OutputBits locate the maximum correlation.
+	InputBit   int
+	OutputBits [2]int
+}
+
+// BitIndependence runs the bit independence criterion test over trials random keys of keyLen bytes,
+// generated from seed.
+// Its cost grows with the square of the number of output bits.
+func BitIndependence(h Hash, keyLen, trials int, seed int64) BICResult {
+	h.check()
+	checkSamples(keyLen, trials)
+	rnd := rand.New(rand.NewSource(seed))
+	keys := make([][]byte, trials)
+	hashes := make([]uint64, trials)
+	for t := range keys {
+		keys[t] = make([]byte, keyLen)
+		rnd.Read(keys[t])
+		hashes[t] = h.Func(keys[t])
+	}
+
+	var res BICResult
+	var sum float64
+	var pairs int
+	ones := make([]int, h.Bits)
+	both := make([]int, h.Bits*h.Bits)
+	n := float64(trials)
+	for i := 0; i < 8*keyLen; i++ {
+		for j := range ones {
+			ones[j] = 0
+		}
+		for j := range both {
+			both[j] = 0
+		}
+		for t, key := range keys {
+			d := flip(h, key, i, hashes[t])
+			for j := 0; j < h.Bits; j++ {
+				if d>>uint(j)&1 == 0 {
+					continue
+				}
+				ones[j]++
+				for k := j + 1; k < h.Bits; k++ {
+					both[j*h.Bits+k] += int(d >> uint(k) & 1)
+				}
+			}
+		}
+		for j := 0; j < h.Bits; j++ {
+			for k := j + 1; k < h.Bits; k++ {
+				pj, pk := float64(ones[j])/n, float64(ones[k])/n
+				cov := float64(both[j*h.Bits+k])/n - pj*pk
+				v := pj * (1 - pj) * pk * (1 - pk)
+				// Bits that always or never flip are reported as fully dependent.
+				c := 1.0
+				if v > 0 {
+					c = math.Abs(cov) / math.Sqrt(v)
+				}
+				sum += c
+				pairs++
+				if c > res.MaxCorrelation {
+					res.MaxCorrelation = c
+					res.InputBit = i
+					res.OutputBits = [2]int{j, k}
+				}
+			}
+		}
+	}
+	if pairs > 0 {
+		res.MeanCorrelation = sum / float64(pairs)
+	}
+	return res
+}
+
+// DistributionResult holds the result of the distribution test.
+type DistributionResult struct {
+	// Keys and Buckets are the number of keys and buckets.
+	Keys, Buckets int
+	// ChiSquare is the chi-squared statistic of the bucket loads against a uniform distribution.
+	ChiSquare float64
+	// Score is ChiSquare normalized to a standard normal variable for a random function:
+	// values beyond a few units in either direction indicate a non uniform distribution.
+	Score float64
+	// MaxLoad is the number of keys in the fullest bucket.
+	MaxLoad int
+	// Skew is MaxLoad divided by the expected load of a bucket.
+	Skew float64
+	// Collisions is the number of distinct keys whose full hash value equals the one of
+	// a previous distinct key. Repeated keys are not collisions.
+	Collisions int
+}
+
+// Distribution hashes keys into buckets by taking their hash values modulo buckets
+// and measures how uniformly they are spread.
+// It panics if there are no keys or less than 2 buckets.
+func Distribution(h Hash, keys [][]byte, buckets int) DistributionResult {
+	h.check()
+	checkDistribution(keys, buckets)
+	hashes := hashKeys(h, keys)
+	res := distribution(hashes, buckets)
+	res.Collisions = collisions(keys, hashes)
+	return res
+}
+
+func checkDistribution(keys [][]byte, buckets int) {
+	if len(keys) == 0 || buckets < 2 {
+		panic("quality: invalid number of keys or buckets")
+	}
+}
+
+// hashKeys returns the significant bits of the hash values of keys.
+func hashKeys(h Hash, keys [][]byte) []uint64 {
+	hashes := make([]uint64, len(keys))
+	for i, key := range keys {
+		hashes[i] = h.Func(key) & mask(h.Bits)
+	}
+	return hashes
+}
+
+// distribution returns the distribution of hashes in buckets, without the collisions.
+func distribution(hashes []uint64, buckets int) DistributionResult {
+	loads := make([]int, buckets)
+	res := DistributionResult{Keys: len(hashes), Buckets: buckets}
+	for _, v := range hashes {
+		loads[v%uint64(buckets)]++
+	}
+	expected := float64(len(hashes)) / float64(buckets)
+	for _, n := range loads {
+		d := float64(n) - expected
+		res.ChiSquare += d * d / expected
+		if n > res.MaxLoad {
+			res.MaxLoad = n
+		}
+	}
+	res.Skew = float64(res.MaxLoad) / expected
+	df := float64(buckets - 1)
+	res.Score = (res.ChiSquare - df) / math.Sqrt(2*df)
+	return res
+}
+
+// collisions returns the number of distinct keys whose hash value equals the one of a previous distinct key.
+func collisions(keys [][]byte, hashes []uint64) int {
+	first := make(map[uint64][]byte, len(keys))
+	// colliding holds the keys colliding with the first key of their hash value,
+	// so that their repetitions are not counted again.
+	colliding := make(map[string]struct{})
+	for i, key := range keys {
+		k, ok := first[hashes[i]]
+		switch {
+		case !ok:
+			first[hashes[i]] = key
+		case bytes.Equal(k, key):
+		default:
+			colliding[string(key)] = struct{}{}
+		}
+	}
+	return len(colliding)
+}
+
+// SequentialKeys returns n keys made of the little endian encoding of the integers from 0 to n-1
+// on size bytes, a typical worst case for weak hashes.
+func SequentialKeys(n, size int) [][]byte {
+	keys := make([][]byte, n)
+	for i := range keys {
+		k := make([]byte, size)
+		for j, v := 0, i; j < size && v > 0; j, v = j+1, v>>8 {
+			k[j] = byte(v)
+		}
+		keys[i] = k
+	}
+	return keys
+}
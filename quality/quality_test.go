@@ -0,0 +1,94 @@
+package quality_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/quality"
+)
+
+// weak is a poor hash summing its input bytes.
+var weak = quality.Hash{
+	Name: "sum",
+	Bits: 32,
+	Func: func(data []byte) uint64 {
+		var s uint64
+		for _, b := range data {
+			s = s*31 + uint64(b)
+		}
+		return s & math.MaxUint32
+	},
+}
+
+func TestAvalanche(t *testing.T) {
+	const trials = 2000
+	for _, h := range []quality.Hash{quality.XXH32(0), quality.XXH64(0), quality.XXH64(0xCAFE)} {
+		res := quality.Avalanche(h, 8, trials, 1)
+		if len(res.Matrix) != 64 || len(res.Matrix[0]) != h.Bits {
+			t.Fatalf("%s: invalid matrix size %dx%d", h.Name, len(res.Matrix), len(res.Matrix[0]))
+		}
+		if max := 5 / math.Sqrt(trials); res.MaxBias > max || res.MeanBias > max/3 {
+			t.Errorf("%s: got bias max %.3f mean %.3f", h.Name, res.MaxBias, res.MeanBias)
+		}
+	}
+	if res := quality.Avalanche(weak, 8, trials, 1); res.MaxBias < 0.9 {
+		t.Errorf("%s: got max bias %.3f", weak.Name, res.MaxBias)
+	}
+}
+
+func TestBitIndependence(t *testing.T) {
+	const trials = 1000
+	for _, h := range []quality.Hash{quality.XXH32(0), quality.XXH64(1)} {
+		res := quality.BitIndependence(h, 4, trials, 1)
+		if max := 6 / math.Sqrt(trials); res.MaxCorrelation > max || res.MeanCorrelation > max/3 {
+			t.Errorf("%s: got correlation max %.3f mean %.3f at %d/%v", h.Name, res.MaxCorrelation, res.MeanCorrelation, res.InputBit, res.OutputBits)
+		}
+	}
+	if res := quality.BitIndependence(weak, 4, trials, 1); res.MaxCorrelation < 0.9 {
+		t.Errorf("%s: got max correlation %.3f", weak.Name, res.MaxCorrelation)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	keys := quality.SequentialKeys(100000, 8)
+	if got := binary.LittleEndian.Uint64(keys[12345]); got != 12345 {
+		t.Fatalf("got key %d expected 12345", got)
+	}
+	for _, h := range []quality.Hash{quality.XXH32(0), quality.XXH64(0)} {
+		res := quality.Distribution(h, keys, 1021)
+		if res.Keys != len(keys) || res.Buckets != 1021 {
+			t.Errorf("%s: invalid result %+v", h.Name, res)
+		}
+		if math.Abs(res.Score) > 5 || res.Collisions > 5 {
+			t.Errorf("%s: got %+v", h.Name, res)
+		}
+	}
+	// Repeated keys are not collisions.
+	dups := append(keys[:1000:1000], keys[:1000]...)
+	if res := quality.Distribution(quality.XXH64(0), dups, 1021); res.Keys != 2000 || res.Collisions != 0 {
+		t.Errorf("repeated keys: got %+v", res)
+	}
+	// The weak hash spreads sequential keys too evenly and collides a lot.
+	if res := quality.Distribution(weak, keys, 1024); res.Score > -10 || res.Collisions == 0 {
+		t.Errorf("%s: got %+v", weak.Name, res)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for name, f := range map[string]func(){
+		"bits":    func() { quality.Avalanche(quality.Hash{Bits: 65, Func: weak.Func}, 1, 10, 0) },
+		"trials":  func() { quality.Avalanche(weak, 1, 1, 0) },
+		"keylen":  func() { quality.BitIndependence(weak, 0, 10, 0) },
+		"buckets": func() { quality.Distribution(weak, [][]byte{nil}, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
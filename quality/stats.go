@@ -0,0 +1,55 @@
+package quality
+
+import (
+	"bufio"
+	"io"
+)
+
+// DefaultTableSizes are the table sizes used by Stats if none is given,
+// powers of two and primes commonly used by hash tables.
+var DefaultTableSizes = []int{256, 1021, 1024, 4093, 4096, 65521, 65536}
+
+// Report holds the statistics of a Hash over a key corpus.
+type Report struct {
+	// Hash is the name of the Hash.
+	Hash string
+	// Keys is the number of keys.
+	Keys int
+	// Collisions is the number of distinct keys whose full hash value equals the one of
+	// a previous distinct key.
+	Collisions int
+	// Tables holds the distribution of the keys for each table size.
+	Tables []DistributionResult
+}
+
+// Stats hashes keys and reports their collisions and their distribution
+// in tables of the given sizes, DefaultTableSizes if empty.
+func Stats(h Hash, keys [][]byte, tableSizes ...int) Report {
+	if len(tableSizes) == 0 {
+		tableSizes = DefaultTableSizes
+	}
+	h.check()
+	for _, n := range tableSizes {
+		checkDistribution(keys, n)
+	}
+	hashes := hashKeys(h, keys)
+	r := Report{Hash: h.Name, Keys: len(keys), Collisions: collisions(keys, hashes)}
+	for _, n := range tableSizes {
+		d := distribution(hashes, n)
+		d.Collisions = r.Collisions
+		r.Tables = append(r.Tables, d)
+	}
+	return r
+}
+
+// ReadKeys returns the lines read from r until io.EOF, without their line endings,
+// for use as a key corpus.
+func ReadKeys(r io.Reader) ([][]byte, error) {
+	var keys [][]byte
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		keys = append(keys, append([]byte(nil), s.Bytes()...))
+	}
+	return keys, s.Err()
+}
@@ -0,0 +1,40 @@
+package quality_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierrec/xxHash/quality"
+)
+
+func TestStats(t *testing.T) {
+	keys := quality.SequentialKeys(10000, 4)
+	r := quality.Stats(quality.XXH64(0), keys)
+	if r.Hash != "XXH64" || r.Keys != len(keys) || len(r.Tables) != len(quality.DefaultTableSizes) {
+		t.Fatalf("invalid report %+v", r)
+	}
+	for i, d := range r.Tables {
+		if d.Buckets != quality.DefaultTableSizes[i] || d.Skew < 1 {
+			t.Errorf("invalid distribution %+v", d)
+		}
+	}
+
+	if r = quality.Stats(quality.XXH64(0), append(keys, keys...)); r.Collisions != 0 {
+		t.Errorf("repeated keys counted as collisions: %+v", r)
+	}
+
+	r = quality.Stats(weak, keys, 16)
+	if len(r.Tables) != 1 || r.Tables[0].Buckets != 16 || r.Collisions == 0 {
+		t.Errorf("invalid report %+v", r)
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	keys, err := quality.ReadKeys(strings.NewReader("a\nbc\r\n\nd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 4 || string(keys[1]) != "bc" || len(keys[2]) != 0 || string(keys[3]) != "d" {
+		t.Errorf("got keys %q", keys)
+	}
+}
@@ -0,0 +1,35 @@
+package rsync
+
+// rollsum is the rolling weak checksum of rsync, an Adler-32 like checksum
+// whose window can be slid by one byte in constant time.
+type rollsum struct {
+	a, b uint32
+	n    uint32 // window size
+}
+
+// init computes the checksum of the window w.
+func (r *rollsum) init(w []byte) {
+	r.a, r.b, r.n = 0, 0, uint32(len(w))
+	for i, c := range w {
+		r.a += uint32(c)
+		r.b += uint32(len(w)-i) * uint32(c)
+	}
+}
+
+// roll slides the window by one byte, removing out and adding in.
+func (r *rollsum) roll(out, in byte) {
+	r.a += uint32(in) - uint32(out)
+	r.b += r.a - r.n*uint32(out)
+}
+
+// sum returns the checksum of the window.
+func (r *rollsum) sum() uint32 {
+	return r.a&0xFFFF | r.b<<16
+}
+
+// weakSum returns the weak checksum of w.
+func weakSum(w []byte) uint32 {
+	var r rollsum
+	r.init(w)
+	return r.sum()
+}
@@ -0,0 +1,262 @@
+// Package rsync implements the signature and delta halves of the rsync algorithm
+// (https://rsync.samba.org/tech_report/), using xxHash64 (https://github.com/Cyan4973/xxHash/)
+// as the strong hash of the blocks.
+//
+// The receiver of an update computes the Signature of its version of the data and sends it
+// to the sender, which computes the Delta from it to the new data. The receiver then
+// rebuilds the new data by applying the delta to its version with Apply.
+// Only the parts of the new data not found in the old version are transferred.
+package rsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// DefaultBlockSize is a reasonable block size for files of a few megabytes.
+const DefaultBlockSize = 2048
+
+var (
+	// ErrInvalid is returned when unmarshaling an invalid signature or applying an invalid delta.
+	ErrInvalid = errors.New("rsync: invalid signature or delta")
+	// ErrMismatch is returned when applying a delta to old data that does not match its signature.
+	ErrMismatch = errors.New("rsync: old data does not match the signature")
+)
+
+// BlockSum holds the checksums of a block.
+type BlockSum struct {
+	Weak   uint32 // rolling checksum
+	Strong uint64 // 64bits Hash value with the Signature seed
+}
+
+// Signature holds the checksums of the blocks of some data.
+type Signature struct {
+	BlockSize int
+	Seed      uint64
+	Size      int64 // size of the data, the last block being shorter if it is not a multiple of BlockSize
+	Blocks    []BlockSum
+}
+
+// NewSignature returns the Signature of the data read from r until io.EOF, using blocks of blockSize bytes.
+// It panics if blockSize is not positive.
+func NewSignature(r io.Reader, blockSize int, seed uint64) (*Signature, error) {
+	if blockSize <= 0 {
+		panic("rsync: invalid block size")
+	}
+	sig := &Signature{BlockSize: blockSize, Seed: seed}
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			b := buf[:n]
+			sig.Blocks = append(sig.Blocks, BlockSum{weakSum(b), xxHash64.Checksum(b, seed)})
+			sig.Size += int64(n)
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return sig, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+// blockLen returns the size of the i-th block.
+func (s *Signature) blockLen(i int) int {
+	if off := int64(i) * int64(s.BlockSize); s.Size-off < int64(s.BlockSize) {
+		return int(s.Size - off)
+	}
+	return s.BlockSize
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 25, 25+12*len(s.Blocks))
+	buf[0] = 1 // version
+	binary.LittleEndian.PutUint64(buf[1:], uint64(s.BlockSize))
+	binary.LittleEndian.PutUint64(buf[9:], s.Seed)
+	binary.LittleEndian.PutUint64(buf[17:], uint64(s.Size))
+	for _, b := range s.Blocks {
+		buf = binary.LittleEndian.AppendUint32(buf, b.Weak)
+		buf = binary.LittleEndian.AppendUint64(buf, b.Strong)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	if len(data) < 25 || data[0] != 1 || (len(data)-25)%12 != 0 {
+		return ErrInvalid
+	}
+	bs := binary.LittleEndian.Uint64(data[1:])
+	size := binary.LittleEndian.Uint64(data[17:])
+	n := uint64(len(data)-25) / 12
+	if bs == 0 || bs > 1<<31 || size > 1<<62 || (size+bs-1)/bs != n {
+		return ErrInvalid
+	}
+	s.BlockSize = int(bs)
+	s.Seed = binary.LittleEndian.Uint64(data[9:])
+	s.Size = int64(size)
+	s.Blocks = make([]BlockSum, n)
+	for i := range s.Blocks {
+		b := data[25+12*i:]
+		s.Blocks[i] = BlockSum{binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint64(b[4:])}
+	}
+	return nil
+}
+
+// OpKind is the kind of an Op.
+type OpKind int
+
+const (
+	// OpCopy copies Count blocks of the old data starting at Block.
+	OpCopy OpKind = iota
+	// OpData inserts Data.
+	OpData
+)
+
+// Op is an operation of a delta.
+type Op struct {
+	Kind  OpKind
+	Block int    // first block to copy
+	Count int    // number of blocks to copy
+	Data  []byte // literal data
+}
+
+func (op Op) String() string {
+	if op.Kind == OpCopy {
+		return fmt.Sprintf("copy(%d, %d)", op.Block, op.Count)
+	}
+	return fmt.Sprintf("data(%d)", len(op.Data))
+}
+
+// Delta returns the operations building data from the old data whose signature is sig.
+// The literal data of the operations shares the memory of data.
+func Delta(sig *Signature, data []byte) []Op {
+	bs := sig.BlockSize
+	index := make(map[uint32][]int, len(sig.Blocks))
+	last := len(sig.Blocks) - 1
+	for i, b := range sig.Blocks {
+		if sig.blockLen(i) == bs {
+			index[b.Weak] = append(index[b.Weak], i)
+		}
+	}
+	// find returns the full block matching w, or -1.
+	find := func(weak uint32, w []byte) int {
+		cands := index[weak]
+		if len(cands) == 0 {
+			return -1
+		}
+		strong := xxHash64.Checksum(w, sig.Seed)
+		for _, j := range cands {
+			if sig.Blocks[j].Strong == strong {
+				return j
+			}
+		}
+		return -1
+	}
+
+	var d deltaBuilder
+	lit := 0
+	var r rollsum
+	if len(data) >= bs {
+		r.init(data[:bs])
+	}
+	for i := 0; i+bs <= len(data); {
+		if j := find(r.sum(), data[i:i+bs]); j >= 0 {
+			d.data(data[lit:i])
+			d.copy(j)
+			i += bs
+			lit = i
+			if i+bs <= len(data) {
+				r.init(data[i : i+bs])
+			}
+			continue
+		}
+		if i+bs == len(data) {
+			break
+		}
+		r.roll(data[i], data[i+bs])
+		i++
+	}
+	// A shorter last block can only match the end of data.
+	if last >= 0 {
+		if n := sig.blockLen(last); n < bs && n > 0 && len(data)-lit >= n {
+			tail := data[len(data)-n:]
+			if b := sig.Blocks[last]; weakSum(tail) == b.Weak && xxHash64.Checksum(tail, sig.Seed) == b.Strong {
+				d.data(data[lit : len(data)-n])
+				d.copy(last)
+				lit = len(data)
+			}
+		}
+	}
+	d.data(data[lit:])
+	return d.ops
+}
+
+// deltaBuilder merges consecutive block copies.
+type deltaBuilder struct {
+	ops []Op
+}
+
+func (d *deltaBuilder) data(p []byte) {
+	if len(p) > 0 {
+		d.ops = append(d.ops, Op{Kind: OpData, Data: p})
+	}
+}
+
+func (d *deltaBuilder) copy(block int) {
+	if n := len(d.ops); n > 0 {
+		if op := &d.ops[n-1]; op.Kind == OpCopy && op.Block+op.Count == block {
+			op.Count++
+			return
+		}
+	}
+	d.ops = append(d.ops, Op{Kind: OpCopy, Block: block, Count: 1})
+}
+
+// Apply writes to w the data built by applying ops to the old data read from old,
+// whose signature is sig. The copied blocks are checked against their strong hash.
+func Apply(w io.Writer, old io.ReaderAt, sig *Signature, ops []Op) error {
+	var buf []byte
+	for _, op := range ops {
+		switch op.Kind {
+		case OpData:
+			if _, err := w.Write(op.Data); err != nil {
+				return err
+			}
+		case OpCopy:
+			if op.Block < 0 || op.Count <= 0 || op.Block+op.Count > len(sig.Blocks) {
+				return ErrInvalid
+			}
+			off := int64(op.Block) * int64(sig.BlockSize)
+			for i := op.Block; i < op.Block+op.Count; i++ {
+				n := sig.blockLen(i)
+				if cap(buf) < n {
+					buf = make([]byte, sig.BlockSize)
+				}
+				if m, err := old.ReadAt(buf[:n], off); m < n {
+					if err == io.EOF {
+						err = io.ErrUnexpectedEOF
+					}
+					return err
+				}
+				if xxHash64.Checksum(buf[:n], sig.Seed) != sig.Blocks[i].Strong {
+					return ErrMismatch
+				}
+				if _, err := w.Write(buf[:n]); err != nil {
+					return err
+				}
+				off += int64(n)
+			}
+		default:
+			return ErrInvalid
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package rsync_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/pierrec/xxHash/rsync"
+	"github.com/pierrec/xxHash/testvectors"
+)
+
+// sync rebuilds new from old with a delta and returns the delta.
+func sync(t *testing.T, old, new []byte, blockSize int) []rsync.Op {
+	t.Helper()
+	sig, err := rsync.NewSignature(bytes.NewReader(old), blockSize, 0xCAFE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := rsync.Delta(sig, new)
+	var buf bytes.Buffer
+	if err := rsync.Apply(&buf, bytes.NewReader(old), sig, ops); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), new) {
+		t.Fatalf("rebuilt %d bytes differ from the %d new ones, ops %v", buf.Len(), len(new), ops)
+	}
+	return ops
+}
+
+// literal returns the number of bytes transferred as data.
+func literal(ops []rsync.Op) int {
+	var n int
+	for _, op := range ops {
+		n += len(op.Data)
+	}
+	return n
+}
+
+func TestDelta(t *testing.T) {
+	old := testvectors.Input(10000)
+	const bs = 100
+
+	if ops := sync(t, old, old, bs); len(ops) != 1 || ops[0].Kind != rsync.OpCopy || ops[0].Count != 100 {
+		t.Errorf("identical: got ops %v", ops)
+	}
+
+	// Insertion in the middle.
+	new := append(append(append([]byte(nil), old[:5050]...), "inserted"...), old[5050:]...)
+	if ops := sync(t, old, new, bs); literal(ops) > 2*bs {
+		t.Errorf("insertion: got %d literal bytes with ops %v", literal(ops), ops)
+	}
+
+	// Deletion and a modified byte.
+	new = append(append([]byte(nil), old[:3000]...), old[3333:]...)
+	new[7000] ^= 1
+	if ops := sync(t, old, new, bs); literal(ops) > 3*bs {
+		t.Errorf("deletion: got %d literal bytes with ops %v", literal(ops), ops)
+	}
+
+	// Shorter last block, moved.
+	short := old[:9950]
+	new = append(append([]byte("head"), short...), "tail"...)
+	sync(t, short, new, bs)
+	new = append([]byte("head"), short...)
+	if ops := sync(t, short, new, bs); literal(ops) != 4 {
+		t.Errorf("short: got %d literal bytes with ops %v", literal(ops), ops)
+	}
+
+	// Unrelated and empty data.
+	rnd := rand.New(rand.NewSource(1))
+	unrelated := make([]byte, 5000)
+	rnd.Read(unrelated)
+	if ops := sync(t, old, unrelated, bs); literal(ops) != len(unrelated) {
+		t.Errorf("unrelated: got ops %v", ops)
+	}
+	sync(t, nil, old, bs)
+	if ops := sync(t, old, nil, bs); len(ops) != 0 {
+		t.Errorf("empty: got ops %v", ops)
+	}
+}
+
+func TestSignatureMarshal(t *testing.T) {
+	sig, err := rsync.NewSignature(bytes.NewReader(testvectors.Input(1050)), 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig.Blocks) != 11 || sig.Size != 1050 {
+		t.Fatalf("got %d blocks for %d bytes", len(sig.Blocks), sig.Size)
+	}
+	b, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got rsync.Signature
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.BlockSize != sig.BlockSize || got.Seed != sig.Seed || got.Size != sig.Size || len(got.Blocks) != len(sig.Blocks) || got.Blocks[10] != sig.Blocks[10] {
+		t.Errorf("got %+v expected %+v", got, sig)
+	}
+	for _, bad := range [][]byte{nil, b[:len(b)-1], b[:len(b)-12]} {
+		if err := got.UnmarshalBinary(bad); err != rsync.ErrInvalid {
+			t.Errorf("got error %v expected %v", err, rsync.ErrInvalid)
+		}
+	}
+}
+
+func TestApplyMismatch(t *testing.T) {
+	old := testvectors.Input(1000)
+	sig, _ := rsync.NewSignature(bytes.NewReader(old), 100, 0)
+	ops := rsync.Delta(sig, old)
+	changed := append([]byte(nil), old...)
+	changed[500] ^= 1
+	var buf bytes.Buffer
+	if err := rsync.Apply(&buf, bytes.NewReader(changed), sig, ops); err != rsync.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, rsync.ErrMismatch)
+	}
+	if err := rsync.Apply(&buf, bytes.NewReader(old), sig, []rsync.Op{{Kind: rsync.OpCopy, Block: 5, Count: 6}}); err != rsync.ErrInvalid {
+		t.Errorf("got error %v expected %v", err, rsync.ErrInvalid)
+	}
+}
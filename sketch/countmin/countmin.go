@@ -0,0 +1,142 @@
+// Package countmin implements a Count-Min sketch (https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch)
+// for streaming frequency estimation.
+//
+// The hash function of row i is the xxHash64 (https://github.com/Cyan4973/xxHash/) Checksum of the key with seed i.
+package countmin
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var (
+	// ErrInvalid is returned when unmarshaling invalid data.
+	ErrInvalid = errors.New("countmin: invalid sketch data")
+	// ErrMismatch is returned when merging sketches with different dimensions.
+	ErrMismatch = errors.New("countmin: sketch dimensions mismatch")
+)
+
+// version of the binary encoding.
+const version = 1
+
+// Sketch is a Count-Min sketch.
+type Sketch struct {
+	depth  int
+	width  int
+	counts []uint64
+}
+
+// New returns a Sketch with depth rows of width counters.
+// It panics if depth or width are not positive.
+func New(depth, width int) *Sketch {
+	if depth <= 0 || width <= 0 {
+		panic("countmin: invalid sketch dimensions")
+	}
+	return &Sketch{
+		depth:  depth,
+		width:  width,
+		counts: make([]uint64, depth*width),
+	}
+}
+
+// NewWithEstimates returns a Sketch whose estimates exceed the true counts
+// by at most epsilon times the total count with probability 1-delta.
+func NewWithEstimates(epsilon, delta float64) *Sketch {
+	if epsilon <= 0 || delta <= 0 || delta >= 1 {
+		panic("countmin: invalid error bounds")
+	}
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return New(depth, width)
+}
+
+// Depth returns the number of rows of the sketch.
+func (s *Sketch) Depth() int {
+	return s.depth
+}
+
+// Width returns the number of counters per row of the sketch.
+func (s *Sketch) Width() int {
+	return s.width
+}
+
+// Add adds count occurrences of key.
+func (s *Sketch) Add(key []byte, count uint64) {
+	for i := 0; i < s.depth; i++ {
+		s.counts[s.index(i, key)] += count
+	}
+}
+
+// Estimate returns the estimated number of occurrences of key.
+// It is never lower than the actual count.
+func (s *Sketch) Estimate(key []byte) uint64 {
+	min := uint64(math.MaxUint64)
+	for i := 0; i < s.depth; i++ {
+		if c := s.counts[s.index(i, key)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// Merge adds the counts of o to the sketch.
+// Both sketches must have the same dimensions.
+func (s *Sketch) Merge(o *Sketch) error {
+	if s.depth != o.depth || s.width != o.width {
+		return ErrMismatch
+	}
+	for i, c := range o.counts {
+		s.counts[i] += c
+	}
+	return nil
+}
+
+// Reset sets all the counts to zero.
+func (s *Sketch) Reset() {
+	for i := range s.counts {
+		s.counts[i] = 0
+	}
+}
+
+// index returns the index of the counter for key in row i.
+func (s *Sketch) index(i int, key []byte) int {
+	h := xxHash64.Checksum(key, uint64(i))
+	return i*s.width + int(h%uint64(s.width))
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 9+8*len(s.counts))
+	buf[0] = version
+	binary.LittleEndian.PutUint32(buf[1:], uint32(s.depth))
+	binary.LittleEndian.PutUint32(buf[5:], uint32(s.width))
+	for i, c := range s.counts {
+		binary.LittleEndian.PutUint64(buf[9+8*i:], c)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 || data[0] != version {
+		return ErrInvalid
+	}
+	depth := uint64(binary.LittleEndian.Uint32(data[1:]))
+	width := uint64(binary.LittleEndian.Uint32(data[5:]))
+	data = data[9:]
+	// The dimensions are bounded by division first, as 8*depth*width overflows for large values.
+	words := uint64(len(data) / 8)
+	if depth == 0 || width == 0 || len(data)%8 != 0 || width > words/depth || depth*width != words {
+		return ErrInvalid
+	}
+	s.depth = int(depth)
+	s.width = int(width)
+	s.counts = make([]uint64, len(data)/8)
+	for i := range s.counts {
+		s.counts[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
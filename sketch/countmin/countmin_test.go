@@ -0,0 +1,80 @@
+package countmin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/sketch/countmin"
+)
+
+func TestSketch(t *testing.T) {
+	const epsilon = 0.001
+	s := countmin.NewWithEstimates(epsilon, 0.01)
+	var total uint64
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprint(i))
+		s.Add(key, uint64(i))
+		total += uint64(i)
+	}
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprint(i))
+		est := s.Estimate(key)
+		if est < uint64(i) {
+			t.Fatalf("key %d: estimate %d lower than count", i, est)
+		}
+		if est > uint64(i)+uint64(3*epsilon*float64(total)) {
+			t.Errorf("key %d: estimate %d too high", i, est)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := countmin.New(4, 100), countmin.New(4, 100)
+	a.Add([]byte("a"), 2)
+	b.Add([]byte("a"), 3)
+	b.Add([]byte("b"), 1)
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if est := a.Estimate([]byte("a")); est < 5 {
+		t.Errorf("got estimate %d expected at least 5", est)
+	}
+	if err := a.Merge(countmin.New(3, 100)); err != countmin.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, countmin.ErrMismatch)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	s := countmin.New(3, 50)
+	for i := 0; i < 100; i++ {
+		s.Add([]byte(fmt.Sprint(i)), uint64(i))
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u countmin.Sketch
+	if err := u.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprint(i))
+		if s.Estimate(key) != u.Estimate(key) {
+			t.Fatalf("key %d: sketches differ", i)
+		}
+	}
+	if err := u.UnmarshalBinary(data[:10]); err != countmin.ErrInvalid {
+		t.Errorf("got error %v expected %v", err, countmin.ErrInvalid)
+	}
+
+	for _, header := range [][]byte{
+		// 8*depth*width used to overflow to zero and match the empty data.
+		{1, 0, 0, 0, 0x80, 0, 0, 0, 0x40},
+		{1, 0, 0, 0, 0, 1, 0, 0, 0},
+		{1, 1, 0, 0, 0, 0, 0, 0, 0},
+	} {
+		if err := u.UnmarshalBinary(header); err != countmin.ErrInvalid {
+			t.Errorf("%x: got error %v expected %v", header, err, countmin.ErrInvalid)
+		}
+	}
+}
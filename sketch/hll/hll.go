@@ -0,0 +1,149 @@
+// Package hll implements the HyperLogLog cardinality estimator
+// (http://algo.inria.fr/flajolet/Publications/FlFuGaMe07.pdf).
+//
+// Elements are hashed with the xxHash64 (https://github.com/Cyan4973/xxHash/) Checksum with a zero seed:
+// the p most significant bits of the hash select the register and the remaining bits its value.
+package hll
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var (
+	// ErrInvalid is returned when unmarshaling invalid data.
+	ErrInvalid = errors.New("hll: invalid sketch data")
+	// ErrMismatch is returned when merging sketches with different precisions.
+	ErrMismatch = errors.New("hll: sketch precision mismatch")
+)
+
+const (
+	// MinPrecision is the minimum supported precision.
+	MinPrecision = 4
+	// MaxPrecision is the maximum supported precision.
+	MaxPrecision = 18
+)
+
+// version of the binary encoding.
+const version = 1
+
+// Sketch is a HyperLogLog sketch.
+type Sketch struct {
+	p    uint8
+	regs []uint8
+}
+
+// New returns a Sketch using 2^precision registers.
+// The standard error of the estimates is 1.04/sqrt(2^precision).
+// It panics if precision is not within [MinPrecision, MaxPrecision].
+func New(precision int) *Sketch {
+	if precision < MinPrecision || precision > MaxPrecision {
+		panic("hll: invalid precision")
+	}
+	return &Sketch{
+		p:    uint8(precision),
+		regs: make([]uint8, 1<<precision),
+	}
+}
+
+// Precision returns the precision of the sketch.
+func (s *Sketch) Precision() int {
+	return int(s.p)
+}
+
+// Add adds an element to the sketch.
+func (s *Sketch) Add(element []byte) {
+	s.AddHash(xxHash64.Checksum(element, 0))
+}
+
+// AddHash adds an element given its xxHash64 Checksum with a zero seed.
+func (s *Sketch) AddHash(h uint64) {
+	i := h >> (64 - s.p)
+	w := h<<s.p | 1<<(s.p-1)
+	if r := uint8(bits.LeadingZeros64(w) + 1); r > s.regs[i] {
+		s.regs[i] = r
+	}
+}
+
+// Estimate returns the estimated number of distinct elements added to the sketch.
+func (s *Sketch) Estimate() uint64 {
+	m := float64(len(s.regs))
+	var sum float64
+	var zeros int
+	for _, r := range s.regs {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	e := alpha(len(s.regs)) * m * m / sum
+	if e <= 2.5*m && zeros > 0 {
+		// Small range correction.
+		e = m * math.Log(m/float64(zeros))
+	}
+	return uint64(e + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge merges o into the sketch, which then estimates the cardinality of the union of both sets.
+// Both sketches must have the same precision.
+func (s *Sketch) Merge(o *Sketch) error {
+	if s.p != o.p {
+		return ErrMismatch
+	}
+	for i, r := range o.regs {
+		if r > s.regs[i] {
+			s.regs[i] = r
+		}
+	}
+	return nil
+}
+
+// Reset removes all the elements from the sketch.
+func (s *Sketch) Reset() {
+	for i := range s.regs {
+		s.regs[i] = 0
+	}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 2+len(s.regs))
+	buf[0] = version
+	buf[1] = s.p
+	copy(buf[2:], s.regs)
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != version {
+		return ErrInvalid
+	}
+	p := data[1]
+	if p < MinPrecision || p > MaxPrecision || len(data)-2 != 1<<p {
+		return ErrInvalid
+	}
+	for _, r := range data[2:] {
+		if r > 65-p {
+			return ErrInvalid
+		}
+	}
+	s.p = p
+	s.regs = append([]uint8(nil), data[2:]...)
+	return nil
+}
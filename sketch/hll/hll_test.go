@@ -0,0 +1,71 @@
+package hll_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/sketch/hll"
+)
+
+func within(est, n uint64, precision int) bool {
+	stderr := 1.04 / math.Sqrt(float64(uint64(1)<<precision))
+	return math.Abs(float64(est)-float64(n)) <= 4*stderr*float64(n)
+}
+
+func TestEstimate(t *testing.T) {
+	for _, precision := range []int{hll.MinPrecision, 10, 14} {
+		s := hll.New(precision)
+		if est := s.Estimate(); est != 0 {
+			t.Errorf("precision %d: got estimate %d for an empty sketch", precision, est)
+		}
+		for _, n := range []int{10, 1000, 100000} {
+			s.Reset()
+			for i := 0; i < n; i++ {
+				s.Add([]byte(fmt.Sprint(i)))
+				s.Add([]byte(fmt.Sprint(i)))
+			}
+			if est := s.Estimate(); !within(est, uint64(n), precision) {
+				t.Errorf("precision %d: got estimate %d for %d elements", precision, est, n)
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := hll.New(12), hll.New(12)
+	for i := 0; i < 20000; i++ {
+		a.Add([]byte(fmt.Sprint(i)))
+		b.Add([]byte(fmt.Sprint(i + 10000)))
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if est := a.Estimate(); !within(est, 30000, 12) {
+		t.Errorf("got estimate %d expected 30000", est)
+	}
+	if err := a.Merge(hll.New(10)); err != hll.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, hll.ErrMismatch)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	s := hll.New(8)
+	for i := 0; i < 1000; i++ {
+		s.Add([]byte(fmt.Sprint(i)))
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u hll.Sketch
+	if err := u.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if u.Precision() != 8 || u.Estimate() != s.Estimate() {
+		t.Errorf("got precision %d estimate %d expected 8 and %d", u.Precision(), u.Estimate(), s.Estimate())
+	}
+	if err := u.UnmarshalBinary(data[:100]); err != hll.ErrInvalid {
+		t.Errorf("got error %v expected %v", err, hll.ErrInvalid)
+	}
+}
@@ -0,0 +1,68 @@
+// Package minhash implements MinHash signatures (https://en.wikipedia.org/wiki/MinHash)
+// for estimating the Jaccard similarity of sets.
+//
+// The i-th permutation of a signature is the xxHash64 (https://github.com/Cyan4973/xxHash/)
+// Checksum of the elements with seed i.
+package minhash
+
+import (
+	"errors"
+	"math"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// ErrMismatch is returned when comparing signatures of different sizes.
+var ErrMismatch = errors.New("minhash: signature size mismatch")
+
+// MinHash computes the signature of a set.
+type MinHash struct {
+	mins []uint64
+}
+
+// New returns a MinHash computing signatures of k values.
+// The standard error of the similarity estimates is 1/sqrt(k).
+// It panics if k is not positive.
+func New(k int) *MinHash {
+	if k <= 0 {
+		panic("minhash: invalid signature size")
+	}
+	m := &MinHash{mins: make([]uint64, k)}
+	m.Reset()
+	return m
+}
+
+// Add adds an element to the set.
+func (m *MinHash) Add(element []byte) {
+	for i, min := range m.mins {
+		if h := xxHash64.Checksum(element, uint64(i)); h < min {
+			m.mins[i] = h
+		}
+	}
+}
+
+// Signature returns the signature of the set.
+func (m *MinHash) Signature() []uint64 {
+	return append([]uint64(nil), m.mins...)
+}
+
+// Reset removes all the elements from the set.
+func (m *MinHash) Reset() {
+	for i := range m.mins {
+		m.mins[i] = math.MaxUint64
+	}
+}
+
+// Similarity returns the estimated Jaccard similarity of the sets with signatures a and b.
+func Similarity(a, b []uint64) (float64, error) {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0, ErrMismatch
+	}
+	var eq int
+	for i, h := range a {
+		if h == b[i] {
+			eq++
+		}
+	}
+	return float64(eq) / float64(len(a)), nil
+}
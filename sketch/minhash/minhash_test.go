@@ -0,0 +1,33 @@
+package minhash_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/sketch/minhash"
+)
+
+func TestSimilarity(t *testing.T) {
+	const k = 256
+	a, b := minhash.New(k), minhash.New(k)
+	// a holds [0, 1000[, b holds [500, 1500[: their Jaccard similarity is 1/3.
+	for i := 0; i < 1000; i++ {
+		a.Add([]byte(fmt.Sprint(i)))
+		b.Add([]byte(fmt.Sprint(i + 500)))
+	}
+	sim, err := minhash.Similarity(a.Signature(), b.Signature())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(sim-1.0/3) > 3/math.Sqrt(k) {
+		t.Errorf("got similarity %f expected 0.33", sim)
+	}
+
+	if sim, _ := minhash.Similarity(a.Signature(), a.Signature()); sim != 1 {
+		t.Errorf("got self similarity %f", sim)
+	}
+	if _, err := minhash.Similarity(a.Signature(), minhash.New(k+1).Signature()); err != minhash.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, minhash.ErrMismatch)
+	}
+}
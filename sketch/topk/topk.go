@@ -0,0 +1,137 @@
+// Package topk implements the Space-Saving heavy hitters sketch
+// (https://www.cs.ucsb.edu/sites/default/files/documents/2005-23.pdf),
+// identifying the most frequent keys of a stream with a bounded memory.
+//
+// Keys are identified by their xxHash64 (https://github.com/Cyan4973/xxHash/) Checksum with a zero seed,
+// so that distinct keys with the same hash are counted together.
+package topk
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Item is a key tracked by the sketch.
+type Item struct {
+	Key string
+	// Count is the estimated number of occurrences of the key.
+	Count uint64
+	// Error is the maximum overestimation of Count.
+	Error uint64
+}
+
+type counter struct {
+	Item
+	hash  uint64
+	index int // in the heap
+}
+
+// Sketch is a Space-Saving sketch.
+type Sketch struct {
+	capacity int
+	counters map[uint64]*counter
+	heap     minHeap
+}
+
+// New returns a Sketch tracking up to capacity keys.
+// Any key occurring more than N/capacity times in a stream of N keys is guaranteed to be tracked.
+// It panics if capacity is not positive.
+func New(capacity int) *Sketch {
+	if capacity <= 0 {
+		panic("topk: invalid capacity")
+	}
+	return &Sketch{
+		capacity: capacity,
+		counters: make(map[uint64]*counter, capacity),
+	}
+}
+
+// Add adds count occurrences of key.
+func (s *Sketch) Add(key []byte, count uint64) {
+	s.add(xxHash64.Checksum(key, 0), key, count, 0)
+}
+
+func (s *Sketch) add(h uint64, key []byte, count, errcount uint64) {
+	if c, ok := s.counters[h]; ok {
+		c.Count += count
+		c.Error += errcount
+		heap.Fix(&s.heap, c.index)
+		return
+	}
+	if len(s.heap) < s.capacity {
+		c := &counter{Item: Item{Key: string(key), Count: count, Error: errcount}, hash: h}
+		s.counters[h] = c
+		heap.Push(&s.heap, c)
+		return
+	}
+	// Replace the key with the lowest count.
+	c := s.heap[0]
+	delete(s.counters, c.hash)
+	c.Error = c.Count + errcount
+	c.Count += count
+	c.Key = string(key)
+	c.hash = h
+	s.counters[h] = c
+	heap.Fix(&s.heap, 0)
+}
+
+// Top returns the k most frequent keys, by decreasing count.
+// It returns no keys if k is not positive.
+func (s *Sketch) Top(k int) []Item {
+	if k < 0 {
+		k = 0
+	}
+	items := make([]Item, len(s.heap))
+	for i, c := range s.heap {
+		items[i] = c.Item
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Key < items[j].Key
+	})
+	if k < len(items) {
+		items = items[:k]
+	}
+	return items
+}
+
+// Merge adds the keys tracked by o to the sketch.
+func (s *Sketch) Merge(o *Sketch) {
+	for _, c := range o.heap {
+		s.add(c.hash, []byte(c.Key), c.Count, c.Error)
+	}
+}
+
+// Reset removes all the keys from the sketch.
+func (s *Sketch) Reset() {
+	s.counters = make(map[uint64]*counter, s.capacity)
+	s.heap = s.heap[:0]
+}
+
+// minHeap orders the counters by increasing count.
+type minHeap []*counter
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h minHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *minHeap) Push(x interface{}) {
+	c := x.(*counter)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,55 @@
+package topk_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/sketch/topk"
+)
+
+func TestTop(t *testing.T) {
+	s := topk.New(20)
+	// Key i occurs 1000/(i+1) times, interleaved with noise.
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 1000/(i+1); j++ {
+			s.Add([]byte(fmt.Sprint("key", i)), 1)
+			s.Add([]byte(fmt.Sprint("noise", i, j)), 1)
+		}
+	}
+	for _, k := range []int{-1, 0} {
+		if top := s.Top(k); len(top) != 0 {
+			t.Errorf("k=%d: got %d items expected none", k, len(top))
+		}
+	}
+	top := s.Top(3)
+	if len(top) != 3 {
+		t.Fatalf("got %d items expected 3", len(top))
+	}
+	for i, item := range top {
+		if want := fmt.Sprint("key", i); item.Key != want {
+			t.Errorf("item %d: got key %s expected %s", i, item.Key, want)
+		}
+		if want := uint64(1000 / (i + 1)); item.Count < want || item.Count-item.Error > want {
+			t.Errorf("item %d: got count %d (error %d) expected %d", i, item.Count, item.Error, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := topk.New(10), topk.New(10)
+	a.Add([]byte("x"), 5)
+	a.Add([]byte("y"), 3)
+	b.Add([]byte("y"), 4)
+	b.Add([]byte("z"), 1)
+	a.Merge(b)
+	top := a.Top(10)
+	want := []topk.Item{{Key: "y", Count: 7}, {Key: "x", Count: 5}, {Key: "z", Count: 1}}
+	if len(top) != len(want) {
+		t.Fatalf("got %v expected %v", top, want)
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Errorf("item %d: got %v expected %v", i, top[i], want[i])
+		}
+	}
+}
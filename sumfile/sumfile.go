@@ -0,0 +1,270 @@
+// Package sumfile reads and writes checksum files as produced by xxhsum
+// (https://github.com/Cyan4973/xxHash/), in either the GNU or the BSD tag format.
+//
+// A GNU format line is the hexadecimal digest followed by two spaces and the file name
+// (a space and an asterisk in binary mode), the algorithm being implied by the digest size.
+// A BSD tag format line is the algorithm name followed by the file name in parentheses, " = " and the digest:
+//
+//	44bc2cf5ad770999  file.txt
+//	XXH64 (file.txt) = 44bc2cf5ad770999
+//
+// As for the coreutils tools, lines of file names containing a new line or a backslash
+// start with a backslash and these characters are escaped.
+package sumfile
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Algorithm identifies the hash algorithm of an entry.
+type Algorithm int
+
+// Supported algorithms.
+const (
+	XXH32 Algorithm = iota
+	XXH64
+	XXH128
+	XXH3
+)
+
+var algorithms = [...]struct {
+	name   string
+	prefix string // of the digest in the GNU format
+	size   int    // in bytes
+}{
+	XXH32:  {"XXH32", "", 4},
+	XXH64:  {"XXH64", "", 8},
+	XXH128: {"XXH128", "", 16},
+	XXH3:   {"XXH3", "XXH3_", 8},
+}
+
+func (a Algorithm) String() string {
+	if a < 0 || int(a) >= len(algorithms) {
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+	return algorithms[a].name
+}
+
+// Size returns the size of the digests of the algorithm in bytes.
+func (a Algorithm) Size() int {
+	return algorithms[a].size
+}
+
+// Format is the format of the checksum lines.
+type Format int
+
+// Supported formats.
+const (
+	GNU Format = iota
+	BSD
+)
+
+// Entry is the checksum of a file.
+type Entry struct {
+	Algorithm Algorithm
+	// Sum is the digest in its canonical form (big endian), as printed in hexadecimal.
+	Sum []byte
+	// Path is the file name.
+	Path string
+	// Binary reports the binary mode flag of the GNU format.
+	Binary bool
+}
+
+// SyntaxError reports an invalid line.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("sumfile: line %d: %s", e.Line, e.Msg)
+}
+
+// Reader reads the entries of a checksum file in either format.
+type Reader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{s: bufio.NewScanner(r)}
+}
+
+// Read returns the next entry, skipping empty lines.
+// It returns io.EOF once all the entries have been read.
+func (r *Reader) Read() (Entry, error) {
+	for r.s.Scan() {
+		r.line++
+		line := strings.TrimSuffix(r.s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		e, msg := parseLine(line)
+		if msg != "" {
+			return Entry{}, &SyntaxError{Line: r.line, Msg: msg}
+		}
+		return e, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// ReadAll returns all the entries read from r.
+func ReadAll(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	sr := NewReader(r)
+	for {
+		e, err := sr.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// parseLine parses a checksum line, returning an error message if it is invalid.
+func parseLine(line string) (Entry, string) {
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	var (
+		e   Entry
+		sum string
+	)
+	if i := strings.Index(line, " ("); i > 0 && !strings.Contains(line[:i], " ") {
+		// BSD tag format.
+		j := strings.LastIndex(line, ") = ")
+		if j < i {
+			return e, "invalid BSD tag line"
+		}
+		a, ok := algorithmByName(line[:i])
+		if !ok {
+			return e, fmt.Sprintf("unknown algorithm %q", line[:i])
+		}
+		e.Algorithm = a
+		e.Path = line[i+2 : j]
+		sum = line[j+4:]
+	} else {
+		// GNU format.
+		i := strings.IndexByte(line, ' ')
+		if i < 0 || i+2 > len(line) || line[i+1] != ' ' && line[i+1] != '*' {
+			return e, "invalid line"
+		}
+		sum = line[:i]
+		e.Binary = line[i+1] == '*'
+		e.Path = line[i+2:]
+		e.Algorithm = XXH32
+		switch {
+		case strings.HasPrefix(sum, algorithms[XXH3].prefix):
+			e.Algorithm = XXH3
+			sum = sum[len(algorithms[XXH3].prefix):]
+		case len(sum) == 2*algorithms[XXH64].size:
+			e.Algorithm = XXH64
+		case len(sum) == 2*algorithms[XXH128].size:
+			e.Algorithm = XXH128
+		}
+	}
+	if e.Path == "" {
+		return e, "missing file name"
+	}
+	if escaped {
+		p, ok := unescape(e.Path)
+		if !ok {
+			return e, "invalid escape sequence"
+		}
+		e.Path = p
+	}
+	if len(sum) != 2*e.Algorithm.Size() {
+		return e, "invalid digest size"
+	}
+	b, err := hex.DecodeString(sum)
+	if err != nil {
+		return e, "invalid digest"
+	}
+	e.Sum = b
+	return e, ""
+}
+
+func algorithmByName(name string) (Algorithm, bool) {
+	for a, alg := range algorithms {
+		if alg.name == name {
+			return Algorithm(a), true
+		}
+	}
+	return 0, false
+}
+
+func unescape(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i == len(s) {
+			return "", false
+		}
+		switch s[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+var escaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+
+// Writer writes checksum files.
+type Writer struct {
+	w      io.Writer
+	format Format
+}
+
+// NewWriter returns a Writer writing lines in the given format to w.
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{w: w, format: format}
+}
+
+// Write writes the line of entry e.
+func (w *Writer) Write(e Entry) error {
+	if e.Algorithm < 0 || int(e.Algorithm) >= len(algorithms) {
+		return fmt.Errorf("sumfile: invalid algorithm %v", e.Algorithm)
+	}
+	if len(e.Sum) != e.Algorithm.Size() {
+		return fmt.Errorf("sumfile: invalid %v digest size %d", e.Algorithm, len(e.Sum))
+	}
+	var prefix string
+	path := e.Path
+	if strings.ContainsAny(path, "\\\n") {
+		prefix = "\\"
+		path = escaper.Replace(path)
+	}
+	var err error
+	switch w.format {
+	case BSD:
+		_, err = fmt.Fprintf(w.w, "%s%v (%s) = %x\n", prefix, e.Algorithm, path, e.Sum)
+	default:
+		mode := ' '
+		if e.Binary {
+			mode = '*'
+		}
+		_, err = fmt.Fprintf(w.w, "%s%s%x %c%s\n", prefix, algorithms[e.Algorithm].prefix, e.Sum, mode, path)
+	}
+	return err
+}
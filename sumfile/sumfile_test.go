@@ -0,0 +1,119 @@
+package sumfile_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pierrec/xxHash/sumfile"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestReadAll(t *testing.T) {
+	const in = `02cc5d05  a.txt
+ef46db3751d8e999 *b.bin
+
+XXH64 (c d.txt) = ef46db3751d8e999
+XXH32 (e) = 02cc5d05
+99aa06d3014798d86001c324468d497f  f
+XXH3_2d06800538d394c2  g
+\ef46db3751d8e999  h\\i\nj
+`
+	entries, err := sumfile.ReadAll(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sumfile.Entry{
+		{sumfile.XXH32, []byte{0x02, 0xcc, 0x5d, 0x05}, "a.txt", false},
+		{sumfile.XXH64, []byte{0xef, 0x46, 0xdb, 0x37, 0x51, 0xd8, 0xe9, 0x99}, "b.bin", true},
+		{sumfile.XXH64, []byte{0xef, 0x46, 0xdb, 0x37, 0x51, 0xd8, 0xe9, 0x99}, "c d.txt", false},
+		{sumfile.XXH32, []byte{0x02, 0xcc, 0x5d, 0x05}, "e", false},
+		{sumfile.XXH128, []byte{0x99, 0xaa, 0x06, 0xd3, 0x01, 0x47, 0x98, 0xd8, 0x60, 0x01, 0xc3, 0x24, 0x46, 0x8d, 0x49, 0x7f}, "f", false},
+		{sumfile.XXH3, []byte{0x2d, 0x06, 0x80, 0x05, 0x38, 0xd3, 0x94, 0xc2}, "g", false},
+		{sumfile.XXH64, []byte{0xef, 0x46, 0xdb, 0x37, 0x51, 0xd8, 0xe9, 0x99}, "h\\i\nj", false},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %v expected %v", entries, want)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	for _, in := range []string{
+		"ef46db3751d8e999",
+		"ef46db3751d8e99  a",
+		"ef46db3751d8e9zz  a",
+		"XXH64 (a) ef46db3751d8e999",
+		"XXH99 (a) = ef46db3751d8e999",
+		"XXH32 (a) = ef46db3751d8e999",
+		"\\ef46db3751d8e999  a\\x",
+	} {
+		_, err := sumfile.ReadAll(strings.NewReader("\n" + in + "\n"))
+		var serr *sumfile.SyntaxError
+		if !errors.As(err, &serr) {
+			t.Errorf("%q: got error %v expected a SyntaxError", in, err)
+			continue
+		}
+		if serr.Line != 2 {
+			t.Errorf("%q: got line %d expected 2", in, serr.Line)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	var c xxHash64.Canonical64
+	c.FromHash(xxHash64.Checksum([]byte("abc"), 0))
+	entries := []sumfile.Entry{
+		{Algorithm: sumfile.XXH64, Sum: c[:], Path: "abc.txt"},
+		{Algorithm: sumfile.XXH64, Sum: c[:], Path: "new\nline\\", Binary: true},
+		{Algorithm: sumfile.XXH3, Sum: c[:], Path: "x"},
+	}
+	for _, format := range []sumfile.Format{sumfile.GNU, sumfile.BSD} {
+		var buf bytes.Buffer
+		w := sumfile.NewWriter(&buf, format)
+		for _, e := range entries {
+			if err := w.Write(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := sumfile.NewReader(&buf)
+		for i, want := range entries {
+			got, err := r.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format == sumfile.BSD {
+				want.Binary = false
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("format %d entry %d: got %v expected %v", format, i, got, want)
+			}
+		}
+		if _, err := r.Read(); err != io.EOF {
+			t.Errorf("got error %v expected %v", err, io.EOF)
+		}
+	}
+}
+
+func TestWriteFormat(t *testing.T) {
+	e := sumfile.Entry{Algorithm: sumfile.XXH32, Sum: []byte{0x02, 0xcc, 0x5d, 0x05}, Path: "a"}
+	for format, want := range map[sumfile.Format]string{
+		sumfile.GNU: "02cc5d05  a\n",
+		sumfile.BSD: "XXH32 (a) = 02cc5d05\n",
+	} {
+		var buf bytes.Buffer
+		if err := sumfile.NewWriter(&buf, format).Write(e); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("got %q expected %q", got, want)
+		}
+	}
+
+	e.Sum = e.Sum[:2]
+	if err := sumfile.NewWriter(io.Discard, sumfile.GNU).Write(e); err == nil {
+		t.Error("expected an error for an invalid digest size")
+	}
+}
@@ -0,0 +1,169 @@
+// Package testvectors provides known answer vectors for the xxHash algorithms
+// (https://github.com/Cyan4973/xxHash/), so that implementations and wrappers
+// can validate themselves against the same data as this module.
+//
+// The inputs are prefixes of the sanity buffer used by the reference xxhsum tool,
+// returned by Input, hashed with several seeds including the ones of xxhsum.
+// Only the XXH32 and XXH64 variants are provided, as they are the ones implemented by this module.
+package testvectors
+
+// Prime32 and Prime64 are the generator constants of the sanity buffer, also used as seeds.
+const (
+	Prime32 = 2654435761
+	Prime64 = 11400714785074694797
+)
+
+// MaxLen is the length of the longest input.
+const MaxLen = 2367
+
+// Vector32 is a known answer for XXH32.
+type Vector32 struct {
+	Len  int    // length of the Input
+	Seed uint32 // seed of the hash
+	Sum  uint32 // expected 32bits Hash value
+}
+
+// Vector64 is a known answer for XXH64.
+type Vector64 struct {
+	Len  int    // length of the Input
+	Seed uint64 // seed of the hash
+	Sum  uint64 // expected 64bits Hash value
+}
+
+// Input returns the first n bytes of the sanity buffer.
+// It panics if n is negative.
+func Input(n int) []byte {
+	if n < 0 {
+		panic("testvectors: negative length")
+	}
+	buf := make([]byte, n)
+	gen := uint64(Prime32)
+	for i := range buf {
+		buf[i] = byte(gen >> 56)
+		gen *= Prime64
+	}
+	return buf
+}
+
+// XXH32 lists the known answers for XXH32.
+var XXH32 = []Vector32{
+	{0, 0x00000000, 0x02cc5d05},
+	{1, 0x00000000, 0xcf65b03e},
+	{2, 0x00000000, 0x1151bee4},
+	{3, 0x00000000, 0xc23884f5},
+	{4, 0x00000000, 0xa9de7ce9},
+	{5, 0x00000000, 0xeb1734bb},
+	{7, 0x00000000, 0x5e1056cd},
+	{8, 0x00000000, 0xa3f6f44b},
+	{9, 0x00000000, 0xffb82a24},
+	{14, 0x00000000, 0x1208e7e2},
+	{15, 0x00000000, 0x6b859e14},
+	{16, 0x00000000, 0x93ba3759},
+	{17, 0x00000000, 0x89fdc23e},
+	{31, 0x00000000, 0x5f40e562},
+	{32, 0x00000000, 0xd89829ec},
+	{33, 0x00000000, 0x31a427e5},
+	{63, 0x00000000, 0xf1d48fdb},
+	{64, 0x00000000, 0x02e95dbb},
+	{65, 0x00000000, 0x16992b3d},
+	{100, 0x00000000, 0x96ad8143},
+	{222, 0x00000000, 0x5bd11dbd},
+	{1024, 0x00000000, 0xc08e0a35},
+	{2367, 0x00000000, 0x4c8a9773},
+	{0, 0x9e3779b1, 0x36b78ae7},
+	{1, 0x9e3779b1, 0xb4545aa4},
+	{2, 0x9e3779b1, 0x1edb879a},
+	{3, 0x9e3779b1, 0x1a269947},
+	{4, 0x9e3779b1, 0x2baafe83},
+	{5, 0x9e3779b1, 0x5874dab0},
+	{7, 0x9e3779b1, 0x3ed9d3fc},
+	{8, 0x9e3779b1, 0xc2a8e239},
+	{9, 0x9e3779b1, 0xd35632c6},
+	{14, 0x9e3779b1, 0x6af1d1fe},
+	{15, 0x9e3779b1, 0xad53090d},
+	{16, 0x9e3779b1, 0xa94fc1e1},
+	{17, 0x9e3779b1, 0xc9910739},
+	{31, 0x9e3779b1, 0x5c0c3350},
+	{32, 0x9e3779b1, 0xa5c44467},
+	{33, 0x9e3779b1, 0x0de5b1f9},
+	{63, 0x9e3779b1, 0x956b3d77},
+	{64, 0x9e3779b1, 0xcf82f830},
+	{65, 0x9e3779b1, 0x428eec5f},
+	{100, 0x9e3779b1, 0x83d48124},
+	{222, 0x9e3779b1, 0x58803c5f},
+	{1024, 0x9e3779b1, 0x1d62ea25},
+	{2367, 0x9e3779b1, 0x6d5366f6},
+}
+
+// XXH64 lists the known answers for XXH64.
+var XXH64 = []Vector64{
+	{0, 0x0000000000000000, 0xef46db3751d8e999},
+	{1, 0x0000000000000000, 0xe934a84adb052768},
+	{2, 0x0000000000000000, 0x5d48cd60a77e23ff},
+	{3, 0x0000000000000000, 0xff7e1959cb50794a},
+	{4, 0x0000000000000000, 0x9136a0dca57457ee},
+	{5, 0x0000000000000000, 0x9b046fb1397f09a5},
+	{7, 0x0000000000000000, 0x6c83909a9f01ed25},
+	{8, 0x0000000000000000, 0xcdbcf538e71d1348},
+	{9, 0x0000000000000000, 0x554b1ae991eda6b6},
+	{14, 0x0000000000000000, 0x8282dcc4994e35c8},
+	{15, 0x0000000000000000, 0x180719316d622d84},
+	{16, 0x0000000000000000, 0x98c90b57fdfcb55c},
+	{17, 0x0000000000000000, 0x0d39a2d051a30c2c},
+	{31, 0x0000000000000000, 0x299b39a290e6d783},
+	{32, 0x0000000000000000, 0x18b216492bb44b70},
+	{33, 0x0000000000000000, 0x55c8dc3e578f5b59},
+	{63, 0x0000000000000000, 0xa9efbe0fa0f3f4e7},
+	{64, 0x0000000000000000, 0xef558f8acac2b5cd},
+	{65, 0x0000000000000000, 0xde0f20dc2631af7a},
+	{100, 0x0000000000000000, 0x4bfe019cd91d9ea4},
+	{222, 0x0000000000000000, 0xb641ae8cb691c174},
+	{1024, 0x0000000000000000, 0x4775bf7cace4d177},
+	{2367, 0x0000000000000000, 0xa82418ddec0ea581},
+	{0, 0x000000009e3779b1, 0xac75fda2929b17ef},
+	{1, 0x000000009e3779b1, 0x5014607643a9b4c3},
+	{2, 0x000000009e3779b1, 0x9e93152232d54a39},
+	{3, 0x000000009e3779b1, 0xaa8584e83660f7d1},
+	{4, 0x000000009e3779b1, 0xcaab286bd8e9fdb5},
+	{5, 0x000000009e3779b1, 0x2af5249930f984ec},
+	{7, 0x000000009e3779b1, 0xf98d03b1ad6f9293},
+	{8, 0x000000009e3779b1, 0xfe0c047a5353cdac},
+	{9, 0x000000009e3779b1, 0x7908265248f6d73f},
+	{14, 0x000000009e3779b1, 0xc3bd6bf63deb6df0},
+	{15, 0x000000009e3779b1, 0xd61105c20e91f99f},
+	{16, 0x000000009e3779b1, 0xc900ad2d536b607e},
+	{17, 0x000000009e3779b1, 0x495cd68a647c7a22},
+	{31, 0x000000009e3779b1, 0xda673d5feb5c1d79},
+	{32, 0x000000009e3779b1, 0xb3f33bdf93ade409},
+	{33, 0x000000009e3779b1, 0xe92c292f64bc3071},
+	{63, 0x000000009e3779b1, 0x6c911fadb05b6fc2},
+	{64, 0x000000009e3779b1, 0xb5eeba99264cc44f},
+	{65, 0x000000009e3779b1, 0xd3f6ff3941e310ca},
+	{100, 0x000000009e3779b1, 0x4853706dc9625cae},
+	{222, 0x000000009e3779b1, 0x20cb8ab7ae10c14a},
+	{1024, 0x000000009e3779b1, 0x238cf9296898b465},
+	{2367, 0x000000009e3779b1, 0xa36a93c18052673a},
+	{0, 0x9e3779b185ebca8d, 0x0b303d920ec349df},
+	{1, 0x9e3779b185ebca8d, 0x9c6678669fcd2e6d},
+	{2, 0x9e3779b185ebca8d, 0x8469cbf08335c09c},
+	{3, 0x9e3779b185ebca8d, 0x281b7cbb86cc6a05},
+	{4, 0x9e3779b185ebca8d, 0xccfe4ead7e01983c},
+	{5, 0x9e3779b185ebca8d, 0x9099058d286ef837},
+	{7, 0x9e3779b185ebca8d, 0x3c18df70e6ef9d24},
+	{8, 0x9e3779b185ebca8d, 0x768161b4e5a58dfa},
+	{9, 0x9e3779b185ebca8d, 0x6a7ef24927b938a0},
+	{14, 0x9e3779b185ebca8d, 0x12dcd5db160cc92b},
+	{15, 0x9e3779b185ebca8d, 0xac31ee102e5cf442},
+	{16, 0x9e3779b185ebca8d, 0x85446bba49cb7df1},
+	{17, 0x9e3779b185ebca8d, 0x1dd902d73122eda0},
+	{31, 0x9e3779b185ebca8d, 0x51aaf1a336575f00},
+	{32, 0x9e3779b185ebca8d, 0x21d817283f4b6283},
+	{33, 0x9e3779b185ebca8d, 0xb09782549294df85},
+	{63, 0x9e3779b185ebca8d, 0x34c3933dc0040446},
+	{64, 0x9e3779b185ebca8d, 0xf90d26fed8023d61},
+	{65, 0x9e3779b185ebca8d, 0x7814174cd6405bee},
+	{100, 0x9e3779b185ebca8d, 0x38f1d4aabfd12d0f},
+	{222, 0x9e3779b185ebca8d, 0xccb064ac93ebb562},
+	{1024, 0x9e3779b185ebca8d, 0xcfbc5e785ff33ccd},
+	{2367, 0x9e3779b185ebca8d, 0x363b532c35e01e25},
+}
@@ -0,0 +1,50 @@
+package testvectors_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestXXH32(t *testing.T) {
+	for _, v := range testvectors.XXH32 {
+		data := testvectors.Input(v.Len)
+		if h := xxHash32.Checksum(data, v.Seed); h != v.Sum {
+			t.Errorf("len %d seed 0x%x: got 0x%08x expected 0x%08x", v.Len, v.Seed, h, v.Sum)
+		}
+		xxh := xxHash32.New(v.Seed)
+		for i := range data {
+			xxh.Write(data[i : i+1])
+		}
+		if h := xxh.Sum32(); h != v.Sum {
+			t.Errorf("len %d seed 0x%x: got 0x%08x expected 0x%08x when streaming", v.Len, v.Seed, h, v.Sum)
+		}
+	}
+}
+
+func TestXXH64(t *testing.T) {
+	for _, v := range testvectors.XXH64 {
+		data := testvectors.Input(v.Len)
+		if h := xxHash64.Checksum(data, v.Seed); h != v.Sum {
+			t.Errorf("len %d seed 0x%x: got 0x%016x expected 0x%016x", v.Len, v.Seed, h, v.Sum)
+		}
+		xxh := xxHash64.New(v.Seed)
+		for i := range data {
+			xxh.Write(data[i : i+1])
+		}
+		if h := xxh.Sum64(); h != v.Sum {
+			t.Errorf("len %d seed 0x%x: got 0x%016x expected 0x%016x when streaming", v.Len, v.Seed, h, v.Sum)
+		}
+	}
+}
+
+func TestInput(t *testing.T) {
+	if a, b := testvectors.Input(10), testvectors.Input(testvectors.MaxLen); string(a) != string(b[:10]) {
+		t.Errorf("got %x expected a prefix of %x", a, b[:10])
+	}
+	if n := testvectors.XXH64[len(testvectors.XXH64)-1].Len; n != testvectors.MaxLen {
+		t.Errorf("got longest input %d expected %d", n, testvectors.MaxLen)
+	}
+}
@@ -0,0 +1,31 @@
+package xxHash32_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash32"
+)
+
+// TestAllocs guards the hot paths against allocations.
+func TestAllocs(t *testing.T) {
+	data := []byte(strings.Repeat("xxHash", 200))
+	s := string(data)
+	xxh := xxHash32.New(1)
+	sum := make([]byte, 0, xxh.Size())
+	for name, f := range map[string]func(){
+		"Checksum":       func() { xxHash32.Checksum(data, 1) },
+		"ChecksumString": func() { xxHash32.ChecksumString(s, 1) },
+		"Write": func() {
+			xxh.Reset()
+			xxh.Write(data[:10])
+			xxh.Write(data[10:])
+		},
+		"Sum32": func() { xxh.Sum32() },
+		"Sum":   func() { xxh.Sum(sum[:0]) },
+	} {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("%s: got %f allocations", name, n)
+		}
+	}
+}
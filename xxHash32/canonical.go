@@ -0,0 +1,25 @@
+package xxHash32
+
+import "encoding/hex"
+
+// Canonical32 is the canonical representation of a 32bits Hash value:
+// its big endian encoding, as used by the reference implementation and its tools.
+type Canonical32 [4]byte
+
+// FromHash sets c to the canonical representation of h.
+func (c *Canonical32) FromHash(h uint32) {
+	c[0] = byte(h >> 24)
+	c[1] = byte(h >> 16)
+	c[2] = byte(h >> 8)
+	c[3] = byte(h)
+}
+
+// Hash returns the 32bits Hash value represented by c.
+func (c Canonical32) Hash() uint32 {
+	return uint32(c[0])<<24 | uint32(c[1])<<16 | uint32(c[2])<<8 | uint32(c[3])
+}
+
+// String returns the lower case hexadecimal encoding of c, as printed by xxhsum.
+func (c Canonical32) String() string {
+	return hex.EncodeToString(c[:])
+}
@@ -0,0 +1,22 @@
+package xxHash32_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash32"
+)
+
+func TestCanonical32(t *testing.T) {
+	// xxh32sum of "abc" prints 32d153ff.
+	var c xxHash32.Canonical32
+	c.FromHash(xxHash32.Checksum([]byte("abc"), 0))
+	if want := (xxHash32.Canonical32{0x32, 0xd1, 0x53, 0xff}); c != want {
+		t.Errorf("got %v expected %v", c, want)
+	}
+	if s := c.String(); s != "32d153ff" {
+		t.Errorf("got %s expected 32d153ff", s)
+	}
+	if h := c.Hash(); h != 0x32d153ff {
+		t.Errorf("got 0x%x expected 0x32d153ff", h)
+	}
+}
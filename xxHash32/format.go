@@ -0,0 +1,22 @@
+package xxHash32
+
+import (
+	"fmt"
+
+	"github.com/pierrec/xxHash/internal/xxhfmt"
+)
+
+// Format implements the fmt.Formatter interface so that c is printed in its canonical form,
+// 8 hexadecimal digits, with the %v, %s, %x and %X verbs.
+// The # flag adds the 0x prefix and %d prints the decimal value.
+func (c Canonical32) Format(f fmt.State, verb rune) {
+	xxhfmt.Format(f, verb, "xxHash32", c.String(), uint64(c.Hash()))
+}
+
+// Format implements the fmt.Formatter interface so that the digest is printed
+// as its current hash value, as for Canonical32.
+func (xxh *xxHash) Format(f fmt.State, verb rune) {
+	var c Canonical32
+	c.FromHash(xxh.Sum32())
+	c.Format(f, verb)
+}
@@ -0,0 +1,45 @@
+package xxHash32_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash32"
+)
+
+func TestFormat(t *testing.T) {
+	var c xxHash32.Canonical32
+	c.FromHash(0xabc)
+	for _, tc := range []struct {
+		format, want string
+	}{
+		{"%v", "00000abc"},
+		{"%x", "00000abc"},
+		{"%X", "00000ABC"},
+		{"%#x", "0x00000abc"},
+		{"%d", "2748"},
+		{"%10s", "  00000abc"},
+		{"%t", "%!t(xxHash32=00000abc)"},
+	} {
+		if got := fmt.Sprintf(tc.format, c); got != tc.want {
+			t.Errorf("%s: got %q expected %q", tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	xxh := xxHash32.New(0)
+	xxh.Write([]byte("abc"))
+	var c xxHash32.Canonical32
+	c.FromHash(xxHash32.Checksum([]byte("abc"), 0))
+	for _, tc := range []struct {
+		format, want string
+	}{
+		{"%v", c.String()},
+		{"%#x", "0x" + c.String()},
+	} {
+		if got := fmt.Sprintf(tc.format, xxh); got != tc.want {
+			t.Errorf("%s: got %q expected %q", tc.format, got, tc.want)
+		}
+	}
+}
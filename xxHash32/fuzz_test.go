@@ -0,0 +1,35 @@
+package xxHash32_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash32"
+)
+
+// FuzzXXH32 checks that hashing data with Writes split at arbitrary points
+// always gives the same result as Checksum.
+// Each byte of splits is the length of a Write, the rest of the data being written last.
+func FuzzXXH32(f *testing.F) {
+	f.Add([]byte(""), []byte{}, uint32(0))
+	f.Add([]byte("abc"), []byte{1, 0, 1}, uint32(1))
+	f.Add(testvectors.Input(100), []byte{31, 1, 32, 3}, uint32(0xCAFE))
+	f.Add(testvectors.Input(1000), []byte{255, 16, 64, 200}, uint32(2654435761))
+	f.Fuzz(func(t *testing.T, data, splits []byte, seed uint32) {
+		want := xxHash32.Checksum(data, seed)
+		xxh := xxHash32.New(seed)
+		rest := data
+		for _, s := range splits {
+			n := int(s)
+			if n > len(rest) {
+				n = len(rest)
+			}
+			xxh.Write(rest[:n])
+			rest = rest[n:]
+		}
+		xxh.Write(rest)
+		if h := xxh.Sum32(); h != want {
+			t.Errorf("splits %v: got 0x%x expected 0x%x", splits, h, want)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package xxHash32_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash32"
+)
+
+// TestProperties checks that random sequences of Write, Sum and Reset calls
+// always agree with Checksum over the data written since the last Reset.
+func TestProperties(t *testing.T) {
+	data := make([]byte, 1<<12)
+	for i := 0; i < 200; i++ {
+		rnd := rand.New(rand.NewSource(int64(i)))
+		rnd.Read(data)
+		seed := uint32(rnd.Uint64())
+		xxh := xxHash32.New(seed)
+		var written []byte
+		var ops []string
+		for op := 0; op < 100; op++ {
+			switch r := rnd.Intn(10); {
+			case r < 6:
+				// Mostly short writes to hit every buffer state, sometimes longer ones.
+				n := rnd.Intn(70)
+				if r == 0 {
+					n = rnd.Intn(len(data))
+				}
+				xxh.Write(data[:n])
+				written = append(written, data[:n]...)
+				ops = append(ops, "write")
+			case r < 9:
+				ops = append(ops, "sum")
+				if h, want := xxh.Sum32(), xxHash32.Checksum(written, seed); h != want {
+					t.Fatalf("seed %d after %v: got 0x%x expected 0x%x", i, ops, h, want)
+				}
+			default:
+				xxh.Reset()
+				written = written[:0]
+				ops = append(ops, "reset")
+			}
+		}
+	}
+}
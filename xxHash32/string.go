@@ -0,0 +1,9 @@
+package xxHash32
+
+import "unsafe"
+
+// ChecksumString returns the 32bits Hash value of s.
+// It does not copy s.
+func ChecksumString(s string, seed uint32) uint32 {
+	return Checksum(unsafe.Slice(unsafe.StringData(s), len(s)), seed)
+}
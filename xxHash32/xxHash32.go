@@ -32,7 +32,7 @@ func New(seed uint32) hash.Hash32 {
 
 // Sum appends the current hash to b and returns the resulting slice.
 // It does not change the underlying hash state.
-func (xxh xxHash) Sum(b []byte) []byte {
+func (xxh *xxHash) Sum(b []byte) []byte {
 	h32 := xxh.Sum32()
 	return append(b, byte(h32), byte(h32>>8), byte(h32>>16), byte(h32>>24))
 }
@@ -65,6 +65,20 @@ func (xxh *xxHash) Write(input []byte) (int, error) {
 
 	xxh.totalLen += uint64(n)
 
+	if m == 0 && n&15 == 0 {
+		// Block aligned input: hash it in place, with no copy into the buffer.
+		v1, v2, v3, v4 := xxh.v1, xxh.v2, xxh.v3, xxh.v4
+		for n, p := n-16, 0; p <= n; p += 16 {
+			b := (*[16]byte)(input[p:]) // no bounds checks in the loop
+			v1 = rol13(v1+u32(b[:4])*prime32_2) * prime32_1
+			v2 = rol13(v2+u32(b[4:8])*prime32_2) * prime32_1
+			v3 = rol13(v3+u32(b[8:12])*prime32_2) * prime32_1
+			v4 = rol13(v4+u32(b[12:])*prime32_2) * prime32_1
+		}
+		xxh.v1, xxh.v2, xxh.v3, xxh.v4 = v1, v2, v3, v4
+		return n, nil
+	}
+
 	r := len(xxh.buf) - m
 	if n < r {
 		copy(xxh.buf[m:], input)
@@ -72,34 +86,31 @@ func (xxh *xxHash) Write(input []byte) (int, error) {
 		return n, nil
 	}
 
-	p := 0
 	if m > 0 {
 		// some data left from previous update
-		copy(xxh.buf[xxh.bufused:], input[:r])
-		xxh.bufused += len(input) - r
+		copy(xxh.buf[m:], input[:r])
+		input = input[r:]
 
 		// fast rotl(13)
 		xxh.v1 = rol13(xxh.v1+u32(xxh.buf[:])*prime32_2) * prime32_1
 		xxh.v2 = rol13(xxh.v2+u32(xxh.buf[4:])*prime32_2) * prime32_1
 		xxh.v3 = rol13(xxh.v3+u32(xxh.buf[8:])*prime32_2) * prime32_1
 		xxh.v4 = rol13(xxh.v4+u32(xxh.buf[12:])*prime32_2) * prime32_1
-		p = r
-		xxh.bufused = 0
 	}
 
 	// Causes compiler to work directly from registers instead of stack:
 	v1, v2, v3, v4 := xxh.v1, xxh.v2, xxh.v3, xxh.v4
-	for n := n - 16; p <= n; p += 16 {
-		sub := input[p:][:16] //BCE hint for compiler
-		v1 = rol13(v1+u32(sub[:])*prime32_2) * prime32_1
-		v2 = rol13(v2+u32(sub[4:])*prime32_2) * prime32_1
-		v3 = rol13(v3+u32(sub[8:])*prime32_2) * prime32_1
-		v4 = rol13(v4+u32(sub[12:])*prime32_2) * prime32_1
+	p := 0
+	for n := len(input) - 16; p <= n; p += 16 {
+		b := (*[16]byte)(input[p:]) // no bounds checks in the loop
+		v1 = rol13(v1+u32(b[:4])*prime32_2) * prime32_1
+		v2 = rol13(v2+u32(b[4:8])*prime32_2) * prime32_1
+		v3 = rol13(v3+u32(b[8:12])*prime32_2) * prime32_1
+		v4 = rol13(v4+u32(b[12:])*prime32_2) * prime32_1
 	}
 	xxh.v1, xxh.v2, xxh.v3, xxh.v4 = v1, v2, v3, v4
 
-	copy(xxh.buf[xxh.bufused:], input[p:])
-	xxh.bufused += len(input) - p
+	xxh.bufused = copy(xxh.buf[:], input[p:])
 
 	return n, nil
 }
@@ -147,11 +158,11 @@ func Checksum(input []byte, seed uint32) uint32 {
 		v4 := seed - prime32_1
 		p := 0
 		for n := n - 16; p <= n; p += 16 {
-			sub := input[p:][:16] //BCE hint for compiler
-			v1 = rol13(v1+u32(sub[:])*prime32_2) * prime32_1
-			v2 = rol13(v2+u32(sub[4:])*prime32_2) * prime32_1
-			v3 = rol13(v3+u32(sub[8:])*prime32_2) * prime32_1
-			v4 = rol13(v4+u32(sub[12:])*prime32_2) * prime32_1
+			b := (*[16]byte)(input[p:]) // no bounds checks in the loop
+			v1 = rol13(v1+u32(b[:4])*prime32_2) * prime32_1
+			v2 = rol13(v2+u32(b[4:8])*prime32_2) * prime32_1
+			v3 = rol13(v3+u32(b[8:12])*prime32_2) * prime32_1
+			v4 = rol13(v4+u32(b[12:])*prime32_2) * prime32_1
 		}
 		input = input[p:]
 		n -= p
@@ -6,6 +6,7 @@ import (
 	"hash/fnv"
 	"testing"
 
+	"github.com/pierrec/xxHash/testvectors"
 	"github.com/pierrec/xxHash/xxHash32"
 )
 
@@ -69,6 +70,10 @@ func TestData(t *testing.T) {
 			t.Errorf("test %d: xxh32(%s)=0x%x expected 0x%x", i, td.printable, h, td.sum)
 			t.FailNow()
 		}
+		if h := xxHash32.ChecksumString(td.data, 0); h != td.sum {
+			t.Errorf("test %d: xxh32(%s)=0x%x expected 0x%x", i, td.printable, h, td.sum)
+			t.FailNow()
+		}
 	}
 }
 
@@ -100,6 +105,24 @@ func TestSum(t *testing.T) {
 	}
 }
 
+func TestWriteAligned(t *testing.T) {
+	data := testvectors.Input(testvectors.MaxLen)
+	for _, split := range []int{0, 1, 15, 16} {
+		for _, size := range []int{16, 32, 128} {
+			h := xxHash32.New(7)
+			h.Write(data[:split])
+			p := split
+			for ; p+size <= len(data); p += size {
+				h.Write(data[p : p+size])
+			}
+			h.Write(data[p:])
+			if got, want := h.Sum32(), xxHash32.Checksum(data, 7); got != want {
+				t.Errorf("split %d, size %d: got %x expected %x", split, size, got, want)
+			}
+		}
+	}
+}
+
 func TestReset(t *testing.T) {
 	xxh := xxHash32.New(0)
 	for i, td := range testdata {
@@ -148,3 +171,12 @@ func Benchmark_Fnv32(b *testing.B) {
 		h.Reset()
 	}
 }
+
+func Benchmark_XXH32_WriteAligned(b *testing.B) {
+	data := make([]byte, 4<<10)
+	h := xxHash32.New(0)
+	b.SetBytes(int64(len(data)))
+	for n := 0; n < b.N; n++ {
+		h.Write(data)
+	}
+}
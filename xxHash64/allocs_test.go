@@ -0,0 +1,36 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// TestAllocs guards the hot paths against allocations.
+func TestAllocs(t *testing.T) {
+	data := testvectors.Input(1000)
+	s := string(data)
+	xxh := xxHash64.New(1)
+	sum := make([]byte, 0, xxh.Size())
+	var d xxHash64.Digest
+	for name, f := range map[string]func(){
+		"Checksum":       func() { xxHash64.Checksum(data, 1) },
+		"ChecksumString": func() { xxHash64.ChecksumString(s, 1) },
+		"Write": func() {
+			xxh.Reset()
+			xxh.Write(data[:10])
+			xxh.Write(data[10:])
+		},
+		"NewInto": func() {
+			xxHash64.NewInto(&d, 1).Write(data)
+			d.Sum64()
+		},
+		"Sum64": func() { xxh.Sum64() },
+		"Sum":   func() { xxh.Sum(sum[:0]) },
+	} {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("%s: got %f allocations", name, n)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package xxHash64
+
+import "sync"
+
+// Background is a Hash64 whose input is hashed on a dedicated goroutine,
+// so that the caller can overlap IO and hashing.
+//
+// Write enqueues a copy of its input and WriteOwned takes ownership of it,
+// both only blocking when the queue is full.
+// Sum, Sum64 and Reset wait for the queue to be drained.
+// Close must be called to release the hashing goroutine.
+//
+// As for the other digests, a Background must not be used concurrently.
+type Background struct {
+	xxh     Digest
+	queue   chan []byte
+	pending sync.WaitGroup
+}
+
+// NewBackground returns a new Background instance queueing up to depth buffers.
+func NewBackground(seed uint64, depth int) *Background {
+	if depth < 0 {
+		depth = 0
+	}
+	b := &Background{
+		xxh:   Digest{seed: seed},
+		queue: make(chan []byte, depth),
+	}
+	b.xxh.Reset()
+	go b.run()
+	return b
+}
+
+func (b *Background) run() {
+	for buf := range b.queue {
+		b.xxh.Write(buf)
+		b.pending.Done()
+	}
+}
+
+// Close waits for the queue to be drained and stops the hashing goroutine.
+// The Background must not be written to after Close.
+func (b *Background) Close() error {
+	b.pending.Wait()
+	close(b.queue)
+	return nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (b *Background) Sum(in []byte) []byte {
+	b.pending.Wait()
+	return b.xxh.Sum(in)
+}
+
+// Sum64 returns the 64bits Hash value.
+func (b *Background) Sum64() uint64 {
+	b.pending.Wait()
+	return b.xxh.Sum64()
+}
+
+// Reset resets the Hash to its initial state.
+func (b *Background) Reset() {
+	b.pending.Wait()
+	b.xxh.Reset()
+}
+
+// Size returns the number of bytes returned by Sum().
+func (b *Background) Size() int {
+	return 8
+}
+
+// BlockSize gives the minimum number of bytes accepted by Write().
+func (b *Background) BlockSize() int {
+	return 1
+}
+
+// Write enqueues a copy of input bytes to be added to the Hash.
+// It never returns an error.
+func (b *Background) Write(input []byte) (int, error) {
+	if len(input) == 0 {
+		return 0, nil
+	}
+	return b.WriteOwned(append([]byte(nil), input...))
+}
+
+// WriteOwned enqueues input bytes to be added to the Hash without copying them.
+// The caller must not modify input afterwards.
+// It never returns an error.
+func (b *Background) WriteOwned(input []byte) (int, error) {
+	if len(input) == 0 {
+		return 0, nil
+	}
+	b.pending.Add(1)
+	b.queue <- input
+	return len(input), nil
+}
@@ -0,0 +1,43 @@
+package xxHash64_test
+
+import (
+	"hash"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var _ hash.Hash64 = (*xxHash64.Background)(nil)
+
+func TestBackground(t *testing.T) {
+	const seed = 0xCAFE
+	data := testvectors.Input(10000)
+
+	b := xxHash64.NewBackground(seed, 4)
+	defer b.Close()
+	for i := 0; i < 2; i++ {
+		buf := make([]byte, 0, 37)
+		for p := 0; p < len(data); p += 37 {
+			end := p + 37
+			if end > len(data) {
+				end = len(data)
+			}
+			// Reusing the buffer checks that Write copies its input.
+			buf = append(buf[:0], data[p:end]...)
+			b.Write(buf)
+		}
+		if got, want := b.Sum64(), xxHash64.Checksum(data, seed); got != want {
+			t.Errorf("got 0x%x expected 0x%x", got, want)
+		}
+		b.Reset()
+	}
+
+	b.WriteOwned(append([]byte(nil), data...))
+	if got, want := b.Sum64(), xxHash64.Checksum(data, seed); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := b.Sum64(), xxHash64.Checksum(data, seed); got != want {
+		t.Errorf("second Sum64: got 0x%x expected 0x%x", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package xxHash64
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+// ErrBase64 is returned when parsing an invalid base64 hash value.
+var ErrBase64 = errors.New("xxHash64: invalid base64 hash")
+
+// Base64 returns the unpadded URL safe base64 encoding of the canonical form of h, 11 characters long.
+func (h Hash64) Base64() string {
+	var c Canonical64
+	c.FromHash(uint64(h))
+	return base64.RawURLEncoding.EncodeToString(c[:])
+}
+
+// ChecksumBase64 returns the 64bits Hash value of data as its unpadded URL safe base64 encoding.
+func ChecksumBase64(data []byte, seed uint64) string {
+	return Hash64(Checksum(data, seed)).Base64()
+}
+
+// ParseBase64 parses a hash value encoded by Hash64.Base64.
+func ParseBase64(s string) (uint64, error) {
+	var c Canonical64
+	if base64.RawURLEncoding.EncodedLen(len(c)) != len(s) {
+		return 0, ErrBase64
+	}
+	if _, err := base64.RawURLEncoding.Strict().Decode(c[:], []byte(s)); err != nil {
+		return 0, ErrBase64
+	}
+	return c.Hash(), nil
+}
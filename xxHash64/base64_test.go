@@ -0,0 +1,31 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestBase64(t *testing.T) {
+	for _, h := range []uint64{0, 1, 0xfedcba9876543210, 1<<64 - 1} {
+		s := xxHash64.Hash64(h).Base64()
+		if len(s) != 11 {
+			t.Errorf("0x%x: got %q", h, s)
+		}
+		v, err := xxHash64.ParseBase64(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != h {
+			t.Errorf("got 0x%x expected 0x%x", v, h)
+		}
+	}
+	if s := xxHash64.ChecksumBase64([]byte("abc"), 0); s != "RLws9a13CZk" {
+		t.Errorf("got %s expected RLws9a13CZk", s)
+	}
+	for _, s := range []string{"", "RLws9a13CZk=", "RLws9a13CZ", "RLws9a13CZ+", "RLws9a13CZl"} {
+		if _, err := xxHash64.ParseBase64(s); err != xxHash64.ErrBase64 {
+			t.Errorf("%q: got error %v expected %v", s, err, xxHash64.ErrBase64)
+		}
+	}
+}
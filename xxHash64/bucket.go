@@ -0,0 +1,26 @@
+package xxHash64
+
+import "math/bits"
+
+// Bucket returns the experiment bucket of key among n, in [0, n).
+// It panics if n is not positive.
+//
+// The salt separates experiments: a key is assigned independent buckets
+// by experiments using different salts, and always the same bucket by a given experiment.
+// The Checksum of key seeded with salt is reduced with a multiply and shift,
+// whose bias is at most n / 2^64 and negligible for any practical n.
+func Bucket(key []byte, n int, salt uint64) int {
+	if n <= 0 {
+		panic("xxHash64: invalid number of buckets")
+	}
+	hi, _ := bits.Mul64(Checksum(key, salt), uint64(n))
+	return int(hi)
+}
+
+// Fraction returns a stable value for key in [0, 1), uniformly distributed over keys.
+// It is derived from the 53 most significant bits of the Checksum of key seeded with salt,
+// so that Fraction(key, salt) < rate selects the same keys as Sample(key, rate, salt)
+// for rates that are multiples of 2^-53.
+func Fraction(key []byte, salt uint64) float64 {
+	return float64(Checksum(key, salt)>>11) / (1 << 53)
+}
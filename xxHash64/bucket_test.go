@@ -0,0 +1,67 @@
+package xxHash64_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestBucket(t *testing.T) {
+	const keys = 100000
+	for _, n := range []int{1, 2, 10, 33} {
+		counts := make([]int, n)
+		var moved int
+		for i := 0; i < keys; i++ {
+			key := []byte(fmt.Sprint(i))
+			b := xxHash64.Bucket(key, n, 1)
+			if b < 0 || b >= n {
+				t.Fatalf("n=%d: got bucket %d", n, b)
+			}
+			if b2 := xxHash64.Bucket(key, n, 1); b2 != b {
+				t.Fatalf("n=%d: got buckets %d and %d for the same key", n, b, b2)
+			}
+			if xxHash64.Bucket(key, n, 2) != b {
+				moved++
+			}
+			counts[b]++
+		}
+		for b, c := range counts {
+			if want := keys / n; c < want*9/10 || c > want*11/10 {
+				t.Errorf("n=%d: bucket %d got %d keys expected about %d", n, b, c, want)
+			}
+		}
+		// Salts are independent: a key changes bucket with probability 1-1/n.
+		if want := keys - keys/n; moved < want*9/10 || moved > want*11/10+1 {
+			t.Errorf("n=%d: %d keys changed bucket with another salt, expected about %d", n, moved, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	xxHash64.Bucket(nil, 0, 0)
+}
+
+func TestFraction(t *testing.T) {
+	const keys = 100000
+	var below int
+	for i := 0; i < keys; i++ {
+		key := []byte(fmt.Sprint(i))
+		f := xxHash64.Fraction(key, 5)
+		if f < 0 || f >= 1 {
+			t.Fatalf("got fraction %f", f)
+		}
+		if (f < 0.25) != xxHash64.Sample(key, 0.25, 5) {
+			t.Fatalf("key %d: fraction %f and sample at 0.25 disagree", i, f)
+		}
+		if f < 0.25 {
+			below++
+		}
+	}
+	if got := float64(below) / keys; got < 0.24 || got > 0.26 {
+		t.Errorf("got %f of the keys below 0.25", got)
+	}
+}
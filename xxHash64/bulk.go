@@ -0,0 +1,47 @@
+package xxHash64
+
+import "unsafe"
+
+// Bulk hashes many independent inputs with the same seed, such as packets or keys,
+// the initial state derived from the seed being computed once.
+// Its methods return the same values as Checksum and do not allocate.
+//
+// A Bulk is never modified once created and is safe for concurrent use.
+type Bulk struct {
+	seed           uint64
+	v1, v2, v3, v4 uint64
+}
+
+// NewBulk returns a Bulk hashing with seed.
+func NewBulk(seed uint64) *Bulk {
+	return &Bulk{
+		seed: seed,
+		v1:   seed + prime64_1 + prime64_2,
+		v2:   seed + prime64_2,
+		v3:   seed,
+		v4:   seed - prime64_1,
+	}
+}
+
+// Checksum returns the 64bits Hash value of input.
+func (b *Bulk) Checksum(input []byte) uint64 {
+	if len(input) < 32 {
+		// Short inputs only use the seed.
+		return Checksum(input, b.seed)
+	}
+	return checksumLong(input, b.v1, b.v2, b.v3, b.v4)
+}
+
+// ChecksumString returns the 64bits Hash value of s.
+// It does not copy s.
+func (b *Bulk) ChecksumString(s string) uint64 {
+	return b.Checksum(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// AppendChecksums appends the 64bits Hash values of inputs to dst, in order.
+func (b *Bulk) AppendChecksums(dst []uint64, inputs [][]byte) []uint64 {
+	for _, in := range inputs {
+		dst = append(dst, b.Checksum(in))
+	}
+	return dst
+}
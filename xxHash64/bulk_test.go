@@ -0,0 +1,48 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestBulk(t *testing.T) {
+	const seed = 0xCAFE
+	data := testvectors.Input(1000)
+	b := xxHash64.NewBulk(seed)
+	var inputs [][]byte
+	for _, n := range []int{0, 1, 4, 8, 31, 32, 33, 64, 100, 1000} {
+		in := data[:n]
+		inputs = append(inputs, in)
+		want := xxHash64.Checksum(in, seed)
+		if got := b.Checksum(in); got != want {
+			t.Errorf("size %d: got %x expected %x", n, got, want)
+		}
+		if got := b.ChecksumString(string(in)); got != want {
+			t.Errorf("size %d: got %x from string expected %x", n, got, want)
+		}
+	}
+
+	sums := b.AppendChecksums([]uint64{42}, inputs)
+	if len(sums) != len(inputs)+1 || sums[0] != 42 {
+		t.Fatalf("got %v", sums)
+	}
+	for i, in := range inputs {
+		if want := xxHash64.Checksum(in, seed); sums[i+1] != want {
+			t.Errorf("input %d: got %x expected %x", i, sums[i+1], want)
+		}
+	}
+
+	if n := testing.AllocsPerRun(100, func() { b.Checksum(data) }); n != 0 {
+		t.Errorf("got %f allocations", n)
+	}
+}
+
+func Benchmark_XXH64_Bulk(b *testing.B) {
+	bulk := xxHash64.NewBulk(0)
+	b.SetBytes(int64(len(testdata1)))
+	for n := 0; n < b.N; n++ {
+		bulk.Checksum(testdata1)
+	}
+}
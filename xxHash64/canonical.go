@@ -0,0 +1,30 @@
+package xxHash64
+
+import "encoding/hex"
+
+// Canonical64 is the canonical representation of a 64bits Hash value:
+// its big endian encoding, as used by the reference implementation and its tools.
+type Canonical64 [8]byte
+
+// FromHash sets c to the canonical representation of h.
+func (c *Canonical64) FromHash(h uint64) {
+	c[0] = byte(h >> 56)
+	c[1] = byte(h >> 48)
+	c[2] = byte(h >> 40)
+	c[3] = byte(h >> 32)
+	c[4] = byte(h >> 24)
+	c[5] = byte(h >> 16)
+	c[6] = byte(h >> 8)
+	c[7] = byte(h)
+}
+
+// Hash returns the 64bits Hash value represented by c.
+func (c Canonical64) Hash() uint64 {
+	return uint64(c[0])<<56 | uint64(c[1])<<48 | uint64(c[2])<<40 | uint64(c[3])<<32 |
+		uint64(c[4])<<24 | uint64(c[5])<<16 | uint64(c[6])<<8 | uint64(c[7])
+}
+
+// String returns the lower case hexadecimal encoding of c, as printed by xxhsum.
+func (c Canonical64) String() string {
+	return hex.EncodeToString(c[:])
+}
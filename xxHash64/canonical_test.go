@@ -0,0 +1,22 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestCanonical64(t *testing.T) {
+	// xxh64sum of "abc" prints 44bc2cf5ad770999.
+	var c xxHash64.Canonical64
+	c.FromHash(xxHash64.Checksum([]byte("abc"), 0))
+	if want := (xxHash64.Canonical64{0x44, 0xbc, 0x2c, 0xf5, 0xad, 0x77, 0x09, 0x99}); c != want {
+		t.Errorf("got %v expected %v", c, want)
+	}
+	if s := c.String(); s != "44bc2cf5ad770999" {
+		t.Errorf("got %s expected 44bc2cf5ad770999", s)
+	}
+	if h := c.Hash(); h != 0x44bc2cf5ad770999 {
+		t.Errorf("got 0x%x expected 0x44bc2cf5ad770999", h)
+	}
+}
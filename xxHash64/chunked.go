@@ -0,0 +1,108 @@
+package xxHash64
+
+// Chunked is a Hash64 that also records the 64bits Hash value of every chunk
+// of its input, so that the chunks can later be verified or transferred individually.
+//
+// The hash of a chunk is Checksum(chunk, seed).
+type Chunked struct {
+	xxh       Digest
+	chunk     Digest
+	chunkSize int
+	chunkLen  int
+	chunks    []uint64
+}
+
+// NewChunked returns a new Chunked instance recording the hashes of chunkSize bytes chunks.
+// It panics if chunkSize is not positive.
+func NewChunked(seed uint64, chunkSize int) *Chunked {
+	if chunkSize <= 0 {
+		panic("xxHash64: invalid chunk size")
+	}
+	c := &Chunked{
+		xxh:       Digest{seed: seed},
+		chunk:     Digest{seed: seed},
+		chunkSize: chunkSize,
+	}
+	c.Reset()
+	return c
+}
+
+// ChunkSize returns the size of the chunks.
+func (c *Chunked) ChunkSize() int {
+	return c.chunkSize
+}
+
+// Sum appends the current hash of the whole input to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (c *Chunked) Sum(b []byte) []byte {
+	return c.xxh.Sum(b)
+}
+
+// Sum64 returns the 64bits Hash value of the whole input.
+func (c *Chunked) Sum64() uint64 {
+	return c.xxh.Sum64()
+}
+
+// TreeSum64 returns the tree mode 64bits Hash value of the input, as defined by ParallelChecksum.
+// It is equal to ParallelChecksum over the same input if the chunk size is ParallelChunkSize.
+func (c *Chunked) TreeSum64() uint64 {
+	chunks := c.Chunks()
+	if len(chunks) <= 1 {
+		return c.Sum64()
+	}
+	return treeRoot(chunks, c.xxh.seed)
+}
+
+// Chunks returns the hashes of the chunks written so far, in order.
+// The last value is the hash of the pending partial chunk, if any.
+func (c *Chunked) Chunks() []uint64 {
+	chunks := make([]uint64, len(c.chunks), len(c.chunks)+1)
+	copy(chunks, c.chunks)
+	if c.chunkLen > 0 {
+		chunks = append(chunks, c.chunk.Sum64())
+	}
+	return chunks
+}
+
+// Reset resets the Hash to its initial state and discards the recorded chunk hashes.
+func (c *Chunked) Reset() {
+	c.xxh.Reset()
+	c.chunk.Reset()
+	c.chunkLen = 0
+	c.chunks = c.chunks[:0]
+}
+
+// Size returns the number of bytes returned by Sum().
+func (c *Chunked) Size() int {
+	return 8
+}
+
+// BlockSize gives the minimum number of bytes accepted by Write().
+func (c *Chunked) BlockSize() int {
+	return 1
+}
+
+// Write adds input bytes to the Hash.
+// It never returns an error.
+func (c *Chunked) Write(input []byte) (int, error) {
+	n := len(input)
+	c.xxh.Write(input)
+
+	for len(input) > 0 {
+		r := c.chunkSize - c.chunkLen
+		if r > len(input) {
+			r = len(input)
+		}
+		c.chunk.Write(input[:r])
+		c.chunkLen += r
+		input = input[r:]
+
+		if c.chunkLen == c.chunkSize {
+			c.chunks = append(c.chunks, c.chunk.Sum64())
+			c.chunk.Reset()
+			c.chunkLen = 0
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package xxHash64_test
+
+import (
+	"hash"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var _ hash.Hash64 = (*xxHash64.Chunked)(nil)
+
+func TestChunked(t *testing.T) {
+	const seed, chunkSize = 0xCAFE, 100
+	data := testvectors.Input(1234)
+
+	c := xxHash64.NewChunked(seed, chunkSize)
+	// Write with a size unrelated to the chunk size.
+	for p := 0; p < len(data); p += 33 {
+		end := p + 33
+		if end > len(data) {
+			end = len(data)
+		}
+		c.Write(data[p:end])
+	}
+
+	if got, want := c.Sum64(), xxHash64.Checksum(data, seed); got != want {
+		t.Errorf("Sum64: got 0x%x expected 0x%x", got, want)
+	}
+	chunks := c.Chunks()
+	if got, want := len(chunks), (len(data)+chunkSize-1)/chunkSize; got != want {
+		t.Fatalf("got %d chunks expected %d", got, want)
+	}
+	for i, h := range chunks {
+		p := i * chunkSize
+		end := p + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if want := xxHash64.Checksum(data[p:end], seed); h != want {
+			t.Errorf("chunk %d: got 0x%x expected 0x%x", i, h, want)
+		}
+	}
+
+	c.Reset()
+	if n := len(c.Chunks()); n != 0 {
+		t.Errorf("got %d chunks after Reset", n)
+	}
+	if got, want := c.Sum64(), xxHash64.Checksum(nil, seed); got != want {
+		t.Errorf("Sum64 after Reset: got 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestChunkedTreeSum64(t *testing.T) {
+	for _, n := range []int{10, xxHash64.ParallelChunkSize, 2*xxHash64.ParallelChunkSize + 10} {
+		data := testvectors.Input(n)
+		c := xxHash64.NewChunked(0, xxHash64.ParallelChunkSize)
+		c.Write(data)
+		if got, want := c.TreeSum64(), xxHash64.ParallelChecksum(data, 0, 0); got != want {
+			t.Errorf("size %d: got 0x%x expected 0x%x", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package xxHash64
+
+// Combine returns the tree mode combination of h1, the hash of a first segment,
+// with h2, the hash of a second segment of len2 bytes following it.
+//
+// The result is the Checksum, with a zero seed, of h1, h2 and len2 each encoded as 8 little endian bytes.
+// It is stable and suitable for merging per-shard digests into an aggregate,
+// but it is NOT the XXH64 of the concatenated segments and the combination is not associative:
+// segments must always be combined in the same order and grouping.
+func Combine(h1, h2 uint64, len2 int) uint64 {
+	var buf [24]byte
+	putU64(buf[:], h1)
+	putU64(buf[8:], h2)
+	putU64(buf[16:], uint64(len2))
+	return Checksum(buf[:], 0)
+}
@@ -0,0 +1,24 @@
+package xxHash64_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestCombine(t *testing.T) {
+	h1 := xxHash64.Checksum([]byte("abc"), 0)
+	h2 := xxHash64.Checksum([]byte("defgh"), 0)
+
+	buf := make([]byte, 24)
+	binary.LittleEndian.PutUint64(buf, h1)
+	binary.LittleEndian.PutUint64(buf[8:], h2)
+	binary.LittleEndian.PutUint64(buf[16:], 5)
+	if got, want := xxHash64.Combine(h1, h2, 5), xxHash64.Checksum(buf, 0); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	if xxHash64.Combine(h1, h2, 5) == xxHash64.Combine(h2, h1, 3) {
+		t.Errorf("combination is order independent")
+	}
+}
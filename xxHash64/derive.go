@@ -0,0 +1,9 @@
+package xxHash64
+
+// DeriveSeed returns the seed of the domain identified by label within the parent seed,
+// so that subsystems sharing a base seed use distinct hash spaces.
+// The derivation is stable: it is the ChecksumUint64 of the ChecksumString of label with parent,
+// again with parent, which differs from the plain hash of label.
+func DeriveSeed(parent uint64, label string) uint64 {
+	return ChecksumUint64(ChecksumString(label, parent), parent)
+}
@@ -0,0 +1,28 @@
+package xxHash64_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	seeds := make(map[uint64]string)
+	for _, parent := range []uint64{0, 1, 0xCAFE} {
+		for _, label := range []string{"", "bloom", "shard", "cache"} {
+			s := xxHash64.DeriveSeed(parent, label)
+			if s != xxHash64.DeriveSeed(parent, label) {
+				t.Fatalf("unstable derivation")
+			}
+			if s == xxHash64.ChecksumString(label, parent) {
+				t.Errorf("derived seed equals the hash of the label")
+			}
+			key := fmt.Sprint(label, "@", parent)
+			if other, ok := seeds[s]; ok {
+				t.Errorf("%s and %s derive the same seed", key, other)
+			}
+			seeds[s] = key
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package xxHash64
+
+import "io"
+
+// ETagFor returns a strong HTTP entity tag for data, made of its canonical hexadecimal
+// 64bits Hash value in double quotes, for instance "44bc2cf5ad770999".
+func ETagFor(data []byte) string {
+	return etag(Checksum(data, 0))
+}
+
+// ETagForReader is like ETagFor but for the data read from r until io.EOF.
+func ETagForReader(r io.Reader) (string, error) {
+	h, err := checksumReader(r, 0)
+	if err != nil {
+		return "", err
+	}
+	return etag(h), nil
+}
+
+// WeakETag returns the weak version of the entity tag etag, prefixed with W/.
+func WeakETag(etag string) string {
+	if len(etag) >= 2 && etag[:2] == "W/" {
+		return etag
+	}
+	return "W/" + etag
+}
+
+func etag(h uint64) string {
+	return `"` + Hash64(h).String() + `"`
+}
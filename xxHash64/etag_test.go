@@ -0,0 +1,31 @@
+package xxHash64_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestETag(t *testing.T) {
+	data := []byte("abc")
+	want := `"44bc2cf5ad770999"`
+	if got := xxHash64.ETagFor(data); got != want {
+		t.Errorf("got %s expected %s", got, want)
+	}
+	got, err := xxHash64.ETagForReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got %s expected %s", got, want)
+	}
+
+	weak := xxHash64.WeakETag(want)
+	if weak != "W/"+want {
+		t.Errorf("got %s expected W/%s", weak, want)
+	}
+	if got := xxHash64.WeakETag(weak); got != weak {
+		t.Errorf("got %s expected %s", got, weak)
+	}
+}
@@ -19,3 +19,15 @@ func ExampleChecksum() {
 	fmt.Printf("%x\n", xxHash64.Checksum(buf.Bytes(), 0xCAFE))
 	// Output: 4228c3215949e862
 }
+
+func ExampleNewInto() {
+	var d xxHash64.Digest
+	for _, s := range []string{"this is a test", "another test"} {
+		xxHash64.NewInto(&d, 0xCAFE)
+		d.Write([]byte(s))
+		fmt.Printf("%x\n", d.Sum64())
+	}
+	// Output:
+	// 4228c3215949e862
+	// f5f1515c539231db
+}
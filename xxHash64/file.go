@@ -0,0 +1,89 @@
+package xxHash64
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// ChecksumFileMmap returns the 64bits Hash value of the content of the named file.
+// On supporting platforms, regular files are memory mapped and hashed directly,
+// avoiding the read system calls and copies. Other files are read.
+func ChecksumFileMmap(path string, seed uint64) (uint64, error) {
+	return ChecksumFileMmapContext(context.Background(), path, seed)
+}
+
+// ChecksumFileMmapContext is like ChecksumFileMmap but returns ctx.Err() if ctx is done
+// before the file is hashed. ctx is checked every ParallelChunkSize bytes.
+func ChecksumFileMmapContext(ctx context.Context, path string, seed uint64) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if fi.Mode().IsRegular() && fi.Size() > 0 && int64(int(fi.Size())) == fi.Size() {
+		if data, unmap, ok := mmap(f, int(fi.Size())); ok {
+			defer unmap()
+			return checksumContext(ctx, data, seed)
+		}
+	}
+	return checksumReaderContext(ctx, f, seed)
+}
+
+// checksumContext returns the 64bits Hash value of data, checking ctx every ParallelChunkSize bytes.
+func checksumContext(ctx context.Context, data []byte, seed uint64) (uint64, error) {
+	if ctx.Done() == nil {
+		// ctx is never cancelled.
+		return Checksum(data, seed), nil
+	}
+	xxh := Digest{seed: seed}
+	xxh.Reset()
+	for len(data) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n := ParallelChunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		xxh.Write(data[:n])
+		data = data[n:]
+	}
+	return xxh.Sum64(), nil
+}
+
+// checksumReader returns the 64bits Hash value of the data read from r until io.EOF.
+func checksumReader(r io.Reader, seed uint64) (uint64, error) {
+	xxh := New(seed)
+	if _, err := io.Copy(xxh, r); err != nil {
+		return 0, err
+	}
+	return xxh.Sum64(), nil
+}
+
+// checksumReaderContext is like checksumReader but checks ctx before every read.
+func checksumReaderContext(ctx context.Context, r io.Reader, seed uint64) (uint64, error) {
+	if ctx.Done() == nil {
+		return checksumReader(r, seed)
+	}
+	xxh := Digest{seed: seed}
+	xxh.Reset()
+	buf := make([]byte, 64<<10)
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n, err := r.Read(buf)
+		xxh.Write(buf[:n])
+		if err == io.EOF {
+			return xxh.Sum64(), nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
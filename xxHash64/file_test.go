@@ -0,0 +1,67 @@
+package xxHash64_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestChecksumFileMmap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxHash64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const seed = 0xCAFE
+	for _, n := range []int{0, 1, 100, 100000} {
+		data := testvectors.Input(n)
+		path := filepath.Join(dir, "file")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		h, err := xxHash64.ChecksumFileMmap(path, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := xxHash64.Checksum(data, seed); h != want {
+			t.Errorf("size %d: got 0x%x expected 0x%x", n, h, want)
+		}
+	}
+
+	if _, err := xxHash64.ChecksumFileMmap(filepath.Join(dir, "missing"), 0); !os.IsNotExist(err) {
+		t.Errorf("got error %v expected a missing file error", err)
+	}
+}
+
+func TestChecksumFileMmapContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxHash64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := testvectors.Input(3*xxHash64.ParallelChunkSize + 1)
+	path := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := xxHash64.ChecksumFileMmapContext(ctx, path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := xxHash64.Checksum(data, 0); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+
+	cancel()
+	if _, err := xxHash64.ChecksumFileMmapContext(ctx, path, 0); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,39 @@
+package xxHash64
+
+// ChecksumUint32 returns the 64bits Hash value of the 4 bytes little endian encoding of v.
+// It is a faster equivalent of Checksum for such inputs.
+func ChecksumUint32(v uint32, seed uint64) uint64 {
+	h64 := seed + prime64_5 + 4
+	h64 ^= uint64(v) * prime64_1
+	h64 = rol23(h64)*prime64_2 + prime64_3
+	return avalanche(h64)
+}
+
+// ChecksumUint64 returns the 64bits Hash value of the 8 bytes little endian encoding of v.
+// It is a faster equivalent of Checksum for such inputs.
+func ChecksumUint64(v uint64, seed uint64) uint64 {
+	h64 := seed + prime64_5 + 8
+	h64 ^= rol31(v*prime64_2) * prime64_1
+	h64 = rol27(h64)*prime64_1 + prime64_4
+	return avalanche(h64)
+}
+
+// ChecksumUint64x2 returns the 64bits Hash value of the 16 bytes little endian encoding of v1 followed by v2.
+// It is a faster equivalent of Checksum for such inputs.
+func ChecksumUint64x2(v1, v2 uint64, seed uint64) uint64 {
+	h64 := seed + prime64_5 + 16
+	h64 ^= rol31(v1*prime64_2) * prime64_1
+	h64 = rol27(h64)*prime64_1 + prime64_4
+	h64 ^= rol31(v2*prime64_2) * prime64_1
+	h64 = rol27(h64)*prime64_1 + prime64_4
+	return avalanche(h64)
+}
+
+func avalanche(h64 uint64) uint64 {
+	h64 ^= h64 >> 33
+	h64 *= prime64_2
+	h64 ^= h64 >> 29
+	h64 *= prime64_3
+	h64 ^= h64 >> 32
+	return h64
+}
@@ -0,0 +1,26 @@
+package xxHash64_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestChecksumUint(t *testing.T) {
+	const seed = 0xCAFE
+	var b [16]byte
+	for _, v := range []uint64{0, 1, 0xDEADBEEF, 1<<64 - 1} {
+		binary.LittleEndian.PutUint64(b[:], v)
+		binary.LittleEndian.PutUint64(b[8:], ^v)
+		if got, want := xxHash64.ChecksumUint32(uint32(v), seed), xxHash64.Checksum(b[:4], seed); got != want {
+			t.Errorf("ChecksumUint32(0x%x): got 0x%x expected 0x%x", uint32(v), got, want)
+		}
+		if got, want := xxHash64.ChecksumUint64(v, seed), xxHash64.Checksum(b[:8], seed); got != want {
+			t.Errorf("ChecksumUint64(0x%x): got 0x%x expected 0x%x", v, got, want)
+		}
+		if got, want := xxHash64.ChecksumUint64x2(v, ^v, seed), xxHash64.Checksum(b[:], seed); got != want {
+			t.Errorf("ChecksumUint64x2(0x%x): got 0x%x expected 0x%x", v, got, want)
+		}
+	}
+}
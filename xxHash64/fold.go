@@ -0,0 +1,87 @@
+package xxHash64
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Folding selects the case folding applied by ChecksumStringFold.
+type Folding int
+
+const (
+	// FoldASCII maps the ASCII upper case letters to lower case.
+	FoldASCII Folding = iota
+	// FoldUnicode applies the Unicode simple case folding,
+	// so that strings equal under strings.EqualFold have the same hash.
+	FoldUnicode
+)
+
+// ChecksumStringFold returns the 64bits Hash value of s after case folding,
+// without allocating a folded copy of s.
+//
+// With FoldASCII, the result is the Checksum of the lower cased string.
+// With FoldUnicode, each rune is replaced by the smallest rune of its simple folding orbit
+// (invalid UTF-8 sequences by utf8.RuneError) before being hashed as UTF-8.
+func ChecksumStringFold(s string, seed uint64, fold Folding) uint64 {
+	if fold == FoldASCII && !hasUpperASCII(s) {
+		return ChecksumString(s, seed)
+	}
+
+	var (
+		xxh = Digest{seed: seed}
+		buf [128]byte
+		n   int
+	)
+	xxh.Reset()
+	for i := 0; i < len(s); {
+		if n > len(buf)-utf8.UTFMax {
+			xxh.Write(buf[:n])
+			n = 0
+		}
+		c := s[i]
+		if fold == FoldASCII {
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			buf[n] = c
+			n++
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			// The smallest rune of the folding orbit of an ASCII letter is its upper case.
+			if 'a' <= c && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			buf[n] = c
+			n++
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += utf8.EncodeRune(buf[n:], foldRune(r))
+		i += size
+	}
+	xxh.Write(buf[:n])
+	return xxh.Sum64()
+}
+
+func hasUpperASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; 'A' <= c && c <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
@@ -0,0 +1,52 @@
+package xxHash64_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestChecksumStringFoldASCII(t *testing.T) {
+	for _, s := range []string{"", "content-type", "Content-Type", "WWW.Example.COM", strings.Repeat("X-Custom-Header", 20), "Éé"} {
+		want := xxHash64.ChecksumString(strings.Map(func(r rune) rune {
+			if 'A' <= r && r <= 'Z' {
+				return r + 'a' - 'A'
+			}
+			return r
+		}, s), 1)
+		if got := xxHash64.ChecksumStringFold(s, 1, xxHash64.FoldASCII); got != want {
+			t.Errorf("%q: got 0x%x expected 0x%x", s, got, want)
+		}
+	}
+}
+
+func TestChecksumStringFoldUnicode(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"Straße", "STRAßE"},
+		{"ÉCOLE", "école"},
+		{"Kelvin", "Kelvin"},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος"},
+		{strings.Repeat("Über", 100), strings.Repeat("üBER", 100)},
+		{"\xff", "\xfe"},
+	} {
+		if !strings.EqualFold(pair[0], pair[1]) {
+			t.Fatalf("%q and %q are not equal under folding", pair[0], pair[1])
+		}
+		h0 := xxHash64.ChecksumStringFold(pair[0], 1, xxHash64.FoldUnicode)
+		h1 := xxHash64.ChecksumStringFold(pair[1], 1, xxHash64.FoldUnicode)
+		if h0 != h1 {
+			t.Errorf("%q and %q: got 0x%x and 0x%x", pair[0], pair[1], h0, h1)
+		}
+	}
+	if xxHash64.ChecksumStringFold("a", 1, xxHash64.FoldUnicode) == xxHash64.ChecksumStringFold("b", 1, xxHash64.FoldUnicode) {
+		t.Errorf("distinct strings have the same hash")
+	}
+}
+
+func TestChecksumStringFoldAllocs(t *testing.T) {
+	s := strings.Repeat("Content-Type", 50)
+	if n := testing.AllocsPerRun(10, func() { xxHash64.ChecksumStringFold(s, 0, xxHash64.FoldUnicode) }); n != 0 {
+		t.Errorf("got %f allocations", n)
+	}
+}
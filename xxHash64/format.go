@@ -0,0 +1,34 @@
+package xxHash64
+
+import (
+	"fmt"
+
+	"github.com/pierrec/xxHash/internal/xxhfmt"
+)
+
+// Format implements the fmt.Formatter interface so that h is printed in its canonical form,
+// 16 hexadecimal digits, with the %v, %s, %x and %X verbs.
+// The # flag adds the 0x prefix and %d prints the decimal value.
+func (h Hash64) Format(f fmt.State, verb rune) {
+	format(f, verb, uint64(h))
+}
+
+// Format implements the fmt.Formatter interface, as for Hash64.
+func (h Hash64Int) Format(f fmt.State, verb rune) {
+	format(f, verb, uint64(h))
+}
+
+// Format implements the fmt.Formatter interface, as for Hash64.
+func (c Canonical64) Format(f fmt.State, verb rune) {
+	format(f, verb, c.Hash())
+}
+
+// Format implements the fmt.Formatter interface so that the digest is printed
+// as its current hash value, as for Hash64.
+func (xxh *Digest) Format(f fmt.State, verb rune) {
+	format(f, verb, xxh.Sum64())
+}
+
+func format(f fmt.State, verb rune, v uint64) {
+	xxhfmt.Format(f, verb, "xxHash64", Hash64(v).String(), v)
+}
@@ -0,0 +1,48 @@
+package xxHash64_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestFormat(t *testing.T) {
+	h := xxHash64.Hash64(0xabc)
+	var c xxHash64.Canonical64
+	c.FromHash(0xabc)
+	for _, tc := range []struct {
+		format, want string
+	}{
+		{"%v", "0000000000000abc"},
+		{"%s", "0000000000000abc"},
+		{"%x", "0000000000000abc"},
+		{"%X", "0000000000000ABC"},
+		{"%#x", "0x0000000000000abc"},
+		{"%#v", "0x0000000000000abc"},
+		{"%q", `"0000000000000abc"`},
+		{"%d", "2748"},
+		{"%20x", "    0000000000000abc"},
+		{"%-20x|", "0000000000000abc    |"},
+		{"%t", "%!t(xxHash64=0000000000000abc)"},
+	} {
+		for _, v := range []any{h, xxHash64.Hash64Int(h), c} {
+			if got := fmt.Sprintf(tc.format, v); got != tc.want {
+				t.Errorf("%s %T: got %q expected %q", tc.format, v, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	var d xxHash64.Digest
+	xxHash64.NewInto(&d, 0)
+	d.Write([]byte("abc"))
+	want := xxHash64.Hash64(xxHash64.Checksum([]byte("abc"), 0)).String()
+	if got := fmt.Sprintf("%v", &d); got != want {
+		t.Errorf("got %q expected %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%#x", xxHash64.New(0)), "0x"+xxHash64.Hash64(xxHash64.Checksum(nil, 0)).String(); got != want {
+		t.Errorf("New: got %q expected %q", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// FuzzXXH64 checks that hashing data with Writes split at arbitrary points
+// always gives the same result as Checksum.
+// Each byte of splits is the length of a Write, the rest of the data being written last.
+func FuzzXXH64(f *testing.F) {
+	f.Add([]byte(""), []byte{}, uint64(0))
+	f.Add([]byte("abc"), []byte{1, 0, 1}, uint64(1))
+	f.Add(testvectors.Input(100), []byte{31, 1, 32, 3}, uint64(0xCAFE))
+	f.Add(testvectors.Input(1000), []byte{255, 16, 64, 200}, uint64(2654435761))
+	f.Fuzz(func(t *testing.T, data, splits []byte, seed uint64) {
+		want := xxHash64.Checksum(data, seed)
+		xxh := xxHash64.New(seed)
+		rest := data
+		for _, s := range splits {
+			n := int(s)
+			if n > len(rest) {
+				n = len(rest)
+			}
+			xxh.Write(rest[:n])
+			rest = rest[n:]
+		}
+		xxh.Write(rest)
+		if h := xxh.Sum64(); h != want {
+			t.Errorf("splits %v: got 0x%x expected 0x%x", splits, h, want)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package xxHash64
+
+// Hash64 is a 64bits Hash value whose text representation is its canonical form,
+// 16 lower case hexadecimal digits.
+// It is encoded as a JSON string, through the encoding.TextMarshaler interface,
+// so that its value is not altered by JSON numbers precision.
+type Hash64 uint64
+
+// String returns the canonical form of h.
+func (h Hash64) String() string {
+	var c Canonical64
+	c.FromHash(uint64(h))
+	return c.String()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (h Hash64) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts up to 16 hexadecimal digits, in either case.
+func (h *Hash64) UnmarshalText(text []byte) error {
+	v, err := ParseHex(string(text))
+	if err != nil {
+		return err
+	}
+	*h = Hash64(v)
+	return nil
+}
@@ -0,0 +1,42 @@
+package xxHash64_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestHash64(t *testing.T) {
+	h := xxHash64.Hash64(0xff)
+	if s := h.String(); s != "00000000000000ff" {
+		t.Errorf("got %s expected 00000000000000ff", s)
+	}
+
+	type doc struct {
+		Hash  xxHash64.Hash64
+		Index map[xxHash64.Hash64]int
+	}
+	in := doc{
+		Hash:  xxHash64.Hash64(xxHash64.Checksum([]byte("abc"), 0)),
+		Index: map[xxHash64.Hash64]int{1<<64 - 1: 1},
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Hash":"44bc2cf5ad770999","Index":{"ffffffffffffffff":1}}`; string(b) != want {
+		t.Errorf("got %s expected %s", b, want)
+	}
+	var out doc
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Hash != in.Hash || out.Index[1<<64-1] != 1 {
+		t.Errorf("got %v expected %v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Hash":"xyz"}`), &out); err == nil {
+		t.Errorf("invalid hash unmarshaled")
+	}
+}
@@ -0,0 +1,29 @@
+package xxHash64
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrHex is returned when parsing an invalid hexadecimal hash value.
+var ErrHex = errors.New("xxHash64: invalid hexadecimal hash")
+
+// ChecksumHex returns the 64bits Hash value of data as 16 lower case hexadecimal digits,
+// in its canonical form as printed by xxhsum.
+func ChecksumHex(data []byte, seed uint64) string {
+	var c Canonical64
+	c.FromHash(Checksum(data, seed))
+	return c.String()
+}
+
+// ParseHex parses a hash value made of up to 16 hexadecimal digits, in either case.
+func ParseHex(s string) (uint64, error) {
+	if len(s) == 0 || len(s) > 16 {
+		return 0, ErrHex
+	}
+	h, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, ErrHex
+	}
+	return h, nil
+}
@@ -0,0 +1,44 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestChecksumHex(t *testing.T) {
+	for i, td := range testdata {
+		s := xxHash64.ChecksumHex([]byte(td.data), 0)
+		if len(s) != 16 {
+			t.Errorf("test %d: got %q", i, s)
+		}
+		h, err := xxHash64.ParseHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h != td.sum {
+			t.Errorf("test %d: got 0x%x expected 0x%x", i, h, td.sum)
+		}
+	}
+	if s := xxHash64.ChecksumHex([]byte("a"), 0); s != "d24ec4f1a98c6e5b" {
+		t.Errorf("got %s expected d24ec4f1a98c6e5b", s)
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	for s, want := range map[string]uint64{
+		"0":                0,
+		"00000000000000ff": 0xff,
+		"D24EC4F1A98C6E5B": 0xd24ec4f1a98c6e5b,
+		"ffffffffffffffff": 1<<64 - 1,
+	} {
+		if h, err := xxHash64.ParseHex(s); err != nil || h != want {
+			t.Errorf("%q: got 0x%x, %v expected 0x%x", s, h, err, want)
+		}
+	}
+	for _, s := range []string{"", "0x12", "-1", "+1", "g", "1_0", "00000000000000000"} {
+		if _, err := xxHash64.ParseHex(s); err != xxHash64.ErrHex {
+			t.Errorf("%q: got error %v expected %v", s, err, xxHash64.ErrHex)
+		}
+	}
+}
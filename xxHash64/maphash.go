@@ -0,0 +1,87 @@
+package xxHash64
+
+import "unsafe"
+
+// Hash mirrors the hash/maphash.Hash API on top of xxHash64,
+// so that code written against hash/maphash can switch to it by changing its import.
+// Unlike hash/maphash, the hash values only depend on the seed and the data,
+// making them stable across processes.
+//
+// The zero Hash is valid and uses a random seed, obtained with MakeSeed on first use,
+// unless SetSeed is called first.
+type Hash struct {
+	_    [0]func() // not comparable
+	seed Seed
+	init bool
+	xxh  Digest
+}
+
+// initSeed sets a random seed if none was set.
+func (h *Hash) initSeed() {
+	if !h.init {
+		h.SetSeed(MakeSeed())
+	}
+}
+
+// SetSeed sets h to use seed and resets it.
+func (h *Hash) SetSeed(seed Seed) {
+	h.seed = seed
+	h.init = true
+	h.xxh = Digest{seed: seed.v}
+	h.xxh.Reset()
+}
+
+// Seed returns the seed of h.
+func (h *Hash) Seed() Seed {
+	h.initSeed()
+	return h.seed
+}
+
+// Write adds b to the hashed data.
+// It never returns an error.
+func (h *Hash) Write(b []byte) (int, error) {
+	h.initSeed()
+	return h.xxh.Write(b)
+}
+
+// WriteString adds the bytes of s to the hashed data.
+// It never returns an error.
+func (h *Hash) WriteString(s string) (int, error) {
+	return h.Write(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// WriteByte adds b to the hashed data.
+// It never returns an error.
+func (h *Hash) WriteByte(b byte) error {
+	h.Write([]byte{b})
+	return nil
+}
+
+// Reset discards the hashed data, keeping the seed.
+func (h *Hash) Reset() {
+	h.initSeed()
+	h.xxh.Reset()
+}
+
+// Sum64 returns the 64bits Hash value of the hashed data.
+func (h *Hash) Sum64() uint64 {
+	h.initSeed()
+	return h.xxh.Sum64()
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (h *Hash) Sum(b []byte) []byte {
+	h.initSeed()
+	return h.xxh.Sum(b)
+}
+
+// Size returns the number of bytes returned by Sum().
+func (h *Hash) Size() int {
+	return 8
+}
+
+// BlockSize gives the minimum number of bytes accepted by Write().
+func (h *Hash) BlockSize() int {
+	return 1
+}
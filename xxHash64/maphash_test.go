@@ -0,0 +1,49 @@
+package xxHash64_test
+
+import (
+	"hash"
+	"io"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var (
+	_ hash.Hash64     = (*xxHash64.Hash)(nil)
+	_ io.StringWriter = (*xxHash64.Hash)(nil)
+	_ io.ByteWriter   = (*xxHash64.Hash)(nil)
+)
+
+func TestHash(t *testing.T) {
+	seed := xxHash64.MakeSeed()
+	for i, td := range testdata {
+		var h xxHash64.Hash
+		h.SetSeed(seed)
+		data := []byte(td.data)
+		l := len(data) / 2
+		h.Write(data[:l/2])
+		h.WriteString(td.data[l/2 : l])
+		for _, c := range data[l:] {
+			h.WriteByte(c)
+		}
+		want := xxHash64.Bytes(seed, data)
+		if got := h.Sum64(); got != want {
+			t.Errorf("test %d: got 0x%x expected 0x%x", i, got, want)
+		}
+		if got := h.Sum64(); got != want {
+			t.Errorf("test %d: second Sum64 got 0x%x expected 0x%x", i, got, want)
+		}
+		h.Reset()
+		if got, want := h.Sum64(), xxHash64.Bytes(seed, nil); got != want {
+			t.Errorf("test %d: after Reset got 0x%x expected 0x%x", i, got, want)
+		}
+	}
+}
+
+func TestHashZero(t *testing.T) {
+	var h xxHash64.Hash
+	h.WriteString("abc")
+	if got, want := h.Sum64(), xxHash64.String(h.Seed(), "abc"); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+}
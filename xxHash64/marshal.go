@@ -0,0 +1,47 @@
+package xxHash64
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrState is returned when unmarshaling an invalid hash state.
+var ErrState = errors.New("xxHash64: invalid hash state")
+
+const (
+	stateVersion = 1
+	stateSize    = 1 + 6*8 // version, seed, v1 to v4, totalLen
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It saves the state of the Hash so that it can be restored with UnmarshalBinary,
+// for instance to resume hashing a large stream after a restart.
+func (xxh *Digest) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, stateSize, stateSize+xxh.bufused)
+	buf[0] = stateVersion
+	for i, v := range [...]uint64{xxh.seed, xxh.v1, xxh.v2, xxh.v3, xxh.v4, xxh.totalLen} {
+		binary.LittleEndian.PutUint64(buf[1+8*i:], v)
+	}
+	return append(buf, xxh.buf[:xxh.bufused]...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It restores a state saved by MarshalBinary, including its seed.
+func (xxh *Digest) UnmarshalBinary(data []byte) error {
+	if len(data) < stateSize || data[0] != stateVersion {
+		return ErrState
+	}
+	totalLen := binary.LittleEndian.Uint64(data[41:])
+	bufused := int(totalLen % uint64(len(xxh.buf)))
+	if len(data) != stateSize+bufused {
+		return ErrState
+	}
+	xxh.seed = binary.LittleEndian.Uint64(data[1:])
+	xxh.v1 = binary.LittleEndian.Uint64(data[9:])
+	xxh.v2 = binary.LittleEndian.Uint64(data[17:])
+	xxh.v3 = binary.LittleEndian.Uint64(data[25:])
+	xxh.v4 = binary.LittleEndian.Uint64(data[33:])
+	xxh.totalLen = totalLen
+	xxh.bufused = copy(xxh.buf[:], data[stateSize:])
+	return nil
+}
@@ -0,0 +1,46 @@
+package xxHash64_test
+
+import (
+	"encoding"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	const seed = 0xCAFE
+	data := testvectors.Input(1000)
+	for _, n := range []int{0, 1, 31, 32, 33, 100, 999} {
+		xxh := xxHash64.New(seed)
+		xxh.Write(data[:n])
+		state, err := xxh.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The seed is restored too.
+		res := xxHash64.New(0)
+		if err := res.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatalf("%d: %v", n, err)
+		}
+		res.Write(data[n:])
+		if got, want := res.Sum64(), xxHash64.Checksum(data, seed); got != want {
+			t.Errorf("%d: got 0x%x expected 0x%x", n, got, want)
+		}
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	xxh := xxHash64.New(0)
+	xxh.Write(testvectors.Input(10))
+	state, _ := xxh.(encoding.BinaryMarshaler).MarshalBinary()
+
+	bad := append([]byte{}, state...)
+	bad[0] = 0
+	for _, data := range [][]byte{nil, state[:len(state)-1], append(state, 0), bad} {
+		if err := xxh.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != xxHash64.ErrState {
+			t.Errorf("%x: got error %v expected %v", data, err, xxHash64.ErrState)
+		}
+	}
+}
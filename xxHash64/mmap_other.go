@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package xxHash64
+
+import "os"
+
+// mmap always reports false as memory mapping is not supported on this platform.
+func mmap(f *os.File, size int) ([]byte, func(), bool) {
+	return nil, nil, false
+}
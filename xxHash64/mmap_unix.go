@@ -0,0 +1,18 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package xxHash64
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps the first size bytes of f in memory, returning the mapped data and the function unmapping it.
+// It reports false if the file could not be mapped.
+func mmap(f *os.File, size int) ([]byte, func(), bool) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, false
+	}
+	return data, func() { syscall.Munmap(data) }, true
+}
@@ -0,0 +1,23 @@
+package xxHash64
+
+// ChecksumK returns k hash values of data derived from a single Checksum
+// using the Kirsch-Mitzenmacher double hashing scheme: the i-th value is h1 + i*h2,
+// h1 being the Checksum of data with seed and h2 an odd value mixed from h1.
+// It panics if k is negative.
+func ChecksumK(data []byte, seed uint64, k int) []uint64 {
+	return AppendChecksumK(make([]uint64, 0, k), data, seed, k)
+}
+
+// AppendChecksumK is like ChecksumK but appends the values to dst.
+func AppendChecksumK(dst []uint64, data []byte, seed uint64, k int) []uint64 {
+	if k < 0 {
+		panic("xxHash64: negative number of hash values")
+	}
+	h1 := Checksum(data, seed)
+	// An odd h2 cycles through all the values modulo powers of two.
+	h2 := ChecksumUint64(h1, seed) | 1
+	for i := 0; i < k; i++ {
+		dst = append(dst, h1+uint64(i)*h2)
+	}
+	return dst
+}
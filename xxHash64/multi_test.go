@@ -0,0 +1,31 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestChecksumK(t *testing.T) {
+	data := []byte("abc")
+	hs := xxHash64.ChecksumK(data, 1, 8)
+	if len(hs) != 8 {
+		t.Fatalf("got %d values expected 8", len(hs))
+	}
+	if want := xxHash64.Checksum(data, 1); hs[0] != want {
+		t.Errorf("got first value 0x%x expected 0x%x", hs[0], want)
+	}
+	seen := make(map[uint64]bool)
+	for i, h := range hs {
+		if seen[h] {
+			t.Errorf("value %d: duplicate 0x%x", i, h)
+		}
+		seen[h] = true
+	}
+	if got := xxHash64.AppendChecksumK(hs[:0], data, 1, 8); got[7] != hs[7] {
+		t.Errorf("AppendChecksumK: got 0x%x expected 0x%x", got[7], hs[7])
+	}
+	if n := len(xxHash64.ChecksumK(data, 1, 0)); n != 0 {
+		t.Errorf("got %d values expected none", n)
+	}
+}
@@ -0,0 +1,85 @@
+package xxHash64
+
+// Multiset computes a 64bits Hash value of a collection of elements that does not depend
+// on their order, such as a set, a list of tags or the entries of a map.
+// Elements are counted with their multiplicity: adding an element twice is not the same as adding it once.
+//
+// The hash of each element is Checksum(element, seed). These hashes are summed,
+// and the result is ChecksumUint64x2(sum, number of elements, seed).
+// As the sum can be updated in any order, elements can also be removed and
+// multisets hashed separately can be merged.
+//
+// The zero value is a valid empty Multiset using a zero seed.
+type Multiset struct {
+	seed uint64
+	sum  uint64
+	n    uint64
+}
+
+// NewMultiset returns a new empty Multiset using seed.
+func NewMultiset(seed uint64) *Multiset {
+	return &Multiset{seed: seed}
+}
+
+// Add adds an element.
+func (m *Multiset) Add(elem []byte) {
+	m.AddHash(Checksum(elem, m.seed))
+}
+
+// AddString adds an element given as a string.
+func (m *Multiset) AddString(elem string) {
+	m.AddHash(ChecksumString(elem, m.seed))
+}
+
+// AddHash adds an element given by its hash, which should be computed with the seed of the Multiset,
+// for instance ChecksumUint64x2(keyHash, valueHash, seed) for the entry of a map.
+func (m *Multiset) AddHash(h uint64) {
+	m.sum += h
+	m.n++
+}
+
+// Remove removes an element previously added.
+func (m *Multiset) Remove(elem []byte) {
+	m.RemoveHash(Checksum(elem, m.seed))
+}
+
+// RemoveHash removes an element previously added by its hash.
+func (m *Multiset) RemoveHash(h uint64) {
+	m.sum -= h
+	m.n--
+}
+
+// Merge adds the elements of o, which must use the same seed, to m.
+// It panics if the seeds differ.
+func (m *Multiset) Merge(o *Multiset) {
+	if m.seed != o.seed {
+		panic("xxHash64: merging multisets with different seeds")
+	}
+	m.sum += o.sum
+	m.n += o.n
+}
+
+// Len returns the number of elements.
+func (m *Multiset) Len() int {
+	return int(m.n)
+}
+
+// Reset removes all the elements.
+func (m *Multiset) Reset() {
+	m.sum = 0
+	m.n = 0
+}
+
+// Sum64 returns the 64bits Hash value of the elements.
+func (m *Multiset) Sum64() uint64 {
+	return ChecksumUint64x2(m.sum, m.n, m.seed)
+}
+
+// ChecksumUnordered returns the Multiset 64bits Hash value of elems.
+func ChecksumUnordered(elems [][]byte, seed uint64) uint64 {
+	m := Multiset{seed: seed}
+	for _, elem := range elems {
+		m.Add(elem)
+	}
+	return m.Sum64()
+}
@@ -0,0 +1,59 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestMultiset(t *testing.T) {
+	const seed = 0xCAFE
+	elems := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("a")}
+	want := xxHash64.ChecksumUnordered(elems, seed)
+
+	m := xxHash64.NewMultiset(seed)
+	for i := len(elems) - 1; i >= 0; i-- {
+		m.Add(elems[i])
+	}
+	if got := m.Sum64(); got != want {
+		t.Errorf("reverse order: got 0x%x expected 0x%x", got, want)
+	}
+	if got := m.Len(); got != len(elems) {
+		t.Errorf("got %d elements expected %d", got, len(elems))
+	}
+
+	// Multiplicity matters.
+	if got := xxHash64.ChecksumUnordered(elems[:3], seed); got == want {
+		t.Errorf("without duplicate: same hash 0x%x", got)
+	}
+	// Concatenating elements does not collide.
+	if a, b := xxHash64.ChecksumUnordered([][]byte{[]byte("ab"), []byte("c")}, seed),
+		xxHash64.ChecksumUnordered([][]byte{[]byte("a"), []byte("bc")}, seed); a == b {
+		t.Errorf("different elements: same hash 0x%x", a)
+	}
+
+	// Removal and merging.
+	m.AddString("d")
+	m.Remove([]byte("d"))
+	if got := m.Sum64(); got != want {
+		t.Errorf("after Remove: got 0x%x expected 0x%x", got, want)
+	}
+	m1, m2 := xxHash64.NewMultiset(seed), xxHash64.NewMultiset(seed)
+	m1.Add(elems[0])
+	m1.Add(elems[1])
+	m2.Add(elems[2])
+	m2.Add(elems[3])
+	m1.Merge(m2)
+	if got := m1.Sum64(); got != want {
+		t.Errorf("after Merge: got 0x%x expected 0x%x", got, want)
+	}
+
+	m.Reset()
+	var zero xxHash64.Multiset
+	if got, want := m.Sum64(), xxHash64.ChecksumUnordered(nil, seed); got != want {
+		t.Errorf("after Reset: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := zero.Sum64(), xxHash64.ChecksumUnordered(nil, 0); got != want {
+		t.Errorf("zero value: got 0x%x expected 0x%x", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package xxHash64
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ErrSize is returned by ParallelChecksumReaderAt and its variants when size is negative.
+var ErrSize = errors.New("xxHash64: negative size")
+
+// ParallelChunkSize is the default size of the chunks hashed independently by ParallelChecksum.
+const ParallelChunkSize = 1 << 20
+
+// BufferPool provides the buffers used by the concurrent hashers to read data.
+type BufferPool interface {
+	// Get returns a buffer of at least size bytes.
+	Get(size int) []byte
+	// Put releases a buffer returned by Get.
+	Put(buf []byte)
+}
+
+// ParallelOptions configures the concurrent hashers.
+// The zero value uses all available CPUs, ParallelChunkSize chunks and allocates its buffers.
+type ParallelOptions struct {
+	// Workers is the maximum number of goroutines hashing concurrently.
+	// All available CPUs are used if it is not positive.
+	Workers int
+	// ChunkSize is the size of the tree mode chunks.
+	// ParallelChunkSize is used if it is not positive.
+	// Note that the resulting hash depends on it.
+	ChunkSize int
+	// BufferPool provides the read buffers, one per worker.
+	// Buffers are allocated if it is nil.
+	BufferPool BufferPool
+}
+
+func (o ParallelOptions) chunkSize() int {
+	if o.ChunkSize <= 0 {
+		return ParallelChunkSize
+	}
+	return o.ChunkSize
+}
+
+func (o ParallelOptions) workers(chunks int) int {
+	w := o.Workers
+	if w <= 0 {
+		w = runtime.GOMAXPROCS(0)
+	}
+	if w > chunks {
+		w = chunks
+	}
+	return w
+}
+
+func (o ParallelOptions) getBuffer(size int) []byte {
+	if o.BufferPool == nil {
+		return make([]byte, size)
+	}
+	return o.BufferPool.Get(size)[:size]
+}
+
+func (o ParallelOptions) putBuffer(buf []byte) {
+	if o.BufferPool != nil {
+		o.BufferPool.Put(buf)
+	}
+}
+
+// ParallelChecksum returns the tree mode 64bits Hash value of data,
+// using up to workers goroutines (all available CPUs if workers <= 0).
+//
+// The tree is built as follows:
+//   - data is split into ParallelChunkSize bytes chunks, the last one being possibly shorter
+//   - each chunk is hashed with Checksum(chunk, seed)
+//   - the chunk hashes are concatenated in order, each encoded as 8 little endian bytes
+//   - the result is the Checksum of that concatenation with the same seed
+//
+// If data fits into a single chunk, the result is Checksum(data, seed).
+// The tree mode digest of larger inputs is NOT the plain XXH64 of data.
+func ParallelChecksum(data []byte, seed uint64, workers int) uint64 {
+	return ParallelChecksumWith(data, seed, ParallelOptions{Workers: workers})
+}
+
+// ParallelChecksumWith is like ParallelChecksum but configured by opts.
+func ParallelChecksumWith(data []byte, seed uint64, opts ParallelOptions) uint64 {
+	h, _ := ParallelChecksumContext(context.Background(), data, seed, opts)
+	return h
+}
+
+// ParallelChecksumContext is like ParallelChecksumWith but returns ctx.Err() if ctx is done
+// before data is hashed. ctx is checked before hashing every chunk.
+func ParallelChecksumContext(ctx context.Context, data []byte, seed uint64, opts ParallelOptions) (uint64, error) {
+	chunkSize := opts.chunkSize()
+	if len(data) <= chunkSize {
+		return Checksum(data, seed), nil
+	}
+	leaves := make([]uint64, (len(data)+chunkSize-1)/chunkSize)
+	workers := opts.workers(len(leaves))
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(leaves) && ctx.Err() == nil; i += workers {
+				leaves[i] = Checksum(chunkAt(data, i, chunkSize), seed)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return treeRoot(leaves, seed), nil
+}
+
+// ParallelChecksumReaderAt returns the tree mode 64bits Hash value of the first size bytes of r,
+// issuing concurrent ReadAt calls from up to workers goroutines (all available CPUs if workers <= 0).
+// The result is identical to ParallelChecksum over the same data.
+func ParallelChecksumReaderAt(r io.ReaderAt, size int64, seed uint64, workers int) (uint64, error) {
+	return ParallelChecksumReaderAtWith(r, size, seed, ParallelOptions{Workers: workers})
+}
+
+// ParallelChecksumReaderAtWith is like ParallelChecksumReaderAt but configured by opts.
+func ParallelChecksumReaderAtWith(r io.ReaderAt, size int64, seed uint64, opts ParallelOptions) (uint64, error) {
+	return ParallelChecksumReaderAtContext(context.Background(), r, size, seed, opts)
+}
+
+// ParallelChecksumReaderAtContext is like ParallelChecksumReaderAtWith but returns ctx.Err()
+// if ctx is done before the data is hashed. ctx is checked before reading every chunk.
+func ParallelChecksumReaderAtContext(ctx context.Context, r io.ReaderAt, size int64, seed uint64, opts ParallelOptions) (uint64, error) {
+	if size < 0 {
+		return 0, ErrSize
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	chunkSize := int64(opts.chunkSize())
+	if size <= chunkSize {
+		buf := opts.getBuffer(int(size))
+		defer opts.putBuffer(buf)
+		if err := readChunk(r, buf, 0); err != nil {
+			return 0, err
+		}
+		return Checksum(buf, seed), nil
+	}
+	leaves := make([]uint64, (size+chunkSize-1)/chunkSize)
+	workers := opts.workers(len(leaves))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		rerr error
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return rerr != nil
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if rerr == nil {
+			rerr = err
+		}
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			buf := opts.getBuffer(int(chunkSize))
+			defer opts.putBuffer(buf)
+			for i := w; i < len(leaves) && !failed(); i += workers {
+				if err := ctx.Err(); err != nil {
+					fail(err)
+					return
+				}
+				off := int64(i) * chunkSize
+				chunk := buf
+				if n := size - off; n < chunkSize {
+					chunk = buf[:n]
+				}
+				if err := readChunk(r, chunk, off); err != nil {
+					fail(err)
+					return
+				}
+				leaves[i] = Checksum(chunk, seed)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if rerr != nil {
+		return 0, rerr
+	}
+
+	return treeRoot(leaves, seed), nil
+}
+
+// readChunk fills buf with the data of r at offset off.
+func readChunk(r io.ReaderAt, buf []byte, off int64) error {
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) {
+		// ReadAt may return io.EOF along with the last bytes.
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// chunkAt returns the i-th chunk of size chunkSize in data.
+func chunkAt(data []byte, i, chunkSize int) []byte {
+	p := i * chunkSize
+	if n := len(data) - p; n < chunkSize {
+		return data[p:]
+	}
+	return data[p : p+chunkSize]
+}
+
+// treeRoot combines the chunk hashes into the tree mode root hash.
+func treeRoot(leaves []uint64, seed uint64) uint64 {
+	buf := make([]byte, 8*len(leaves))
+	for i, h := range leaves {
+		putU64(buf[8*i:], h)
+	}
+	return Checksum(buf, seed)
+}
+
+func putU64(buf []byte, v uint64) {
+	_ = buf[7] // BCE hint for compiler
+	buf[0] = byte(v)
+	buf[1] = byte(v >> 8)
+	buf[2] = byte(v >> 16)
+	buf[3] = byte(v >> 24)
+	buf[4] = byte(v >> 32)
+	buf[5] = byte(v >> 40)
+	buf[6] = byte(v >> 48)
+	buf[7] = byte(v >> 56)
+}
@@ -0,0 +1,142 @@
+package xxHash64_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestParallelChecksumSmall(t *testing.T) {
+	for i, td := range testdata {
+		if h := xxHash64.ParallelChecksum([]byte(td.data), 0, 4); h != td.sum {
+			t.Errorf("test %d: xxh64(%s)=0x%x expected 0x%x", i, td.printable, h, td.sum)
+		}
+	}
+}
+
+func TestParallelChecksumTree(t *testing.T) {
+	const seed = 0xCAFE
+	data := testvectors.Input(3*xxHash64.ParallelChunkSize + 123)
+
+	var leaves []byte
+	for p := 0; p < len(data); p += xxHash64.ParallelChunkSize {
+		end := p + xxHash64.ParallelChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], xxHash64.Checksum(data[p:end], seed))
+		leaves = append(leaves, b[:]...)
+	}
+	want := xxHash64.Checksum(leaves, seed)
+
+	for _, workers := range []int{-1, 0, 1, 2, 3, 16} {
+		if got := xxHash64.ParallelChecksum(data, seed, workers); got != want {
+			t.Errorf("workers=%d: got 0x%x expected 0x%x", workers, got, want)
+		}
+	}
+}
+
+func TestParallelChecksumReaderAt(t *testing.T) {
+	const seed = 0xCAFE
+	for _, n := range []int{0, 1, 100, xxHash64.ParallelChunkSize, 2*xxHash64.ParallelChunkSize + 1} {
+		data := testvectors.Input(n)
+		want := xxHash64.ParallelChecksum(data, seed, 1)
+		got, err := xxHash64.ParallelChecksumReaderAt(bytes.NewReader(data), int64(n), seed, 0)
+		if err != nil {
+			t.Fatalf("size %d: %v", n, err)
+		}
+		if got != want {
+			t.Errorf("size %d: got 0x%x expected 0x%x", n, got, want)
+		}
+	}
+}
+
+func TestParallelChecksumReaderAtShort(t *testing.T) {
+	data := testvectors.Input(2*xxHash64.ParallelChunkSize + 1)
+	_, err := xxHash64.ParallelChecksumReaderAt(bytes.NewReader(data), int64(len(data))+1, 0, 0)
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("got error %v expected %v", err, io.ErrUnexpectedEOF)
+	}
+	if _, err := xxHash64.ParallelChecksumReaderAt(bytes.NewReader(data), -1, 0, 0); err != xxHash64.ErrSize {
+		t.Errorf("negative size: got error %v expected %v", err, xxHash64.ErrSize)
+	}
+}
+
+type countingPool struct {
+	mu       sync.Mutex
+	gets     int
+	puts     int
+	capacity int
+}
+
+func (p *countingPool) Get(size int) []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gets++
+	return make([]byte, size, size+p.capacity)
+}
+
+func (p *countingPool) Put(buf []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.puts++
+}
+
+func TestParallelOptions(t *testing.T) {
+	const seed, chunkSize = 0xCAFE, 1000
+	data := testvectors.Input(10*chunkSize + 1)
+
+	c := xxHash64.NewChunked(seed, chunkSize)
+	c.Write(data)
+	want := c.TreeSum64()
+
+	opts := xxHash64.ParallelOptions{Workers: 3, ChunkSize: chunkSize}
+	if got := xxHash64.ParallelChecksumWith(data, seed, opts); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	pool := &countingPool{capacity: 10}
+	opts.BufferPool = pool
+	got, err := xxHash64.ParallelChecksumReaderAtWith(bytes.NewReader(data), int64(len(data)), seed, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	if pool.gets != opts.Workers || pool.puts != pool.gets {
+		t.Errorf("got %d Get and %d Put expected %d", pool.gets, pool.puts, opts.Workers)
+	}
+}
+
+func TestParallelChecksumContext(t *testing.T) {
+	const chunkSize = 1000
+	data := testvectors.Input(10*chunkSize + 1)
+	opts := xxHash64.ParallelOptions{Workers: 2, ChunkSize: chunkSize}
+	want := xxHash64.ParallelChecksumWith(data, 0, opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	got, err := xxHash64.ParallelChecksumContext(ctx, data, 0, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	cancel()
+	if _, err := xxHash64.ParallelChecksumContext(ctx, data, 0, opts); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+	_, err = xxHash64.ParallelChecksumReaderAtContext(ctx, bytes.NewReader(data), int64(len(data)), 0, opts)
+	if err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,24 @@
+package xxHash64
+
+import "math/bits"
+
+// Partition returns the partition of key among n, in [0, n).
+// It panics if n is not positive.
+//
+// The key hash is reduced with Lemire's multiply and shift method
+// (https://arxiv.org/abs/1805.10941), rehashing the key with an incremented seed
+// in the rare cases where the result would be biased, so that all partitions are equally likely.
+func Partition(key []byte, n int) int {
+	if n <= 0 {
+		panic("xxHash64: invalid number of partitions")
+	}
+	un := uint64(n)
+	hi, lo := bits.Mul64(Checksum(key, 0), un)
+	if lo < un {
+		t := -un % un
+		for seed := uint64(1); lo < t; seed++ {
+			hi, lo = bits.Mul64(Checksum(key, seed), un)
+		}
+	}
+	return int(hi)
+}
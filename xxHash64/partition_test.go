@@ -0,0 +1,31 @@
+package xxHash64_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestPartition(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 100} {
+		counts := make([]int, n)
+		const keys = 100000
+		for i := 0; i < keys; i++ {
+			key := []byte(fmt.Sprint(i))
+			p := xxHash64.Partition(key, n)
+			if p < 0 || p >= n {
+				t.Fatalf("n=%d: got partition %d", n, p)
+			}
+			if p2 := xxHash64.Partition(key, n); p2 != p {
+				t.Fatalf("n=%d: got partitions %d and %d for the same key", n, p, p2)
+			}
+			counts[p]++
+		}
+		for p, c := range counts {
+			if want := keys / n; c < want*9/10 || c > want*11/10 {
+				t.Errorf("n=%d: partition %d got %d keys expected about %d", n, p, c, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package xxHash64
+
+import "unsafe"
+
+// Prefix is the precomputed state of a Digest after hashing a constant prefix,
+// such as a namespace, from which the hash values of many suffixes are computed
+// without hashing the prefix again.
+//
+// A Prefix is never modified once created and is safe for concurrent use.
+type Prefix struct {
+	d Digest
+}
+
+// NewPrefix returns the Prefix of prefix hashed with seed.
+func NewPrefix(prefix []byte, seed uint64) *Prefix {
+	p := &Prefix{}
+	NewInto(&p.d, seed).Write(prefix)
+	return p
+}
+
+// Checksum returns the 64bits Hash value of the prefix followed by suffix.
+// It does not allocate.
+func (p *Prefix) Checksum(suffix []byte) uint64 {
+	d := p.d
+	d.Write(suffix)
+	return d.Sum64()
+}
+
+// ChecksumString returns the 64bits Hash value of the prefix followed by suffix.
+// It does not copy suffix.
+func (p *Prefix) ChecksumString(suffix string) uint64 {
+	return p.Checksum(unsafe.Slice(unsafe.StringData(suffix), len(suffix)))
+}
+
+// Into sets d to the state after hashing the prefix and returns it,
+// so that the suffix can be written to d in several parts.
+func (p *Prefix) Into(d *Digest) *Digest {
+	*d = p.d
+	return d
+}
@@ -0,0 +1,46 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestPrefix(t *testing.T) {
+	const seed = 0xCAFE
+	data := testvectors.Input(300)
+	for _, pn := range []int{0, 5, 32, 45, 100} {
+		p := xxHash64.NewPrefix(data[:pn], seed)
+		for _, sn := range []int{0, 3, 19, 32, 200} {
+			full := data[:pn+sn]
+			suffix := full[pn:]
+			want := xxHash64.Checksum(full, seed)
+			if got := p.Checksum(suffix); got != want {
+				t.Errorf("prefix %d, suffix %d: got %x expected %x", pn, sn, got, want)
+			}
+			if got := p.ChecksumString(string(suffix)); got != want {
+				t.Errorf("prefix %d, suffix %d: got %x from string expected %x", pn, sn, got, want)
+			}
+
+			var d xxHash64.Digest
+			p.Into(&d).Write(suffix[:sn/2])
+			d.Write(suffix[sn/2:])
+			if got := d.Sum64(); got != want {
+				t.Errorf("prefix %d, suffix %d: got %x from digest expected %x", pn, sn, got, want)
+			}
+		}
+	}
+
+	p := xxHash64.NewPrefix(data[:45], seed)
+	if n := testing.AllocsPerRun(100, func() { p.Checksum(data) }); n != 0 {
+		t.Errorf("got %f allocations", n)
+	}
+}
+
+func Benchmark_XXH64_Prefix(b *testing.B) {
+	p := xxHash64.NewPrefix([]byte("namespace/tenant/"), 0)
+	for n := 0; n < b.N; n++ {
+		p.Checksum(testdata1)
+	}
+}
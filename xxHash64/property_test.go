@@ -0,0 +1,58 @@
+package xxHash64_test
+
+import (
+	"encoding"
+	"math/rand"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// TestProperties checks that random sequences of Write, Sum, Reset and clone calls
+// always agree with Checksum over the data written since the last Reset.
+func TestProperties(t *testing.T) {
+	data := make([]byte, 1<<12)
+	for i := 0; i < 200; i++ {
+		rnd := rand.New(rand.NewSource(int64(i)))
+		rnd.Read(data)
+		seed := uint64(rnd.Uint64())
+		xxh := xxHash64.New(seed)
+		var written []byte
+		var ops []string
+		for op := 0; op < 100; op++ {
+			switch r := rnd.Intn(10); {
+			case r < 6:
+				// Mostly short writes to hit every buffer state, sometimes longer ones.
+				n := rnd.Intn(70)
+				if r == 0 {
+					n = rnd.Intn(len(data))
+				}
+				xxh.Write(data[:n])
+				written = append(written, data[:n]...)
+				ops = append(ops, "write")
+			case r < 9:
+				ops = append(ops, "sum")
+				if h, want := xxh.Sum64(), xxHash64.Checksum(written, seed); h != want {
+					t.Fatalf("seed %d after %v: got 0x%x expected 0x%x", i, ops, h, want)
+				}
+			case rnd.Intn(2) == 0:
+				// Clone the state and carry on with the clone, leaving the original untouched.
+				state, err := xxh.(encoding.BinaryMarshaler).MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				clone := xxHash64.New(0)
+				if err := clone.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+					t.Fatal(err)
+				}
+				xxh.Write(data)
+				xxh = clone
+				ops = append(ops, "clone")
+			default:
+				xxh.Reset()
+				written = written[:0]
+				ops = append(ops, "reset")
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package xxHash64
+
+import "math/bits"
+
+// Sample reports whether key is part of a sample of the given rate, in [0, 1].
+// The decision is stable: it only depends on key, rate and salt, so that services
+// sharing the same salt sample the same keys. Keys sampled at a given rate
+// are also sampled at any higher rate.
+//
+// The key is sampled if its Checksum, seeded with salt, is lower than rate * 2^64.
+// As rate has a 53 bits mantissa, the threshold is exact and a uniformly distributed
+// Checksum is sampled with a probability of exactly rate.
+// Rates below 2^-64 are rounded down to 0.
+func Sample(key []byte, rate float64, salt uint64) bool {
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	}
+	return Checksum(key, salt) < uint64(rate*(1<<64))
+}
+
+// ShouldSample is Sample, under the name used by tracing and logging libraries.
+// The whole 64bits Checksum is compared with the threshold, no modulo is involved,
+// so that services written in other languages reach the same decision
+// by comparing the unsigned XXH64 value of key with rate * 2^64.
+func ShouldSample(key []byte, rate float64, salt uint64) bool {
+	return Sample(key, rate, salt)
+}
+
+// SampleRatio reports whether key is part of a sample of the rate num/den,
+// for rates that cannot be represented exactly by a float64, such as 1/3.
+// It panics if den is zero. Ratios greater than 1 sample all keys.
+//
+// The key is sampled if its Checksum h, seeded with salt, satisfies h * den < num * 2^64,
+// so that the sampling probability differs from num/den by less than 2^-64.
+// Keys sampled at a given ratio are also sampled at any higher ratio.
+func SampleRatio(key []byte, num, den uint64, salt uint64) bool {
+	if den == 0 {
+		panic("xxHash64: invalid sampling ratio")
+	}
+	if num >= den {
+		return true
+	}
+	hi, _ := bits.Mul64(Checksum(key, salt), den)
+	return hi < num
+}
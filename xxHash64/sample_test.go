@@ -0,0 +1,75 @@
+package xxHash64_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestSample(t *testing.T) {
+	const keys = 100000
+	for _, rate := range []float64{-1, 0, 0.01, 0.5, 0.9, 1, 2} {
+		var n int
+		for i := 0; i < keys; i++ {
+			key := []byte(fmt.Sprint(i))
+			if xxHash64.Sample(key, rate, 123) {
+				n++
+				if !xxHash64.Sample(key, rate+0.05, 123) {
+					t.Fatalf("key %d sampled at rate %f but not at a higher rate", i, rate)
+				}
+			}
+		}
+		want := rate
+		if want < 0 {
+			want = 0
+		} else if want > 1 {
+			want = 1
+		}
+		if got := float64(n) / keys; got < want-0.01 || got > want+0.01 {
+			t.Errorf("rate %f: sampled %f of the keys", rate, got)
+		}
+	}
+}
+
+func TestShouldSample(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprint(i))
+		for _, rate := range []float64{0, 0.1, 0.5, 1} {
+			if xxHash64.ShouldSample(key, rate, 7) != xxHash64.Sample(key, rate, 7) {
+				t.Fatalf("key %d: ShouldSample and Sample disagree at rate %f", i, rate)
+			}
+		}
+	}
+}
+
+func TestSampleRatio(t *testing.T) {
+	const keys = 100000
+	for _, r := range [][2]uint64{{0, 1}, {1, 3}, {1, 2}, {2, 3}, {1, 1}, {5, 3}} {
+		var n int
+		for i := 0; i < keys; i++ {
+			key := []byte(fmt.Sprint(i))
+			if xxHash64.SampleRatio(key, r[0], r[1], 123) {
+				n++
+				if !xxHash64.SampleRatio(key, r[0]+1, r[1], 123) {
+					t.Fatalf("key %d sampled at ratio %d/%d but not at a higher ratio", i, r[0], r[1])
+				}
+			}
+		}
+		want := float64(r[0]) / float64(r[1])
+		if want > 1 {
+			want = 1
+		}
+		if got := float64(n) / keys; got < want-0.01 || got > want+0.01 {
+			t.Errorf("ratio %d/%d: sampled %f of the keys", r[0], r[1], got)
+		}
+	}
+
+	// Power of two ratios match Sample.
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprint(i))
+		if xxHash64.SampleRatio(key, 1, 4, 9) != xxHash64.Sample(key, 0.25, 9) {
+			t.Fatalf("key %d: SampleRatio and Sample disagree", i)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package xxHash64
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"hash"
+)
+
+// Seed is a random seed, mirroring hash/maphash.Seed.
+// Use MakeSeed to create one: the zero Seed is the deterministic zero seed.
+//
+// Functions taking an explicit uint64 seed remain available for deterministic hashing.
+type Seed struct {
+	v uint64
+}
+
+// MakeSeed returns a new Seed from a cryptographically secure random source.
+func MakeSeed() Seed {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("xxHash64: cannot generate seed: " + err.Error())
+	}
+	return Seed{v: binary.LittleEndian.Uint64(b[:])}
+}
+
+// NewWithSeed returns a new Hash64 instance using seed.
+func NewWithSeed(seed Seed) hash.Hash64 {
+	return New(seed.v)
+}
+
+// Bytes returns the 64bits Hash value of b using seed.
+func Bytes(seed Seed, b []byte) uint64 {
+	return Checksum(b, seed.v)
+}
+
+// String returns the 64bits Hash value of s using seed.
+func String(seed Seed, s string) uint64 {
+	return ChecksumString(s, seed.v)
+}
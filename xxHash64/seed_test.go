@@ -0,0 +1,31 @@
+package xxHash64_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestSeed(t *testing.T) {
+	s1, s2 := xxHash64.MakeSeed(), xxHash64.MakeSeed()
+	if s1 == s2 {
+		t.Fatalf("got identical random seeds")
+	}
+	data := []byte("abcdefghijklmnopqrstuvwxyz0123456789")
+	if xxHash64.Bytes(s1, data) == xxHash64.Bytes(s2, data) {
+		t.Errorf("got identical hashes with different seeds")
+	}
+	if got, want := xxHash64.String(s1, string(data)), xxHash64.Bytes(s1, data); got != want {
+		t.Errorf("String: got 0x%x expected 0x%x", got, want)
+	}
+	xxh := xxHash64.NewWithSeed(s1)
+	xxh.Write(data)
+	if got, want := xxh.Sum64(), xxHash64.Bytes(s1, data); got != want {
+		t.Errorf("NewWithSeed: got 0x%x expected 0x%x", got, want)
+	}
+
+	var zero xxHash64.Seed
+	if got, want := xxHash64.Bytes(zero, data), xxHash64.Checksum(data, 0); got != want {
+		t.Errorf("zero Seed: got 0x%x expected 0x%x", got, want)
+	}
+}
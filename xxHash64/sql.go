@@ -0,0 +1,60 @@
+package xxHash64
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements the database/sql/driver.Valuer interface,
+// storing h as its 16 hexadecimal digits canonical form.
+func (h Hash64) Value() (driver.Value, error) {
+	return h.String(), nil
+}
+
+// Scan implements the database/sql.Scanner interface.
+// It accepts the hexadecimal form of a hash, as a string or bytes, as well as an int64.
+func (h *Hash64) Scan(src interface{}) error {
+	v, err := scanHash(src)
+	if err != nil {
+		return err
+	}
+	*h = Hash64(v)
+	return nil
+}
+
+// Hash64Int is a Hash64 stored in SQL columns as an int64,
+// the two's complement of the hash value, for databases without unsigned integers.
+type Hash64Int Hash64
+
+// String returns the canonical form of h.
+func (h Hash64Int) String() string {
+	return Hash64(h).String()
+}
+
+// Value implements the database/sql/driver.Valuer interface.
+func (h Hash64Int) Value() (driver.Value, error) {
+	return int64(h), nil
+}
+
+// Scan implements the database/sql.Scanner interface.
+// It accepts an int64 as well as the hexadecimal form of a hash, as a string or bytes.
+func (h *Hash64Int) Scan(src interface{}) error {
+	v, err := scanHash(src)
+	if err != nil {
+		return err
+	}
+	*h = Hash64Int(v)
+	return nil
+}
+
+func scanHash(src interface{}) (uint64, error) {
+	switch v := src.(type) {
+	case int64:
+		return uint64(v), nil
+	case string:
+		return ParseHex(v)
+	case []byte:
+		return ParseHex(string(v))
+	}
+	return 0, fmt.Errorf("xxHash64: cannot scan %T into a hash", src)
+}
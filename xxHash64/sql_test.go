@@ -0,0 +1,47 @@
+package xxHash64_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var (
+	_ driver.Valuer = xxHash64.Hash64(0)
+	_ sql.Scanner   = (*xxHash64.Hash64)(nil)
+	_ driver.Valuer = xxHash64.Hash64Int(0)
+	_ sql.Scanner   = (*xxHash64.Hash64Int)(nil)
+)
+
+func TestSQL(t *testing.T) {
+	const want = 0xfedcba9876543210
+
+	v, err := xxHash64.Hash64(want).Value()
+	if err != nil || v != "fedcba9876543210" {
+		t.Errorf("Hash64: got %v, %v", v, err)
+	}
+	v, err = xxHash64.Hash64Int(want).Value()
+	if err != nil || v != int64(-81985529216486896) {
+		t.Errorf("Hash64Int: got %v, %v", v, err)
+	}
+
+	for _, src := range []interface{}{"fedcba9876543210", []byte("FEDCBA9876543210"), int64(-81985529216486896)} {
+		var h xxHash64.Hash64
+		if err := h.Scan(src); err != nil || h != want {
+			t.Errorf("Hash64 scan %v: got 0x%x, %v", src, uint64(h), err)
+		}
+		var hi xxHash64.Hash64Int
+		if err := hi.Scan(src); err != nil || hi != want {
+			t.Errorf("Hash64Int scan %v: got 0x%x, %v", src, uint64(hi), err)
+		}
+	}
+
+	var h xxHash64.Hash64
+	for _, src := range []interface{}{nil, 1.5, "xyz"} {
+		if err := h.Scan(src); err == nil {
+			t.Errorf("scan %v: got no error", src)
+		}
+	}
+}
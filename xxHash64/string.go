@@ -0,0 +1,9 @@
+package xxHash64
+
+import "unsafe"
+
+// ChecksumString returns the 64bits Hash value of s.
+// It does not copy s.
+func ChecksumString(s string, seed uint64) uint64 {
+	return Checksum(unsafe.Slice(unsafe.StringData(s), len(s)), seed)
+}
@@ -0,0 +1,71 @@
+package xxHash64
+
+import (
+	"hash"
+	"sync"
+)
+
+// SyncHash is a Hash64 safe for concurrent use by multiple goroutines.
+// Each Write is applied atomically, so data written concurrently is hashed
+// in the order the Writes acquire the SyncHash, without interleaving.
+//
+// Clone takes a snapshot of the state that can be used independently.
+type SyncHash struct {
+	mu  sync.Mutex
+	xxh Digest
+}
+
+// NewSync returns a new SyncHash instance using seed.
+func NewSync(seed uint64) *SyncHash {
+	s := &SyncHash{xxh: Digest{seed: seed}}
+	s.xxh.Reset()
+	return s
+}
+
+// Write adds input bytes to the Hash.
+// It never returns an error.
+func (s *SyncHash) Write(input []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.xxh.Write(input)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (s *SyncHash) Sum(b []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.xxh.Sum(b)
+}
+
+// Sum64 returns the 64bits Hash value.
+func (s *SyncHash) Sum64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.xxh.Sum64()
+}
+
+// Reset resets the Hash to its initial state.
+func (s *SyncHash) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.xxh.Reset()
+}
+
+// Size returns the number of bytes returned by Sum().
+func (s *SyncHash) Size() int {
+	return 8
+}
+
+// BlockSize gives the minimum number of bytes accepted by Write().
+func (s *SyncHash) BlockSize() int {
+	return 1
+}
+
+// Clone returns a copy of the current state as a new Hash64, which is not safe for concurrent use.
+func (s *SyncHash) Clone() hash.Hash64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	xxh := s.xxh
+	return &xxh
+}
@@ -0,0 +1,86 @@
+package xxHash64_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// TestSyncHash hammers a SyncHash from several goroutines, which is reported by the race detector if unsafe.
+func TestSyncHash(t *testing.T) {
+	const (
+		workers = 8
+		writes  = 200
+	)
+	// All the writes are identical, so that the result does not depend on their order
+	// and every snapshot is the hash of a whole number of them, plus one for the clones.
+	chunk := testvectors.Input(37)
+	prefixes := map[uint64]bool{}
+	for i := 0; i <= workers*writes+1; i++ {
+		prefixes[xxHash64.Checksum(bytes.Repeat(chunk, i), 0xCAFE)] = true
+	}
+
+	s := xxHash64.NewSync(0xCAFE)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				s.Write(chunk)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writes/10; i++ {
+				if h := s.Sum64(); !prefixes[h] {
+					t.Errorf("invalid Sum64 0x%x", h)
+					return
+				}
+				c := s.Clone()
+				h := c.Sum64()
+				c.Write(chunk)
+				if !prefixes[h] || !prefixes[c.Sum64()] {
+					t.Errorf("invalid clone")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h, want := s.Sum64(), xxHash64.Checksum(bytes.Repeat(chunk, workers*writes), 0xCAFE); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+	s.Reset()
+	if h, want := s.Sum64(), xxHash64.Checksum(nil, 0xCAFE); h != want {
+		t.Errorf("got 0x%x expected 0x%x after Reset", h, want)
+	}
+}
+
+// TestConcurrentChecksum checks that the functions can be called concurrently.
+func TestConcurrentChecksum(t *testing.T) {
+	data := testvectors.Input(1000)
+	want := xxHash64.Checksum(data, 1)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if h := xxHash64.Checksum(data, 1); h != want {
+					t.Errorf("got 0x%x expected 0x%x", h, want)
+					return
+				}
+				if h := xxHash64.ChecksumString(string(data), 1); h != want {
+					t.Errorf("got 0x%x expected 0x%x", h, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
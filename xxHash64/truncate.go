@@ -0,0 +1,52 @@
+package xxHash64
+
+import "math"
+
+// Truncate returns the bits most significant bits of h, in [1, 64].
+// Keeping the most significant bits makes the hexadecimal form of the short hash
+// a prefix of the canonical form of h when bits is a multiple of 4.
+// It panics if bits is out of range.
+func Truncate(h uint64, bits int) uint64 {
+	if bits < 1 || bits > 64 {
+		panic("xxHash64: invalid number of bits")
+	}
+	return h >> (64 - bits)
+}
+
+// Short32 returns the 32bits short hash of h.
+func Short32(h uint64) uint32 {
+	return uint32(h >> 32)
+}
+
+// Short48 returns the 48bits short hash of h.
+func Short48(h uint64) uint64 {
+	return h >> 16
+}
+
+// CollisionProbability returns the probability that at least two of n distinct
+// inputs share the same bits short hash, using the birthday approximation 1 - e^(-n(n-1)/2^(bits+1)).
+func CollisionProbability(n uint64, bits int) float64 {
+	if n < 2 {
+		return 0
+	}
+	x := float64(n) * float64(n-1) / math.Ldexp(2, bits)
+	return -math.Expm1(-x)
+}
+
+// MaxItems returns the largest number of distinct inputs whose bits short hashes
+// collide with a probability not exceeding p, in (0, 1).
+func MaxItems(bits int, p float64) uint64 {
+	switch {
+	case p <= 0:
+		return 1
+	case p >= 1:
+		return math.MaxUint64
+	}
+	// Solve n(n-1) = -2^(bits+1) ln(1-p) for n.
+	x := -math.Log1p(-p) * math.Ldexp(2, bits)
+	n := math.Floor((1 + math.Sqrt(1+4*x)) / 2)
+	if n >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(n)
+}
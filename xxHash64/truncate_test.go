@@ -0,0 +1,51 @@
+package xxHash64_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+func TestTruncate(t *testing.T) {
+	h := xxHash64.Checksum([]byte("abc"), 0)
+	full := xxHash64.Hash64(h).String()
+	for _, bits := range []int{4, 32, 48, 64} {
+		short := xxHash64.Truncate(h, bits)
+		if bits < 64 && short>>bits != 0 {
+			t.Errorf("%d bits: got 0x%x", bits, short)
+		}
+		hex := xxHash64.Hash64(short).String()[16-bits/4:]
+		if !strings.HasPrefix(full, hex) {
+			t.Errorf("%d bits: %s is not a prefix of %s", bits, hex, full)
+		}
+	}
+	if got, want := uint64(xxHash64.Short32(h)), xxHash64.Truncate(h, 32); got != want {
+		t.Errorf("Short32: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxHash64.Short48(h), xxHash64.Truncate(h, 48); got != want {
+		t.Errorf("Short48: got 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	// About 77k items for a 50% chance of collision with 32 bits.
+	if p := xxHash64.CollisionProbability(77163, 32); math.Abs(p-0.5) > 0.001 {
+		t.Errorf("got %v expected 0.5", p)
+	}
+	if p := xxHash64.CollisionProbability(1, 8); p != 0 {
+		t.Errorf("got %v expected 0", p)
+	}
+	for _, bits := range []int{16, 32, 48} {
+		for _, p := range []float64{1e-6, 0.01, 0.5} {
+			n := xxHash64.MaxItems(bits, p)
+			if got := xxHash64.CollisionProbability(n, bits); got > p {
+				t.Errorf("%d bits, p=%v: %d items collide with probability %v", bits, p, n, got)
+			}
+			if got := xxHash64.CollisionProbability(n+1, bits); got <= p {
+				t.Errorf("%d bits, p=%v: %d items collide with probability %v", bits, p, n+1, got)
+			}
+		}
+	}
+}
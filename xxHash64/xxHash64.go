@@ -1,5 +1,10 @@
 // Package xxHash64 implements the very fast xxHash hashing algorithm (64 bits version).
 // (https://github.com/Cyan4973/xxHash/)
+//
+// The functions of this package and the hash values, such as Hash64 and Canonical64,
+// are safe for concurrent use. The digests, such as the ones returned by New, Chunked,
+// Digest, Background, Hash and Multiset, are not and must be used by one goroutine at a time.
+// SyncHash is the digest that can be shared by several goroutines.
 package xxHash64
 
 import "hash"
@@ -12,7 +17,11 @@ const (
 	prime64_5 = 2870177450012600261
 )
 
-type xxHash struct {
+// Digest is the 64bits xxHash digest returned by New, implementing hash.Hash64.
+// It can be embedded in other structures or reused with NewInto to avoid
+// the allocation of New. The zero Digest is not ready for use: it must be
+// initialized by NewInto.
+type Digest struct {
 	seed     uint64
 	v1       uint64
 	v2       uint64
@@ -25,20 +34,28 @@ type xxHash struct {
 
 // New returns a new Hash64 instance.
 func New(seed uint64) hash.Hash64 {
-	xxh := &xxHash{seed: seed}
+	xxh := &Digest{seed: seed}
 	xxh.Reset()
 	return xxh
 }
 
+// NewInto initializes d with seed, discarding its previous state, and returns it.
+// It does not allocate.
+func NewInto(d *Digest, seed uint64) *Digest {
+	d.seed = seed
+	d.Reset()
+	return d
+}
+
 // Sum appends the current hash to b and returns the resulting slice.
 // It does not change the underlying hash state.
-func (xxh xxHash) Sum(b []byte) []byte {
+func (xxh *Digest) Sum(b []byte) []byte {
 	h64 := xxh.Sum64()
 	return append(b, byte(h64), byte(h64>>8), byte(h64>>16), byte(h64>>24), byte(h64>>32), byte(h64>>40), byte(h64>>48), byte(h64>>56))
 }
 
 // Reset resets the Hash to its initial state.
-func (xxh *xxHash) Reset() {
+func (xxh *Digest) Reset() {
 	xxh.v1 = xxh.seed + prime64_1 + prime64_2
 	xxh.v2 = xxh.seed + prime64_2
 	xxh.v3 = xxh.seed
@@ -48,23 +65,42 @@ func (xxh *xxHash) Reset() {
 }
 
 // Size returns the number of bytes returned by Sum().
-func (xxh *xxHash) Size() int {
+func (xxh *Digest) Size() int {
 	return 8
 }
 
 // BlockSize gives the minimum number of bytes accepted by Write().
-func (xxh *xxHash) BlockSize() int {
+func (xxh *Digest) BlockSize() int {
 	return 1
 }
 
+// Len returns the number of bytes written since the last Reset.
+func (xxh *Digest) Len() uint64 {
+	return xxh.totalLen
+}
+
 // Write adds input bytes to the Hash.
 // It never returns an error.
-func (xxh *xxHash) Write(input []byte) (int, error) {
+func (xxh *Digest) Write(input []byte) (int, error) {
 	n := len(input)
 	m := xxh.bufused
 
 	xxh.totalLen += uint64(n)
 
+	if m == 0 && n&31 == 0 {
+		// Block aligned input: hash it in place, with no copy into the buffer.
+		v1, v2, v3, v4 := xxh.v1, xxh.v2, xxh.v3, xxh.v4
+		for n, p := n-32, 0; p <= n; p += 32 {
+			b := (*[32]byte)(input[p:]) // no bounds checks in the loop
+			v1 = rol31(v1+u64(b[:8])*prime64_2) * prime64_1
+			v2 = rol31(v2+u64(b[8:16])*prime64_2) * prime64_1
+			v3 = rol31(v3+u64(b[16:24])*prime64_2) * prime64_1
+			v4 = rol31(v4+u64(b[24:])*prime64_2) * prime64_1
+		}
+		xxh.v1, xxh.v2, xxh.v3, xxh.v4 = v1, v2, v3, v4
+		return n, nil
+	}
+
 	r := len(xxh.buf) - m
 	if n < r {
 		copy(xxh.buf[m:], input)
@@ -72,125 +108,139 @@ func (xxh *xxHash) Write(input []byte) (int, error) {
 		return n, nil
 	}
 
-	p := 0
 	if m > 0 {
 		// some data left from previous update
-		copy(xxh.buf[xxh.bufused:], input[:r])
-		xxh.bufused += len(input) - r
+		copy(xxh.buf[m:], input[:r])
+		input = input[r:]
 
 		// fast rotl(31)
 		xxh.v1 = rol31(xxh.v1+u64(xxh.buf[:])*prime64_2) * prime64_1
 		xxh.v2 = rol31(xxh.v2+u64(xxh.buf[8:])*prime64_2) * prime64_1
 		xxh.v3 = rol31(xxh.v3+u64(xxh.buf[16:])*prime64_2) * prime64_1
 		xxh.v4 = rol31(xxh.v4+u64(xxh.buf[24:])*prime64_2) * prime64_1
-		p = r
-		xxh.bufused = 0
 	}
 
 	// Causes compiler to work directly from registers instead of stack:
 	v1, v2, v3, v4 := xxh.v1, xxh.v2, xxh.v3, xxh.v4
-	for n := n - 32; p <= n; p += 32 {
-		sub := input[p:][:32] //BCE hint for compiler
-		v1 = rol31(v1+u64(sub[:])*prime64_2) * prime64_1
-		v2 = rol31(v2+u64(sub[8:])*prime64_2) * prime64_1
-		v3 = rol31(v3+u64(sub[16:])*prime64_2) * prime64_1
-		v4 = rol31(v4+u64(sub[24:])*prime64_2) * prime64_1
+	p := 0
+	for n := len(input) - 32; p <= n; p += 32 {
+		b := (*[32]byte)(input[p:]) // no bounds checks in the loop
+		v1 = rol31(v1+u64(b[:8])*prime64_2) * prime64_1
+		v2 = rol31(v2+u64(b[8:16])*prime64_2) * prime64_1
+		v3 = rol31(v3+u64(b[16:24])*prime64_2) * prime64_1
+		v4 = rol31(v4+u64(b[24:])*prime64_2) * prime64_1
 	}
 	xxh.v1, xxh.v2, xxh.v3, xxh.v4 = v1, v2, v3, v4
 
-	copy(xxh.buf[xxh.bufused:], input[p:])
-	xxh.bufused += len(input) - p
+	xxh.bufused = copy(xxh.buf[:], input[p:])
 
 	return n, nil
 }
 
 // Sum64 returns the 64bits Hash value.
-func (xxh *xxHash) Sum64() uint64 {
+// It does not change the underlying hash state.
+func (xxh *Digest) Sum64() uint64 {
 	var h64 uint64
 	if xxh.totalLen >= 32 {
-		h64 = rol1(xxh.v1) + rol7(xxh.v2) + rol12(xxh.v3) + rol18(xxh.v4)
+		v1, v2, v3, v4 := xxh.v1, xxh.v2, xxh.v3, xxh.v4
+		h64 = rol1(v1) + rol7(v2) + rol12(v3) + rol18(v4)
 
-		xxh.v1 *= prime64_2
-		xxh.v2 *= prime64_2
-		xxh.v3 *= prime64_2
-		xxh.v4 *= prime64_2
+		v1 *= prime64_2
+		v2 *= prime64_2
+		v3 *= prime64_2
+		v4 *= prime64_2
 
-		h64 = (h64^(rol31(xxh.v1)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(xxh.v2)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(xxh.v3)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(xxh.v4)*prime64_1))*prime64_1 + prime64_4
+		h64 = (h64^(rol31(v1)*prime64_1))*prime64_1 + prime64_4
+		h64 = (h64^(rol31(v2)*prime64_1))*prime64_1 + prime64_4
+		h64 = (h64^(rol31(v3)*prime64_1))*prime64_1 + prime64_4
+		h64 = (h64^(rol31(v4)*prime64_1))*prime64_1 + prime64_4
 
 		h64 += xxh.totalLen
 	} else {
 		h64 = xxh.seed + prime64_5 + xxh.totalLen
 	}
 
+	// Finalize straight from the pending bytes in the buffer.
+	buf := xxh.buf[:xxh.bufused]
+	n := len(buf)
 	p := 0
-	n := xxh.bufused
 	for n := n - 8; p <= n; p += 8 {
-		h64 ^= rol31(u64(xxh.buf[p:p+8])*prime64_2) * prime64_1
+		h64 ^= rol31(u64(buf[p:p+8])*prime64_2) * prime64_1
 		h64 = rol27(h64)*prime64_1 + prime64_4
 	}
 	if p+4 <= n {
-		sub := xxh.buf[p : p+4]
-		h64 ^= uint64(u32(sub)) * prime64_1
+		h64 ^= uint64(u32(buf[p:p+4])) * prime64_1
 		h64 = rol23(h64)*prime64_2 + prime64_3
 		p += 4
 	}
-	for ; p < n; p++ {
-		h64 ^= uint64(xxh.buf[p]) * prime64_5
+	for _, c := range buf[p:] {
+		h64 ^= uint64(c) * prime64_5
 		h64 = rol11(h64) * prime64_1
 	}
 
-	h64 ^= h64 >> 33
-	h64 *= prime64_2
-	h64 ^= h64 >> 29
-	h64 *= prime64_3
-	h64 ^= h64 >> 32
-
-	return h64
+	return avalanche(h64)
 }
 
 // Checksum returns the 64bits Hash value.
 func Checksum(input []byte, seed uint64) uint64 {
+	if len(input) >= 32 {
+		return checksumLong(input, seed+prime64_1+prime64_2, seed+prime64_2, seed, seed-prime64_1)
+	}
+	// Short inputs are only made of the tail: hashing them here keeps
+	// the function frameless and free of the block state setup.
+	// It is not inlined at call sites though: the tail loops alone exceed
+	// the inlining budget of the gc compiler.
 	n := len(input)
-	var h64 uint64
+	h64 := seed + prime64_5 + uint64(n)
+	p := 0
+	for n := n - 8; p <= n; p += 8 {
+		sub := input[p : p+8]
+		h64 ^= rol31(u64(sub)*prime64_2) * prime64_1
+		h64 = rol27(h64)*prime64_1 + prime64_4
+	}
+	if p+4 <= n {
+		sub := input[p : p+4]
+		h64 ^= uint64(u32(sub)) * prime64_1
+		h64 = rol23(h64)*prime64_2 + prime64_3
+		p += 4
+	}
+	for ; p < n; p++ {
+		h64 ^= uint64(input[p]) * prime64_5
+		h64 = rol11(h64) * prime64_1
+	}
 
-	if n >= 32 {
-		v1 := seed + prime64_1 + prime64_2
-		v2 := seed + prime64_2
-		v3 := seed
-		v4 := seed - prime64_1
-		p := 0
-		for n := n - 32; p <= n; p += 32 {
-			sub := input[p:][:32] //BCE hint for compiler
-			v1 = rol31(v1+u64(sub[:])*prime64_2) * prime64_1
-			v2 = rol31(v2+u64(sub[8:])*prime64_2) * prime64_1
-			v3 = rol31(v3+u64(sub[16:])*prime64_2) * prime64_1
-			v4 = rol31(v4+u64(sub[24:])*prime64_2) * prime64_1
-		}
+	return avalanche(h64)
+}
 
-		h64 = rol1(v1) + rol7(v2) + rol12(v3) + rol18(v4)
+// checksumLong returns the 64bits Hash value of an input of at least 32 bytes
+// given the initial state v1 to v4.
+func checksumLong(input []byte, v1, v2, v3, v4 uint64) uint64 {
+	p := 0
+	for n := len(input) - 32; p <= n; p += 32 {
+		b := (*[32]byte)(input[p:]) // no bounds checks in the loop
+		v1 = rol31(v1+u64(b[:8])*prime64_2) * prime64_1
+		v2 = rol31(v2+u64(b[8:16])*prime64_2) * prime64_1
+		v3 = rol31(v3+u64(b[16:24])*prime64_2) * prime64_1
+		v4 = rol31(v4+u64(b[24:])*prime64_2) * prime64_1
+	}
 
-		v1 *= prime64_2
-		v2 *= prime64_2
-		v3 *= prime64_2
-		v4 *= prime64_2
+	h64 := rol1(v1) + rol7(v2) + rol12(v3) + rol18(v4)
 
-		h64 = (h64^(rol31(v1)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(v2)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(v3)*prime64_1))*prime64_1 + prime64_4
-		h64 = (h64^(rol31(v4)*prime64_1))*prime64_1 + prime64_4
+	v1 *= prime64_2
+	v2 *= prime64_2
+	v3 *= prime64_2
+	v4 *= prime64_2
 
-		h64 += uint64(n)
+	h64 = (h64^(rol31(v1)*prime64_1))*prime64_1 + prime64_4
+	h64 = (h64^(rol31(v2)*prime64_1))*prime64_1 + prime64_4
+	h64 = (h64^(rol31(v3)*prime64_1))*prime64_1 + prime64_4
+	h64 = (h64^(rol31(v4)*prime64_1))*prime64_1 + prime64_4
 
-		input = input[p:]
-		n -= p
-	} else {
-		h64 = seed + prime64_5 + uint64(n)
-	}
+	h64 += uint64(len(input))
 
-	p := 0
+	input = input[p:]
+	n := len(input)
+	p = 0
 	for n := n - 8; p <= n; p += 8 {
 		sub := input[p : p+8]
 		h64 ^= rol31(u64(sub)*prime64_2) * prime64_1
@@ -207,13 +257,7 @@ func Checksum(input []byte, seed uint64) uint64 {
 		h64 = rol11(h64) * prime64_1
 	}
 
-	h64 ^= h64 >> 33
-	h64 *= prime64_2
-	h64 ^= h64 >> 29
-	h64 *= prime64_3
-	h64 ^= h64 >> 32
-
-	return h64
+	return avalanche(h64)
 }
 
 func u64(buf []byte) uint64 {
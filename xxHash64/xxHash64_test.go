@@ -2,10 +2,13 @@ package xxHash64_test
 
 import (
 	"encoding/binary"
+	"fmt"
+	"hash"
 	"hash/crc64"
 	"hash/fnv"
 	"testing"
 
+	"github.com/pierrec/xxHash/testvectors"
 	"github.com/pierrec/xxHash/xxHash64"
 )
 
@@ -69,6 +72,10 @@ func TestData(t *testing.T) {
 			t.Errorf("test %d: xxh64(%s)=0x%x expected 0x%x", i, td.printable, h, td.sum)
 			t.FailNow()
 		}
+		if h := xxHash64.ChecksumString(td.data, 0); h != td.sum {
+			t.Errorf("test %d: xxh64(%s)=0x%x expected 0x%x", i, td.printable, h, td.sum)
+			t.FailNow()
+		}
 	}
 }
 
@@ -100,6 +107,50 @@ func TestSum(t *testing.T) {
 	}
 }
 
+func TestWriteAligned(t *testing.T) {
+	data := testvectors.Input(testvectors.MaxLen)
+	for _, split := range []int{0, 1, 31, 32} {
+		for _, size := range []int{32, 64, 256} {
+			h := xxHash64.New(7)
+			h.Write(data[:split])
+			p := split
+			for ; p+size <= len(data); p += size {
+				h.Write(data[p : p+size])
+			}
+			h.Write(data[p:])
+			if got, want := h.Sum64(), xxHash64.Checksum(data, 7); got != want {
+				t.Errorf("split %d, size %d: got %x expected %x", split, size, got, want)
+			}
+		}
+	}
+}
+
+func TestNewInto(t *testing.T) {
+	data := testvectors.Input(100)
+	var d xxHash64.Digest
+	var _ hash.Hash64 = &d
+	for _, seed := range []uint64{0, 1, testvectors.Prime64} {
+		// The state left by the previous seed is discarded.
+		if got := xxHash64.NewInto(&d, seed); got != &d {
+			t.Fatal("NewInto did not return its argument")
+		}
+		d.Write(data)
+		if got, want := d.Sum64(), xxHash64.Checksum(data, seed); got != want {
+			t.Errorf("seed %d: got %x expected %x", seed, got, want)
+		}
+		if d.Len() != uint64(len(data)) {
+			t.Errorf("seed %d: got length %d expected %d", seed, d.Len(), len(data))
+		}
+		d.Reset()
+		if d.Len() != 0 {
+			t.Errorf("seed %d: got length %d after Reset", seed, d.Len())
+		}
+		if got, want := d.Sum64(), xxHash64.Checksum(nil, seed); got != want {
+			t.Errorf("seed %d: got %x after Reset expected %x", seed, got, want)
+		}
+	}
+}
+
 func TestReset(t *testing.T) {
 	xxh := xxHash64.New(0)
 	for i, td := range testdata {
@@ -148,3 +199,56 @@ func Benchmark_Fnv64(b *testing.B) {
 		h.Reset()
 	}
 }
+
+func Benchmark_XXH64_WriteAligned(b *testing.B) {
+	data := make([]byte, 4<<10)
+	h := xxHash64.New(0)
+	b.SetBytes(int64(len(data)))
+	for n := 0; n < b.N; n++ {
+		h.Write(data)
+	}
+}
+
+func Benchmark_XXH64_WriteAligned64(b *testing.B) {
+	data := make([]byte, 64)
+	h := xxHash64.New(0)
+	b.SetBytes(int64(len(data)))
+	for n := 0; n < b.N; n++ {
+		h.Write(data)
+	}
+}
+
+func Benchmark_XXH64_ChecksumShort(b *testing.B) {
+	for _, n := range []int{0, 2, 4, 8, 12, 16, 24, 31} {
+		data := testdata1[:n]
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				xxHash64.Checksum(data, 0)
+			}
+		})
+	}
+}
+
+func Benchmark_XXH64_Sum(b *testing.B) {
+	h := xxHash64.New(0)
+	h.Write(testdata1)
+	buf := make([]byte, 0, 8)
+	for n := 0; n < b.N; n++ {
+		buf = h.Sum(buf[:0])
+	}
+}
+
+func Benchmark_XXH64_ChecksumMixed(b *testing.B) {
+	// Short inputs of varying sizes defeat the branch predictor.
+	data := testvectors.Input(32)
+	inputs := make([][]byte, 1024)
+	gen := uint32(1)
+	for i := range inputs {
+		gen = gen*1664525 + 1013904223
+		inputs[i] = data[:gen>>27]
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		xxHash64.Checksum(inputs[n&1023], 0)
+	}
+}
@@ -0,0 +1,194 @@
+// Package xxhbatch computes and verifies xxHash64 (https://github.com/Cyan4973/xxHash/) checksums
+// of record batches stored in length prefixed binary logs, as done by log structured storage
+// engines such as Kafka with CRC32-C.
+//
+// The position of the length and of the checksum in the batch header is described by a Layout.
+// The checksum is stored as a 64bits integer and covers the batch from a given offset to its end,
+// which must follow the checksum.
+package xxhbatch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// DefaultMaxBatchSize is the default maximum size of a batch read by a Reader.
+const DefaultMaxBatchSize = 64 << 20
+
+var (
+	// ErrShortBatch is returned for a batch shorter than its header or than its length says.
+	ErrShortBatch = errors.New("xxhbatch: short batch")
+	// ErrBatchTooLarge is returned by a Reader reading a batch larger than its maximum batch size.
+	ErrBatchTooLarge = errors.New("xxhbatch: batch too large")
+)
+
+// ChecksumError is returned when verifying a batch that does not match its checksum.
+type ChecksumError struct {
+	Want, Got uint64
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("xxhbatch: checksum mismatch: got %016x expected %016x", e.Got, e.Want)
+}
+
+// Layout describes the header of the batches.
+type Layout struct {
+	// ByteOrder of the length and the checksum.
+	ByteOrder binary.ByteOrder
+	// LengthOffset is the offset of the length in the header.
+	LengthOffset int
+	// LengthSize is the size of the length in bytes: 2, 4 or 8.
+	LengthSize int
+	// LengthBase is the number of bytes of the batch not counted by its length:
+	// the size of the batch is LengthBase + length.
+	LengthBase int
+	// ChecksumOffset is the offset of the checksum in the header.
+	ChecksumOffset int
+	// CoveredOffset is the offset at which the bytes covered by the checksum start.
+	CoveredOffset int
+	// Seed of the checksum.
+	Seed uint64
+}
+
+// KafkaLayout mirrors the header of Kafka record batches, a 64bits checksum replacing
+// the partition leader epoch, the magic byte and the CRC32-C:
+// the base offset (8 bytes), the length of the rest of the batch (4 bytes),
+// then the checksum (8 bytes) of the rest of the batch, in big endian.
+var KafkaLayout = Layout{
+	ByteOrder:      binary.BigEndian,
+	LengthOffset:   8,
+	LengthSize:     4,
+	LengthBase:     12,
+	ChecksumOffset: 12,
+	CoveredOffset:  20,
+}
+
+// HeaderSize returns the number of bytes needed to read the length and the checksum of a batch.
+// It panics if the Layout is invalid.
+func (l Layout) HeaderSize() int {
+	if l.ByteOrder == nil || l.LengthSize != 2 && l.LengthSize != 4 && l.LengthSize != 8 ||
+		l.LengthOffset < 0 || l.ChecksumOffset < 0 || l.LengthBase < 0 || l.CoveredOffset < l.ChecksumOffset+8 ||
+		l.LengthOffset < l.ChecksumOffset+8 && l.ChecksumOffset < l.LengthOffset+l.LengthSize {
+		panic("xxhbatch: invalid layout")
+	}
+	n := l.LengthOffset + l.LengthSize
+	if m := l.ChecksumOffset + 8; m > n {
+		n = m
+	}
+	return n
+}
+
+// Size returns the size of the batch whose header starts header.
+func (l Layout) Size(header []byte) (int64, error) {
+	h := l.HeaderSize()
+	if len(header) < h {
+		return 0, ErrShortBatch
+	}
+	b := header[l.LengthOffset:]
+	var n uint64
+	switch l.LengthSize {
+	case 2:
+		n = uint64(l.ByteOrder.Uint16(b))
+	case 4:
+		n = uint64(l.ByteOrder.Uint32(b))
+	default:
+		n = l.ByteOrder.Uint64(b)
+	}
+	size := int64(n) + int64(l.LengthBase)
+	if n > 1<<62 || size < int64(l.CoveredOffset) || size < int64(h) {
+		return 0, ErrShortBatch
+	}
+	return size, nil
+}
+
+// batch returns the batch starting b, checking it is complete.
+func (l Layout) batch(b []byte) ([]byte, error) {
+	size, err := l.Size(b)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) < size {
+		return nil, ErrShortBatch
+	}
+	return b[:size], nil
+}
+
+// Seal computes the checksum of the batch starting b and stores it in its header.
+func (l Layout) Seal(b []byte) error {
+	batch, err := l.batch(b)
+	if err != nil {
+		return err
+	}
+	l.ByteOrder.PutUint64(batch[l.ChecksumOffset:], xxHash64.Checksum(batch[l.CoveredOffset:], l.Seed))
+	return nil
+}
+
+// Verify checks the batch starting b against its checksum and returns it.
+// It returns ErrShortBatch if the batch is incomplete and a *ChecksumError if it is corrupted.
+func (l Layout) Verify(b []byte) ([]byte, error) {
+	batch, err := l.batch(b)
+	if err != nil {
+		return nil, err
+	}
+	want := l.ByteOrder.Uint64(batch[l.ChecksumOffset:])
+	if got := xxHash64.Checksum(batch[l.CoveredOffset:], l.Seed); got != want {
+		return nil, &ChecksumError{Want: want, Got: got}
+	}
+	return batch, nil
+}
+
+// Reader reads and verifies the batches of a log.
+type Reader struct {
+	r      io.Reader
+	l      Layout
+	max    int64
+	header int
+	buf    []byte
+}
+
+// NewReader returns a Reader reading batches with layout l from r,
+// up to maxBatchSize bytes long, DefaultMaxBatchSize if not positive.
+func NewReader(r io.Reader, l Layout, maxBatchSize int) *Reader {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+	return &Reader{r: r, l: l, max: int64(maxBatchSize), header: l.HeaderSize()}
+}
+
+// Next reads and verifies the next batch, which is only valid until the next call.
+// It returns io.EOF at the end of the log and io.ErrUnexpectedEOF if the last batch is truncated,
+// as happens when a crash interrupts a write.
+func (r *Reader) Next() ([]byte, error) {
+	if cap(r.buf) < r.header {
+		r.buf = make([]byte, r.header)
+	}
+	if _, err := io.ReadFull(r.r, r.buf[:r.header]); err != nil {
+		return nil, err
+	}
+	size, err := r.l.Size(r.buf[:r.header])
+	if err != nil {
+		return nil, err
+	}
+	if size > r.max {
+		return nil, ErrBatchTooLarge
+	}
+	if int64(cap(r.buf)) < size {
+		buf := make([]byte, size)
+		copy(buf, r.buf[:r.header])
+		r.buf = buf
+	}
+	batch := r.buf[:size]
+	if int64(r.header) < size {
+		if _, err := io.ReadFull(r.r, batch[r.header:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+	return r.l.Verify(batch)
+}
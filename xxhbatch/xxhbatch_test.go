@@ -0,0 +1,138 @@
+package xxhbatch_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxhbatch"
+)
+
+// kafkaBatch returns a sealed batch with the KafkaLayout holding records.
+func kafkaBatch(t *testing.T, offset uint64, records string) []byte {
+	t.Helper()
+	b := make([]byte, 20, 20+len(records))
+	binary.BigEndian.PutUint64(b, offset)
+	binary.BigEndian.PutUint32(b[8:], uint32(8+len(records)))
+	b = append(b, records...)
+	if err := xxhbatch.KafkaLayout.Seal(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSealVerify(t *testing.T) {
+	l := xxhbatch.KafkaLayout
+	b := kafkaBatch(t, 42, "some records")
+	// Trailing data belongs to the next batch.
+	got, err := l.Verify(append(b, "next"...))
+	if err != nil || !bytes.Equal(got, b) {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if size, err := l.Size(b); err != nil || size != int64(len(b)) {
+		t.Errorf("got size %d, %v expected %d", size, err, len(b))
+	}
+
+	// The base offset is not covered by the checksum.
+	b[0]++
+	if _, err := l.Verify(b); err != nil {
+		t.Error(err)
+	}
+	b[len(b)-1]++
+	var cerr *xxhbatch.ChecksumError
+	if _, err := l.Verify(b); !errors.As(err, &cerr) {
+		t.Errorf("got error %v expected a checksum error", err)
+	}
+	if _, err := l.Verify(b[:len(b)-1]); err != xxhbatch.ErrShortBatch {
+		t.Errorf("got error %v expected %v", err, xxhbatch.ErrShortBatch)
+	}
+	if _, err := l.Verify(b[:10]); err != xxhbatch.ErrShortBatch {
+		t.Errorf("got error %v expected %v", err, xxhbatch.ErrShortBatch)
+	}
+}
+
+func TestCustomLayout(t *testing.T) {
+	// Little endian checksum first, then a 2 bytes length counting the whole batch.
+	l := xxhbatch.Layout{
+		ByteOrder:      binary.LittleEndian,
+		ChecksumOffset: 0,
+		LengthOffset:   8,
+		LengthSize:     2,
+		CoveredOffset:  8,
+		Seed:           1,
+	}
+	if n := l.HeaderSize(); n != 10 {
+		t.Errorf("got header size %d expected 10", n)
+	}
+	b := make([]byte, 10, 20)
+	b = append(b, "records"...)
+	binary.LittleEndian.PutUint16(b[8:], uint16(len(b)))
+	if err := l.Seal(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Verify(b); err != nil {
+		t.Error(err)
+	}
+	// The length is covered.
+	b[8]--
+	if _, err := l.Verify(b); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestInvalidLayout(t *testing.T) {
+	for _, l := range []xxhbatch.Layout{
+		{},
+		{ByteOrder: binary.BigEndian, LengthSize: 3, ChecksumOffset: 4, CoveredOffset: 12},
+		{ByteOrder: binary.BigEndian, LengthSize: 4, ChecksumOffset: 2, CoveredOffset: 10},
+		{ByteOrder: binary.BigEndian, LengthSize: 4, ChecksumOffset: 4, CoveredOffset: 11},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v: expected a panic", l)
+				}
+			}()
+			l.HeaderSize()
+		}()
+	}
+}
+
+func TestReader(t *testing.T) {
+	var log bytes.Buffer
+	records := []string{"a", "", "some records"}
+	for i, rec := range records {
+		log.Write(kafkaBatch(t, uint64(i), rec))
+	}
+	data := log.Bytes()
+
+	r := xxhbatch.NewReader(bytes.NewReader(data), xxhbatch.KafkaLayout, 0)
+	for i, rec := range records {
+		b, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b[20:]); got != rec || binary.BigEndian.Uint64(b) != uint64(i) {
+			t.Errorf("got batch %q expected %q", got, rec)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("got error %v expected %v", err, io.EOF)
+	}
+
+	r = xxhbatch.NewReader(bytes.NewReader(data[:len(data)-1]), xxhbatch.KafkaLayout, 0)
+	r.Next()
+	r.Next()
+	if _, err := r.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("got error %v expected %v", err, io.ErrUnexpectedEOF)
+	}
+
+	r = xxhbatch.NewReader(bytes.NewReader(data), xxhbatch.KafkaLayout, 25)
+	r.Next()
+	r.Next()
+	if _, err := r.Next(); err != xxhbatch.ErrBatchTooLarge {
+		t.Errorf("got error %v expected %v", err, xxhbatch.ErrBatchTooLarge)
+	}
+}
@@ -0,0 +1,85 @@
+package xxhfile
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"io"
+	"path"
+	"strings"
+)
+
+// HashTar returns the manifest of the regular files of the tar archive read from r,
+// without extracting it. Entries are named by their cleaned slash separated path,
+// without leading "./" or "/", so that the manifest of an archive is equal to the
+// HashFS manifest of its extracted content. If several entries have the same name,
+// the last one wins, as it would when extracting the archive.
+// The aggregate hash of the archive content is the Sum64 of the manifest.
+//
+// opts.Progress is called for each entry, with the size of the entry as the total.
+func HashTar(ctx context.Context, r io.Reader, opts Options) (Manifest, error) {
+	m := Manifest{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := entryName(hdr.Name)
+		if name == "" {
+			continue
+		}
+		h, err := ChecksumWith(ctx, tr, hdr.Size, opts)
+		if err != nil {
+			return nil, err
+		}
+		m[name] = h
+	}
+}
+
+// HashZip is like HashTar but for the zip archive of size bytes read from r.
+func HashZip(ctx context.Context, r io.ReaderAt, size int64, opts Options) (Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	m := Manifest{}
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		name := entryName(f.Name)
+		if name == "" {
+			continue
+		}
+		h, err := hashZipFile(ctx, f, opts)
+		if err != nil {
+			return nil, err
+		}
+		m[name] = h
+	}
+	return m, nil
+}
+
+func hashZipFile(ctx context.Context, f *zip.File, opts Options) (uint64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	// Reading the whole entry verifies its CRC-32.
+	return ChecksumWith(ctx, rc, int64(f.UncompressedSize64), opts)
+}
+
+// entryName returns the manifest path of an archive entry name,
+// or an empty string if it does not name a file.
+func entryName(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	return strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,94 @@
+package xxhfile_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+// archiveFiles are the files of the test archives, in order.
+var archiveFiles = []struct {
+	name string
+	data []byte
+}{
+	{"./a.txt", []byte("a")},
+	{"dir/b.txt", []byte("b")},
+	{"/dir/sub/../c.txt", testvectors.Input(100000)},
+	{"a.txt", []byte("a2")},
+}
+
+// archiveManifest returns the manifest expected from the test archives.
+func archiveManifest(t *testing.T, seed uint64) xxhfile.Manifest {
+	fsys := testFS()
+	fsys["a.txt"].Data = []byte("a2")
+	fsys["dir/c.txt"] = fsys["dir/sub/c.txt"]
+	m, err := xxhfile.HashFS(fsys, xxhfile.FSOptions{
+		Seed: seed,
+		Skip: func(path string, _ fs.DirEntry) bool { return path == "dir/sub" || path == "skip" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestHashTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0o755})
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "a.txt"})
+	for _, f := range archiveFiles {
+		tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(f.data))})
+		tw.Write(f.data)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := xxhfile.HashTar(context.Background(), &buf, xxhfile.Options{Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := archiveManifest(t, 1)
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v expected %v", m, want)
+	}
+	if m.Sum64(1) != want.Sum64(1) {
+		t.Error("aggregate hashes differ")
+	}
+}
+
+func TestHashZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.Create("dir/")
+	for i, f := range archiveFiles {
+		method := zip.Deflate
+		if i%2 == 0 {
+			method = zip.Store
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(f.data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := xxhfile.HashZip(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()), xxhfile.Options{Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := archiveManifest(t, 1); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v expected %v", m, want)
+	}
+}
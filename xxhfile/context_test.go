@@ -0,0 +1,38 @@
+package xxhfile_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+// cancelReader cancels its context after the first read.
+type cancelReader struct {
+	sliceReader
+	cancel func()
+}
+
+func (r *cancelReader) Read(buf []byte) (int, error) {
+	defer r.cancel()
+	return r.sliceReader.Read(buf[:1])
+}
+
+func TestChecksumContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &cancelReader{sliceReader{testvectors.Input(100)}, cancel}
+	if _, err := xxhfile.ChecksumContext(ctx, r, 0); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+	if len(r.data) != 99 {
+		t.Errorf("got %d bytes left expected 99", len(r.data))
+	}
+
+	if _, err := xxhfile.HashFSContext(ctx, testFS(), xxhfile.FSOptions{}); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+	if _, err := xxhfile.VerifyContext(ctx, testFS(), nil, xxhfile.FSOptions{}); err != context.Canceled {
+		t.Errorf("got error %v expected %v", err, context.Canceled)
+	}
+}
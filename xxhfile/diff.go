@@ -0,0 +1,60 @@
+package xxhfile
+
+import (
+	"context"
+	"io/fs"
+	"sort"
+)
+
+// Changes lists the differences between two manifests, each list being sorted.
+type Changes struct {
+	// Added holds the paths only present in the new manifest.
+	Added []string
+	// Removed holds the paths only present in the old manifest.
+	Removed []string
+	// Changed holds the paths present in both manifests with different hashes.
+	Changed []string
+}
+
+// Empty reports whether there are no differences.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Diff returns the differences from the old manifest to the new one.
+func Diff(old, new Manifest) Changes {
+	var c Changes
+	for path, h := range new {
+		switch oh, ok := old[path]; {
+		case !ok:
+			c.Added = append(c.Added, path)
+		case oh != h:
+			c.Changed = append(c.Changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			c.Removed = append(c.Removed, path)
+		}
+	}
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Changed)
+	return c
+}
+
+// Verify hashes fsys with opts and returns its differences with the expected manifest m:
+// files added to fsys, removed from it or whose content changed.
+// opts must be identical to the ones used to build m.
+func Verify(fsys fs.FS, m Manifest, opts FSOptions) (Changes, error) {
+	return VerifyContext(context.Background(), fsys, m, opts)
+}
+
+// VerifyContext is like Verify but returns ctx.Err() if ctx is done before all the files are hashed.
+func VerifyContext(ctx context.Context, fsys fs.FS, m Manifest, opts FSOptions) (Changes, error) {
+	current, err := HashFSContext(ctx, fsys, opts)
+	if err != nil {
+		return Changes{}, err
+	}
+	return Diff(m, current), nil
+}
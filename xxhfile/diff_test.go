@@ -0,0 +1,61 @@
+package xxhfile_test
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+func TestDiff(t *testing.T) {
+	old := xxhfile.Manifest{"a": 1, "b": 2, "c": 3}
+	new := xxhfile.Manifest{"a": 1, "b": 20, "d": 4, "e": 5}
+	got := xxhfile.Diff(old, new)
+	want := xxhfile.Changes{
+		Added:   []string{"d", "e"},
+		Removed: []string{"c"},
+		Changed: []string{"b"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v expected %+v", got, want)
+	}
+	if got.Empty() {
+		t.Error("expected changes")
+	}
+	if c := xxhfile.Diff(old, old); !c.Empty() {
+		t.Errorf("got %+v expected no changes", c)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	fsys := testFS()
+	opts := xxhfile.FSOptions{Seed: 1}
+	m, err := xxhfile.HashFS(fsys, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := xxhfile.Verify(fsys, m, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Empty() {
+		t.Errorf("got %+v expected no changes", c)
+	}
+
+	fsys["a.txt"] = &fstest.MapFile{Data: []byte("modified")}
+	fsys["new.txt"] = &fstest.MapFile{Data: []byte("new")}
+	delete(fsys, "dir/b.txt")
+	c, err = xxhfile.Verify(fsys, m, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := xxhfile.Changes{
+		Added:   []string{"new.txt"},
+		Removed: []string{"dir/b.txt"},
+		Changed: []string{"a.txt"},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v expected %+v", c, want)
+	}
+}
@@ -0,0 +1,117 @@
+package xxhfile
+
+import (
+	"context"
+	"encoding/binary"
+	"io/fs"
+	"sort"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Manifest maps the slash separated paths of files to the 64bits Hash value of their content.
+type Manifest map[string]uint64
+
+// Paths returns the sorted paths of m.
+func (m Manifest) Paths() []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Sum64 returns the 64bits Hash value of m, a fingerprint of the whole tree.
+// It is the Checksum of the sorted entries, each encoded as the length of its path
+// and its hash as 8 little endian bytes, with the path in between.
+func (m Manifest) Sum64(seed uint64) uint64 {
+	xxh := xxHash64.New(seed)
+	var buf [8]byte
+	for _, path := range m.Paths() {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(path)))
+		xxh.Write(buf[:])
+		xxh.Write([]byte(path))
+		binary.LittleEndian.PutUint64(buf[:], m[path])
+		xxh.Write(buf[:])
+	}
+	return xxh.Sum64()
+}
+
+// FSOptions configures the hashing of file systems.
+type FSOptions struct {
+	// Seed is the seed of the file hashes.
+	Seed uint64
+	// Root is the directory to hash, "." if empty.
+	// Paths are relative to the root of the file system, not to Root.
+	Root string
+	// Skip, if not nil, is called for every file and directory.
+	// Files for which it returns true are not hashed, directories are not walked.
+	Skip func(path string, d fs.DirEntry) bool
+	// Limiter, if not nil, limits the rate at which files are read.
+	Limiter Limiter
+}
+
+// HashFS returns the manifest of the regular files of fsys.
+// Other files, such as symbolic links, are ignored.
+func HashFS(fsys fs.FS, opts FSOptions) (Manifest, error) {
+	return HashFSContext(context.Background(), fsys, opts)
+}
+
+// HashFSContext is like HashFS but returns ctx.Err() if ctx is done before all the files are hashed.
+func HashFSContext(ctx context.Context, fsys fs.FS, opts FSOptions) (Manifest, error) {
+	m := Manifest{}
+	err := WalkFSContext(ctx, fsys, opts, func(path string, h uint64) error {
+		m[path] = h
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WalkFS hashes the regular files of fsys in lexical order, calling fn for each of them.
+// Walking stops at the first error, including those returned by fn.
+func WalkFS(fsys fs.FS, opts FSOptions, fn func(path string, h uint64) error) error {
+	return WalkFSContext(context.Background(), fsys, opts, fn)
+}
+
+// WalkFSContext is like WalkFS but returns ctx.Err() if ctx is done before all the files are hashed.
+func WalkFSContext(ctx context.Context, fsys fs.FS, opts FSOptions, fn func(path string, h uint64) error) error {
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.Skip != nil && opts.Skip(path, d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		h, err := hashFSFile(ctx, fsys, path, opts)
+		if err != nil {
+			return err
+		}
+		return fn(path, h)
+	})
+}
+
+func hashFSFile(ctx context.Context, fsys fs.FS, path string, opts FSOptions) (uint64, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return ChecksumWith(ctx, f, -1, Options{Seed: opts.Seed, Limiter: opts.Limiter})
+}
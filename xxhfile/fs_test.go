@@ -0,0 +1,86 @@
+package xxhfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":         {Data: []byte("a")},
+		"dir/b.txt":     {Data: []byte("b")},
+		"dir/sub/c.txt": {Data: testvectors.Input(100000)},
+		"skip/d.txt":    {Data: []byte("d")},
+		"link":          {Data: []byte("a.txt"), Mode: fs.ModeSymlink},
+		"empty":         {Mode: fs.ModeDir},
+	}
+}
+
+func TestHashFS(t *testing.T) {
+	fsys := testFS()
+	m, err := xxhfile.HashFS(fsys, xxhfile.FSOptions{Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := xxhfile.Manifest{}
+	for _, path := range []string{"a.txt", "dir/b.txt", "dir/sub/c.txt", "skip/d.txt"} {
+		want[path] = xxHash64.Checksum(fsys[path].Data, 1)
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v expected %v", m, want)
+	}
+
+	m, err = xxhfile.HashFS(fsys, xxhfile.FSOptions{
+		Seed: 1,
+		Root: "dir",
+		Skip: func(path string, d fs.DirEntry) bool { return path == "dir/sub" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Paths(), []string{"dir/b.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
+
+func TestWalkFS(t *testing.T) {
+	var paths []string
+	errStop := errors.New("stop")
+	err := xxhfile.WalkFS(testFS(), xxhfile.FSOptions{}, func(path string, h uint64) error {
+		paths = append(paths, path)
+		if len(paths) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("got error %v expected %v", err, errStop)
+	}
+	if want := []string{"a.txt", "dir/b.txt"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %v expected %v", paths, want)
+	}
+
+	_, err = xxhfile.HashFS(testFS(), xxhfile.FSOptions{Root: "missing"})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v expected %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestManifestSum64(t *testing.T) {
+	m1 := xxhfile.Manifest{"a": 1, "b": 2}
+	m2 := xxhfile.Manifest{"b": 2, "a": 1}
+	if a, b := m1.Sum64(0), m2.Sum64(0); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+	m2["a"] = 3
+	if a, b := m1.Sum64(0), m2.Sum64(0); a == b {
+		t.Errorf("different manifests: same hash 0x%x", a)
+	}
+}
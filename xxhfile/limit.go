@@ -0,0 +1,55 @@
+package xxhfile
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate at which data is hashed.
+// WaitN blocks until n more bytes can be hashed, or returns an error if ctx is done first.
+// n is at most BufferSize.
+//
+// It is implemented by *rate.Limiter from golang.org/x/time/rate,
+// provided its burst is at least BufferSize.
+type Limiter interface {
+	WaitN(ctx context.Context, n int) error
+}
+
+// NewLimiter returns a Limiter allowing bytesPerSec bytes per second on average.
+// It is safe for concurrent use, so that it can be shared by several hashing jobs.
+// It panics if bytesPerSec is not positive.
+func NewLimiter(bytesPerSec int64) Limiter {
+	if bytesPerSec <= 0 {
+		panic("xxhfile: invalid rate")
+	}
+	return &limiter{rate: float64(bytesPerSec)}
+}
+
+type limiter struct {
+	rate float64
+	mu   sync.Mutex
+	next time.Time // when the next bytes can be hashed
+}
+
+func (l *limiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	l.mu.Unlock()
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
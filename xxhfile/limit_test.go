@@ -0,0 +1,70 @@
+package xxhfile_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+// countingLimiter records the bytes it is waited for.
+type countingLimiter struct {
+	n, calls int
+}
+
+func (l *countingLimiter) WaitN(ctx context.Context, n int) error {
+	if n > xxhfile.BufferSize {
+		panic("WaitN called with more than BufferSize bytes")
+	}
+	l.n += n
+	l.calls++
+	return nil
+}
+
+func TestLimiter(t *testing.T) {
+	data := testvectors.Input(3*xxhfile.BufferSize + 1)
+	l := &countingLimiter{}
+	opts := xxhfile.Options{Seed: 1, Limiter: l}
+	h, err := xxhfile.ChecksumWith(context.Background(), &sliceReader{data}, -1, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := xxHash64.Checksum(data, 1); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+	if l.n != len(data) || l.calls != 4 {
+		t.Errorf("got %d bytes in %d calls expected %d bytes in 4 calls", l.n, l.calls, len(data))
+	}
+
+	l = &countingLimiter{}
+	m, err := xxhfile.HashFS(testFS(), xxhfile.FSOptions{Limiter: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.calls == 0 || len(m) == 0 {
+		t.Errorf("limiter not used when hashing a file system")
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	// Waits of 0, 64ms, 128ms, 192ms and 256ms for 5 reads at 1MB/s.
+	data := testvectors.Input(4*xxhfile.BufferSize + 1000)
+	opts := xxhfile.Options{Limiter: xxhfile.NewLimiter(1 << 20)}
+	start := time.Now()
+	if _, err := xxhfile.ChecksumWith(context.Background(), &sliceReader{data}, -1, opts); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 200*time.Millisecond {
+		t.Errorf("hashed %d bytes in %v at 1MB/s", len(data), d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	opts.Limiter = xxhfile.NewLimiter(1 << 10)
+	if _, err := xxhfile.ChecksumWith(ctx, &sliceReader{data}, -1, opts); err != context.DeadlineExceeded {
+		t.Errorf("got error %v expected %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,71 @@
+package xxhfile_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+// slowReader sleeps before every read.
+type slowReader struct {
+	sliceReader
+	delay time.Duration
+}
+
+func (r *slowReader) Read(buf []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.sliceReader.Read(buf[:100])
+}
+
+func TestProgress(t *testing.T) {
+	data := testvectors.Input(1000)
+	var calls [][2]int64
+	opts := xxhfile.Options{
+		Seed:             1,
+		Progress:         func(done, total int64) { calls = append(calls, [2]int64{done, total}) },
+		ProgressInterval: 5 * time.Millisecond,
+	}
+	r := &slowReader{sliceReader{data}, 3 * time.Millisecond}
+	h, err := xxhfile.ChecksumWith(context.Background(), r, int64(len(data)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := xxHash64.Checksum(data, 1); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+	// 10 reads of at least 3ms with reports every 5ms at most, the final one included.
+	if n := len(calls); n < 2 || n > 10 {
+		t.Errorf("got %d progress reports: %v", n, calls)
+	}
+	for i, c := range calls {
+		if c[1] != int64(len(data)) || i > 0 && c[0] <= calls[i-1][0] {
+			t.Errorf("invalid progress reports: %v", calls)
+			break
+		}
+	}
+	if last := calls[len(calls)-1]; last[0] != int64(len(data)) {
+		t.Errorf("got final report %v expected %d bytes done", last, len(data))
+	}
+}
+
+func TestProgressFile(t *testing.T) {
+	data := testvectors.Input(3*xxhfile.BufferSize + 1)
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var done, total int64
+	opts := xxhfile.Options{Progress: func(d, t int64) { done, total = d, t }}
+	if _, err := xxhfile.ChecksumFileWith(context.Background(), path, opts); err != nil {
+		t.Fatal(err)
+	}
+	if done != int64(len(data)) || total != int64(len(data)) {
+		t.Errorf("got %d/%d expected %d/%d", done, total, len(data), len(data))
+	}
+}
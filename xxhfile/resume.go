@@ -0,0 +1,144 @@
+package xxhfile
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// CheckpointSize is the number of bytes hashed between two saves of the hash state by ResumeFileHash.
+const CheckpointSize = 64 << 20
+
+// ErrStateMismatch is returned when a saved hash state does not match the file being hashed.
+var ErrStateMismatch = errors.New("xxhfile: hash state does not match the file")
+
+const (
+	stateVersion    = 1
+	stateHeaderSize = 1 + 8 + 8 // version, seed, offset
+)
+
+// ResumeFileHash returns the 64bits Hash value of the content of the named file using a zero seed,
+// resuming from the hash state saved in stateFile if it exists.
+//
+// The hash state is saved to stateFile every CheckpointSize bytes and when an error interrupts hashing,
+// so that hashing can be resumed after a crash or a cancellation by calling ResumeFileHash again.
+// The saved prefix length is checked against the size of the file, but the prefix is not hashed again.
+// stateFile is removed once the file has been hashed.
+func ResumeFileHash(path, stateFile string) (uint64, error) {
+	return ResumeFileHashContext(context.Background(), path, stateFile)
+}
+
+// ResumeFileHashContext is like ResumeFileHash but returns ctx.Err() if ctx is done
+// before the file is hashed. ctx is checked before every read.
+func ResumeFileHashContext(ctx context.Context, path, stateFile string) (uint64, error) {
+	return ResumeFileHashWith(ctx, path, stateFile, Options{})
+}
+
+// ResumeFileHashWith is like ResumeFileHashContext but configured by opts.
+// ErrStateMismatch is returned if the state was saved with another seed than opts.Seed.
+func ResumeFileHashWith(ctx context.Context, path, stateFile string, opts Options) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := fi.Size()
+
+	xxh := xxHash64.NewInto(new(xxHash64.Digest), opts.Seed)
+	offset, err := loadState(stateFile, opts.Seed, xxh)
+	if err != nil {
+		return 0, err
+	}
+	if offset > size {
+		return 0, ErrStateMismatch
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	p := newProgress(size, opts)
+	p.n = offset
+	saved := offset
+	save := func() error {
+		if err := saveState(stateFile, opts.Seed, p.n, xxh); err != nil {
+			return err
+		}
+		saved = p.n
+		return nil
+	}
+	checkpoint := func() error {
+		if p.n-saved < CheckpointSize {
+			return nil
+		}
+		return save()
+	}
+	if err := copyBuffer(ctx, xxh, f, p, opts, checkpoint); err != nil {
+		if p.n > saved {
+			// Keep the progress made so far.
+			save()
+		}
+		return 0, err
+	}
+	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return xxh.Sum64(), nil
+}
+
+// loadState restores the hash state saved in stateFile into xxh and returns the length of the hashed prefix.
+// It returns 0 if stateFile does not exist.
+func loadState(stateFile string, seed uint64, xxh *xxHash64.Digest) (int64, error) {
+	data, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < stateHeaderSize || data[0] != stateVersion {
+		return 0, fmt.Errorf("xxhfile: invalid hash state in %s", stateFile)
+	}
+	if binary.LittleEndian.Uint64(data[1:]) != seed {
+		return 0, ErrStateMismatch
+	}
+	offset := int64(binary.LittleEndian.Uint64(data[9:]))
+	if offset < 0 {
+		return 0, fmt.Errorf("xxhfile: invalid hash state in %s", stateFile)
+	}
+	if err := xxh.UnmarshalBinary(data[stateHeaderSize:]); err != nil {
+		return 0, fmt.Errorf("xxhfile: invalid hash state in %s: %w", stateFile, err)
+	}
+	if uint64(offset) != xxh.Len() {
+		// The prefix length does not match the hashed data.
+		return 0, ErrStateMismatch
+	}
+	return offset, nil
+}
+
+// saveState atomically saves the state of xxh after hashing offset bytes to stateFile.
+func saveState(stateFile string, seed uint64, offset int64, xxh *xxHash64.Digest) error {
+	state, err := xxh.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	data := make([]byte, stateHeaderSize, stateHeaderSize+len(state))
+	data[0] = stateVersion
+	binary.LittleEndian.PutUint64(data[1:], seed)
+	binary.LittleEndian.PutUint64(data[9:], uint64(offset))
+	data = append(data, state...)
+
+	tmp := stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, stateFile)
+}
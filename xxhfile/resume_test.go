@@ -0,0 +1,120 @@
+package xxhfile_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+// cancelLimiter cancels hashing after a number of reads.
+type cancelLimiter struct {
+	reads  int
+	cancel context.CancelFunc
+}
+
+func (l *cancelLimiter) WaitN(ctx context.Context, n int) error {
+	if l.reads--; l.reads == 0 {
+		l.cancel()
+	}
+	return ctx.Err()
+}
+
+// interruptedHash starts hashing path and interrupts it after reads reads.
+func interruptedHash(t *testing.T, path, stateFile string, seed uint64, reads int) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := xxhfile.Options{Seed: seed, Limiter: &cancelLimiter{reads, cancel}}
+	if _, err := xxhfile.ResumeFileHashWith(ctx, path, stateFile, opts); err != context.Canceled {
+		t.Fatalf("got error %v expected %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumeFileHash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	stateFile := filepath.Join(dir, "state")
+	data := testvectors.Input(5*xxhfile.BufferSize + 100)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	interruptedHash(t, path, stateFile, 0, 2)
+	var calls [][2]int64
+	opts := xxhfile.Options{Progress: func(done, total int64) { calls = append(calls, [2]int64{done, total}) }}
+	h, err := xxhfile.ResumeFileHashWith(context.Background(), path, stateFile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := xxHash64.Checksum(data, 0); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file not removed: %v", err)
+	}
+	if n := int64(len(data)); len(calls) == 0 || calls[len(calls)-1] != [2]int64{n, n} {
+		t.Errorf("invalid progress reports: %v", calls)
+	}
+
+	// Without state, the file is hashed from the start.
+	if h, err := xxhfile.ResumeFileHash(path, stateFile); err != nil || h != xxHash64.Checksum(data, 0) {
+		t.Errorf("got 0x%x, %v expected 0x%x", h, err, xxHash64.Checksum(data, 0))
+	}
+}
+
+func TestResumeFileHashMismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	stateFile := filepath.Join(dir, "state")
+	data := testvectors.Input(3 * xxhfile.BufferSize)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	interruptedHash(t, path, stateFile, 1, 2)
+	opts := xxhfile.Options{Seed: 2}
+	if _, err := xxhfile.ResumeFileHashWith(context.Background(), path, stateFile, opts); err != xxhfile.ErrStateMismatch {
+		t.Errorf("got error %v expected %v", err, xxhfile.ErrStateMismatch)
+	}
+
+	if err := os.WriteFile(path, data[:xxhfile.BufferSize], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts.Seed = 1
+	if _, err := xxhfile.ResumeFileHashWith(context.Background(), path, stateFile, opts); err != xxhfile.ErrStateMismatch {
+		t.Errorf("got error %v expected %v", err, xxhfile.ErrStateMismatch)
+	}
+
+	// The saved prefix length does not match the restored digest.
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(stateFile)
+	interruptedHash(t, path, stateFile, 1, 2)
+	state, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state[9]++ // low byte of the little-endian prefix length after the version and seed
+	if err := os.WriteFile(stateFile, state, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := xxhfile.ResumeFileHashWith(context.Background(), path, stateFile, opts); err != xxhfile.ErrStateMismatch {
+		t.Errorf("tampered offset: got error %v expected %v", err, xxhfile.ErrStateMismatch)
+	}
+
+	if err := os.WriteFile(stateFile, []byte("invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := xxhfile.ResumeFileHash(path, stateFile); err == nil {
+		t.Error("expected an error for an invalid state")
+	}
+}
@@ -0,0 +1,65 @@
+package xxhfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// XattrName is the name of the extended attribute holding the hash of a file,
+// its canonical hexadecimal 64bits Hash value with a zero seed.
+const XattrName = "user.xxhash64"
+
+var (
+	// ErrXattrUnsupported is returned when the platform or the file system does not support extended attributes.
+	ErrXattrUnsupported = errors.New("xxhfile: extended attributes not supported")
+	// ErrXattrMissing is returned when a file has no hash extended attribute.
+	ErrXattrMissing = errors.New("xxhfile: missing hash extended attribute")
+	// ErrMismatch is returned when the content of a file does not match its hash.
+	ErrMismatch = errors.New("xxhfile: hash mismatch")
+)
+
+// StoreXattr stores h, the hash of the named file computed with a zero seed, in its XattrName extended attribute.
+func StoreXattr(path string, h uint64) error {
+	return setXattr(path, XattrName, []byte(xxHash64.Hash64(h).String()))
+}
+
+// UpdateXattr hashes the named file and stores its hash in its XattrName extended attribute.
+func UpdateXattr(path string) (uint64, error) {
+	h, err := ChecksumFile(path, 0)
+	if err != nil {
+		return 0, err
+	}
+	return h, StoreXattr(path, h)
+}
+
+// ReadXattr returns the hash stored in the XattrName extended attribute of the named file.
+func ReadXattr(path string) (uint64, error) {
+	b, err := getXattr(path, XattrName)
+	if err != nil {
+		return 0, err
+	}
+	h, err := xxHash64.ParseHex(string(b))
+	if err != nil {
+		return 0, fmt.Errorf("xxhfile: invalid hash extended attribute %q", b)
+	}
+	return h, nil
+}
+
+// VerifyXattr hashes the named file and checks its hash against the one stored in its XattrName extended attribute,
+// returning ErrMismatch if they differ.
+func VerifyXattr(path string) error {
+	want, err := ReadXattr(path)
+	if err != nil {
+		return err
+	}
+	h, err := ChecksumFile(path, 0)
+	if err != nil {
+		return err
+	}
+	if h != want {
+		return ErrMismatch
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package xxhfile
+
+import (
+	"os"
+	"syscall"
+)
+
+func setXattr(path, name string, value []byte) error {
+	if err := syscall.Setxattr(path, name, value, 0); err != nil {
+		return xattrError("setxattr", path, err)
+	}
+	return nil
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	// Hashes are 16 bytes long, a larger buffer detects invalid values.
+	buf := make([]byte, 64)
+	n, err := syscall.Getxattr(path, name, buf)
+	if err != nil {
+		return nil, xattrError("getxattr", path, err)
+	}
+	return buf[:n], nil
+}
+
+func xattrError(op, path string, err error) error {
+	switch err {
+	case syscall.ENOTSUP:
+		return ErrXattrUnsupported
+	case syscall.ENODATA:
+		return ErrXattrMissing
+	}
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package xxhfile
+
+func setXattr(path, name string, value []byte) error {
+	return ErrXattrUnsupported
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	return nil, ErrXattrUnsupported
+}
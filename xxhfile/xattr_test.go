@@ -0,0 +1,56 @@
+package xxhfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+func TestXattr(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	data := testvectors.Input(1000)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := xxhfile.VerifyXattr(path)
+	switch err {
+	case xxhfile.ErrXattrUnsupported:
+		t.Skip(err)
+	case xxhfile.ErrXattrMissing:
+	default:
+		t.Fatalf("got error %v expected %v", err, xxhfile.ErrXattrMissing)
+	}
+
+	h, err := xxhfile.UpdateXattr(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := xxHash64.Checksum(data, 0); h != want {
+		t.Errorf("got 0x%x expected 0x%x", h, want)
+	}
+	if got, err := xxhfile.ReadXattr(path); err != nil || got != h {
+		t.Errorf("got 0x%x, %v expected 0x%x", got, err, h)
+	}
+	if err := xxhfile.VerifyXattr(path); err != nil {
+		t.Error(err)
+	}
+
+	data[0]++
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := xxhfile.VerifyXattr(path); err != xxhfile.ErrMismatch {
+		t.Errorf("got error %v expected %v", err, xxhfile.ErrMismatch)
+	}
+	if err := xxhfile.StoreXattr(path, xxHash64.Checksum(data, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := xxhfile.VerifyXattr(path); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package xxhfile computes xxHash64 (https://github.com/Cyan4973/xxHash/) hashes of files.
+package xxhfile
+
+import (
+	"context"
+	"hash"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// BufferSize is the size of the buffer used to read files.
+const BufferSize = 64 << 10
+
+// DefaultProgressInterval is the default minimum interval between two progress reports.
+const DefaultProgressInterval = 100 * time.Millisecond
+
+// Options configures the hashing of files and streams.
+type Options struct {
+	// Seed is the seed of the hash.
+	Seed uint64
+	// Progress, if not nil, is called with the number of bytes hashed so far and
+	// the total number of bytes to hash, or -1 if it is unknown.
+	// It is called at most once every ProgressInterval while hashing,
+	// and once all the data has been hashed.
+	Progress func(done, total int64)
+	// ProgressInterval is the minimum interval between two calls to Progress.
+	// DefaultProgressInterval is used if it is not positive.
+	ProgressInterval time.Duration
+	// Limiter, if not nil, limits the rate at which data is read.
+	Limiter Limiter
+}
+
+// ChecksumFile returns the 64bits Hash value of the content of the named file.
+//
+// The file is read sequentially with a BufferSize buffer and closed before returning.
+// Short reads are handled by reading until io.EOF, and reads interrupted by
+// signals (EINTR) are retried by the os package.
+func ChecksumFile(path string, seed uint64) (uint64, error) {
+	return ChecksumFileContext(context.Background(), path, seed)
+}
+
+// ChecksumFileContext is like ChecksumFile but returns ctx.Err() if ctx is done
+// before the file is hashed. ctx is checked before every read.
+func ChecksumFileContext(ctx context.Context, path string, seed uint64) (uint64, error) {
+	return ChecksumFileWith(ctx, path, Options{Seed: seed})
+}
+
+// ChecksumFileWith is like ChecksumFileContext but configured by opts.
+// The total size reported to opts.Progress is the size of the file when it is opened.
+func ChecksumFileWith(ctx context.Context, path string, opts Options) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	size := int64(-1)
+	if opts.Progress != nil {
+		fi, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		if fi.Mode().IsRegular() {
+			size = fi.Size()
+		}
+	}
+	return ChecksumWith(ctx, f, size, opts)
+}
+
+// Checksum returns the 64bits Hash value of the data read from r until io.EOF.
+func Checksum(r io.Reader, seed uint64) (uint64, error) {
+	return ChecksumContext(context.Background(), r, seed)
+}
+
+// ChecksumContext is like Checksum but returns ctx.Err() if ctx is done
+// before io.EOF is reached. ctx is checked before every read.
+func ChecksumContext(ctx context.Context, r io.Reader, seed uint64) (uint64, error) {
+	return ChecksumWith(ctx, r, -1, Options{Seed: seed})
+}
+
+// ChecksumWith is like ChecksumContext but configured by opts.
+// size is the number of bytes expected from r, reported to opts.Progress, or -1 if it is unknown.
+func ChecksumWith(ctx context.Context, r io.Reader, size int64, opts Options) (uint64, error) {
+	xxh := xxHash64.New(opts.Seed)
+	if err := copyBuffer(ctx, xxh, r, newProgress(size, opts), opts, nil); err != nil {
+		return 0, err
+	}
+	return xxh.Sum64(), nil
+}
+
+// copyBuffer writes all the data read from r to h, recording it in p.
+// checkpoint, if not nil, is called after every read.
+func copyBuffer(ctx context.Context, h hash.Hash64, r io.Reader, p *progress, opts Options, checkpoint func() error) error {
+	buf := make([]byte, BufferSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := r.Read(buf)
+		h.Write(buf[:n])
+		p.add(n)
+		if opts.Limiter != nil && n > 0 {
+			if err := opts.Limiter.WaitN(ctx, n); err != nil {
+				return err
+			}
+		}
+		if checkpoint != nil && n > 0 {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+		}
+		switch err {
+		case nil:
+		case io.EOF:
+			p.done()
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// progress reports the progress of a hashing job.
+type progress struct {
+	fn       func(done, total int64)
+	interval time.Duration
+	total    int64
+	n        int64
+	reported int64 // last reported n, -1 if none
+	last     time.Time
+}
+
+func newProgress(total int64, opts Options) *progress {
+	p := &progress{fn: opts.Progress, interval: opts.ProgressInterval, total: total, reported: -1}
+	if p.interval <= 0 {
+		p.interval = DefaultProgressInterval
+	}
+	if p.fn != nil {
+		p.last = time.Now()
+	}
+	return p
+}
+
+// add records n more hashed bytes, reporting them if the interval has elapsed.
+func (p *progress) add(n int) {
+	p.n += int64(n)
+	if p.fn == nil || n == 0 {
+		return
+	}
+	if now := time.Now(); now.Sub(p.last) >= p.interval {
+		p.last = now
+		p.report()
+	}
+}
+
+// done reports the final progress, unless it already was.
+func (p *progress) done() {
+	if p.fn != nil && p.reported != p.n {
+		p.report()
+	}
+}
+
+func (p *progress) report() {
+	p.reported = p.n
+	p.fn(p.n, p.total)
+}
@@ -0,0 +1,79 @@
+package xxhfile_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhfile"
+)
+
+func TestChecksumFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, n := range []int{0, 1, xxhfile.BufferSize, 3*xxhfile.BufferSize + 7} {
+		data := testvectors.Input(n)
+		path := filepath.Join(dir, "data")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		h, err := xxhfile.ChecksumFile(path, 0xCAFE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := xxHash64.Checksum(data, 0xCAFE); h != want {
+			t.Errorf("size %d: got 0x%x expected 0x%x", n, h, want)
+		}
+	}
+
+	_, err := xxhfile.ChecksumFile(filepath.Join(dir, "missing"), 0)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v expected %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestChecksumShortReads(t *testing.T) {
+	data := testvectors.Input(1000)
+	want := xxHash64.Checksum(data, 0)
+	for _, r := range []io.Reader{
+		iotest.OneByteReader(bytesReader(data)),
+		iotest.HalfReader(bytesReader(data)),
+		iotest.DataErrReader(bytesReader(data)),
+	} {
+		h, err := xxhfile.Checksum(r, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h != want {
+			t.Errorf("got 0x%x expected 0x%x", h, want)
+		}
+	}
+
+	_, err := xxhfile.Checksum(iotest.TimeoutReader(bytesReader(data)), 0)
+	if err != iotest.ErrTimeout {
+		t.Errorf("got error %v expected %v", err, iotest.ErrTimeout)
+	}
+}
+
+func bytesReader(data []byte) io.Reader {
+	return &sliceReader{data}
+}
+
+// sliceReader is a minimal io.Reader without WriterTo.
+type sliceReader struct {
+	data []byte
+}
+
+func (r *sliceReader) Read(buf []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(buf, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -0,0 +1,54 @@
+package xxhframe
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// ErrShortDatagram is returned when opening a datagram too short to hold its checksum.
+var ErrShortDatagram = errors.New("xxhframe: datagram too short")
+
+// SealDatagram appends the 64bits Hash value of payload as a little endian integer to payload
+// and returns the extended slice, as append does.
+func SealDatagram(payload []byte, seed uint64) []byte {
+	return binary.LittleEndian.AppendUint64(payload, xxHash64.Checksum(payload, seed))
+}
+
+// OpenDatagram verifies a datagram sealed by SealDatagram and returns its payload,
+// which shares the memory of packet.
+// It returns ErrShortDatagram or a *ChecksumError if the datagram is truncated or corrupted.
+func OpenDatagram(packet []byte, seed uint64) ([]byte, error) {
+	if len(packet) < 8 {
+		return nil, ErrShortDatagram
+	}
+	n := len(packet) - 8
+	payload := packet[:n]
+	want := binary.LittleEndian.Uint64(packet[n:])
+	if got := xxHash64.Checksum(payload, seed); got != want {
+		return nil, &ChecksumError{Want: want, Got: got}
+	}
+	return payload, nil
+}
+
+// SealDatagram32 is like SealDatagram but appends the 4 bytes xxHash32 value of payload,
+// trading detection strength for a smaller overhead.
+func SealDatagram32(payload []byte, seed uint32) []byte {
+	return binary.LittleEndian.AppendUint32(payload, xxHash32.Checksum(payload, seed))
+}
+
+// OpenDatagram32 verifies a datagram sealed by SealDatagram32 and returns its payload.
+func OpenDatagram32(packet []byte, seed uint32) ([]byte, error) {
+	if len(packet) < 4 {
+		return nil, ErrShortDatagram
+	}
+	n := len(packet) - 4
+	payload := packet[:n]
+	want := binary.LittleEndian.Uint32(packet[n:])
+	if got := xxHash32.Checksum(payload, seed); got != want {
+		return nil, &ChecksumError{Want: uint64(want), Got: uint64(got)}
+	}
+	return payload, nil
+}
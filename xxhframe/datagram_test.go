@@ -0,0 +1,77 @@
+package xxhframe_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhframe"
+)
+
+func TestDatagram(t *testing.T) {
+	data := testvectors.Input(1000)
+	for _, n := range []int{0, 1, 100, 1000} {
+		payload := append([]byte(nil), data[:n]...)
+		packet := xxhframe.SealDatagram(payload, 0xCAFE)
+		if len(packet) != n+8 {
+			t.Fatalf("got %d bytes expected %d", len(packet), n+8)
+		}
+		p, err := xxhframe.OpenDatagram(packet, 0xCAFE)
+		if err != nil || !bytes.Equal(p, data[:n]) {
+			t.Errorf("%d: got %d bytes, %v", n, len(p), err)
+		}
+
+		packet32 := xxhframe.SealDatagram32(append([]byte(nil), data[:n]...), 0xCAFE)
+		if len(packet32) != n+4 {
+			t.Fatalf("got %d bytes expected %d", len(packet32), n+4)
+		}
+		p, err = xxhframe.OpenDatagram32(packet32, 0xCAFE)
+		if err != nil || !bytes.Equal(p, data[:n]) {
+			t.Errorf("%d: got %d bytes, %v", n, len(p), err)
+		}
+
+		var cerr *xxhframe.ChecksumError
+		packet[len(packet)/2] ^= 0x80
+		if _, err := xxhframe.OpenDatagram(packet, 0xCAFE); !errors.As(err, &cerr) {
+			t.Errorf("%d: got error %v expected a checksum error", n, err)
+		}
+		packet32[len(packet32)/2] ^= 0x80
+		if _, err := xxhframe.OpenDatagram32(packet32, 0xCAFE); !errors.As(err, &cerr) {
+			t.Errorf("%d: got error %v expected a checksum error", n, err)
+		}
+	}
+
+	if _, err := xxhframe.OpenDatagram(make([]byte, 7), 0); err != xxhframe.ErrShortDatagram {
+		t.Errorf("got error %v expected %v", err, xxhframe.ErrShortDatagram)
+	}
+	if _, err := xxhframe.OpenDatagram32(make([]byte, 3), 0); err != xxhframe.ErrShortDatagram {
+		t.Errorf("got error %v expected %v", err, xxhframe.ErrShortDatagram)
+	}
+}
+
+func TestDatagramUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Write(xxhframe.SealDatagram([]byte("telemetry"), 1)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := xxhframe.OpenDatagram(buf[:n], 1); err != nil || string(p) != "telemetry" {
+		t.Errorf("got %q, %v", p, err)
+	}
+}
@@ -0,0 +1,97 @@
+package xxhframe
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// NewMessageWriter returns a writer for a single message, such as a WebSocket message
+// obtained from a NextWriter method, appending the 64bits Hash value of the data written to it
+// as a little endian integer when it is closed, before closing w.
+func NewMessageWriter(w io.WriteCloser, seed uint64) io.WriteCloser {
+	return &messageWriter{w: w, xxh: xxHash64.New(seed)}
+}
+
+type messageWriter struct {
+	w   io.WriteCloser
+	xxh hash.Hash64
+}
+
+func (m *messageWriter) Write(p []byte) (int, error) {
+	n, err := m.w.Write(p)
+	m.xxh.Write(p[:n])
+	return n, err
+}
+
+func (m *messageWriter) Close() error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], m.xxh.Sum64())
+	if _, err := m.w.Write(buf[:]); err != nil {
+		m.w.Close()
+		return err
+	}
+	return m.w.Close()
+}
+
+// NewMessageReader returns a reader for a single message written with NewMessageWriter,
+// such as a WebSocket message obtained from a NextReader method.
+// It returns the message without its checksum, which is verified when reaching the end of r:
+// it then returns a *ChecksumError instead of io.EOF if the message is corrupted,
+// or io.ErrUnexpectedEOF if it is too short to hold a checksum.
+//
+// The data returned before the end of the message is not verified yet.
+func NewMessageReader(r io.Reader, seed uint64) io.Reader {
+	return &messageReader{r: r, xxh: xxHash64.New(seed)}
+}
+
+type messageReader struct {
+	r   io.Reader
+	xxh hash.Hash64
+	buf []byte // data read from r but not returned, the last 8 bytes being the checksum candidate
+	eof bool
+	err error
+}
+
+func (m *messageReader) Read(p []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for !m.eof && len(m.buf) <= 8 {
+		if m.buf == nil {
+			m.buf = make([]byte, 0, 4096)
+		}
+		n, err := m.r.Read(m.buf[len(m.buf):cap(m.buf)])
+		m.buf = m.buf[:len(m.buf)+n]
+		switch err {
+		case nil:
+		case io.EOF:
+			m.eof = true
+		default:
+			return 0, err
+		}
+	}
+	if avail := len(m.buf) - 8; avail > 0 {
+		n := copy(p, m.buf[:avail])
+		m.xxh.Write(p[:n])
+		m.buf = m.buf[:copy(m.buf, m.buf[n:])]
+		return n, nil
+	}
+	// The end of the message is reached with only the checksum left.
+	m.err = io.EOF
+	switch {
+	case len(m.buf) < 8:
+		m.err = io.ErrUnexpectedEOF
+	default:
+		want := binary.LittleEndian.Uint64(m.buf)
+		if got := m.xxh.Sum64(); got != want {
+			m.err = &ChecksumError{Want: want, Got: got}
+		}
+	}
+	return 0, m.err
+}
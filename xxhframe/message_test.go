@@ -0,0 +1,65 @@
+package xxhframe_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhframe"
+)
+
+// message is a buffer standing for a WebSocket message.
+type message struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (m *message) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestMessage(t *testing.T) {
+	data := testvectors.Input(10000)
+	for _, n := range []int{0, 1, 8, 9, 4095, 4096, 10000} {
+		var msg message
+		w := xxhframe.NewMessageWriter(&msg, 1)
+		w.Write(data[:n/2])
+		w.Write(data[n/2 : n])
+		if err := w.Close(); err != nil || !msg.closed {
+			t.Fatalf("%d: got %v, closed %v", n, err, msg.closed)
+		}
+		if msg.Len() != n+8 {
+			t.Fatalf("%d: got %d bytes expected %d", n, msg.Len(), n+8)
+		}
+		packet := msg.Bytes()
+		if !bytes.Equal(packet, xxhframe.SealDatagram(append([]byte(nil), data[:n]...), 1)) {
+			t.Errorf("%d: message differs from the sealed datagram", n)
+		}
+
+		for _, r := range []io.Reader{
+			xxhframe.NewMessageReader(bytes.NewReader(packet), 1),
+			xxhframe.NewMessageReader(iotest.OneByteReader(bytes.NewReader(packet)), 1),
+			iotest.OneByteReader(xxhframe.NewMessageReader(bytes.NewReader(packet), 1)),
+		} {
+			got, err := io.ReadAll(r)
+			if err != nil || !bytes.Equal(got, data[:n]) {
+				t.Errorf("%d: got %d bytes, %v", n, len(got), err)
+			}
+		}
+
+		packet[n/2] ^= 1
+		_, err := io.ReadAll(xxhframe.NewMessageReader(bytes.NewReader(packet), 1))
+		var cerr *xxhframe.ChecksumError
+		if !errors.As(err, &cerr) {
+			t.Errorf("%d: got error %v expected a checksum error", n, err)
+		}
+	}
+
+	if _, err := io.ReadAll(xxhframe.NewMessageReader(bytes.NewReader(make([]byte, 7)), 0)); err != io.ErrUnexpectedEOF {
+		t.Errorf("got error %v expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,140 @@
+// Package xxhframe reads and writes checksummed frames over streams, such as network connections,
+// using xxHash64 (https://github.com/Cyan4973/xxHash/) to detect corrupted frames.
+//
+// A frame is made of the length of its payload as a little endian 32bits integer,
+// the payload, and the 64bits Hash value of the length and the payload as a little endian integer.
+//
+// Datagrams, such as UDP packets, do not need framing and are sealed with a checksum
+// of their payload by SealDatagram or SealDatagram32. Messages of message based protocols,
+// such as WebSocket, are sealed the same way while streaming them with NewMessageWriter and NewMessageReader.
+package xxhframe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+const (
+	headerSize  = 4
+	trailerSize = 8
+)
+
+// DefaultMaxFrameSize is the default maximum size of a frame payload.
+const DefaultMaxFrameSize = 16 << 20
+
+// ErrFrameTooLarge is returned when writing or reading a frame whose payload exceeds the maximum frame size.
+var ErrFrameTooLarge = errors.New("xxhframe: frame too large")
+
+// ChecksumError is returned when reading a frame that does not match its checksum.
+type ChecksumError struct {
+	Want, Got uint64
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("xxhframe: checksum mismatch: got %016x expected %016x", e.Got, e.Want)
+}
+
+// Options configures Readers and Writers.
+type Options struct {
+	// MaxFrameSize is the maximum size of a frame payload, DefaultMaxFrameSize if not positive.
+	MaxFrameSize int
+	// Seed is the seed of the checksums.
+	Seed uint64
+}
+
+func (o Options) maxFrameSize() int {
+	if o.MaxFrameSize <= 0 {
+		return DefaultMaxFrameSize
+	}
+	return o.MaxFrameSize
+}
+
+// Writer writes frames to an io.Writer.
+type Writer struct {
+	w    io.Writer
+	opts Options
+	buf  []byte
+}
+
+// NewWriter returns a new Writer writing frames to w.
+func NewWriter(w io.Writer, opts Options) *Writer {
+	return &Writer{w: w, opts: opts}
+}
+
+// WriteFrame writes a frame holding p with a single Write call to the underlying io.Writer.
+func (w *Writer) WriteFrame(p []byte) error {
+	if len(p) > w.opts.maxFrameSize() || uint64(len(p)) > 1<<32-1 {
+		return ErrFrameTooLarge
+	}
+	w.buf = append(w.buf[:0], 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(w.buf, uint32(len(p)))
+	w.buf = append(w.buf, p...)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, xxHash64.Checksum(w.buf, w.opts.Seed))
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// Reader reads frames from an io.Reader.
+type Reader struct {
+	r    io.Reader
+	opts Options
+	buf  []byte
+}
+
+// NewReader returns a new Reader reading frames from r.
+func NewReader(r io.Reader, opts Options) *Reader {
+	return &Reader{r: r, opts: opts}
+}
+
+// ReadFrame reads the next frame and returns its payload, which is only valid until the next call.
+// It returns io.EOF if there are no more frames, io.ErrUnexpectedEOF if the last frame is truncated,
+// ErrFrameTooLarge if the frame payload exceeds the maximum frame size and
+// a *ChecksumError if the frame is corrupted.
+func (r *Reader) ReadFrame() ([]byte, error) {
+	if cap(r.buf) < headerSize+trailerSize {
+		r.buf = make([]byte, headerSize+trailerSize)
+	}
+	hdr := r.buf[:headerSize]
+	if _, err := io.ReadFull(r.r, hdr); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(hdr)
+	if uint64(n) > uint64(r.opts.maxFrameSize()) {
+		return nil, ErrFrameTooLarge
+	}
+	size := headerSize + int(n) + trailerSize
+	if cap(r.buf) < size {
+		buf := make([]byte, size)
+		copy(buf, hdr)
+		r.buf = buf
+	}
+	frame := r.buf[:size]
+	if _, err := io.ReadFull(r.r, frame[headerSize:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	data := frame[:headerSize+n]
+	want := binary.LittleEndian.Uint64(frame[headerSize+n:])
+	if got := xxHash64.Checksum(data, r.opts.Seed); got != want {
+		return nil, &ChecksumError{Want: want, Got: got}
+	}
+	return data[headerSize:], nil
+}
+
+// Codec reads and writes frames over an io.ReadWriter such as a net.Conn.
+// Reading and writing can be done concurrently, but not several reads or several writes.
+type Codec struct {
+	*Reader
+	*Writer
+}
+
+// NewCodec returns a new Codec reading and writing frames over rw.
+func NewCodec(rw io.ReadWriter, opts Options) *Codec {
+	return &Codec{NewReader(rw, opts), NewWriter(rw, opts)}
+}
@@ -0,0 +1,113 @@
+package xxhframe_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhframe"
+)
+
+func TestFrames(t *testing.T) {
+	data := testvectors.Input(100000)
+	sizes := []int{0, 1, 100, 100000}
+	opts := xxhframe.Options{Seed: 0xCAFE}
+
+	var buf bytes.Buffer
+	w := xxhframe.NewWriter(&buf, opts)
+	for _, n := range sizes {
+		if err := w.WriteFrame(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := len(sizes)*12 + 100101; buf.Len() != want {
+		t.Errorf("got %d bytes expected %d", buf.Len(), want)
+	}
+
+	r := xxhframe.NewReader(&buf, opts)
+	for _, n := range sizes {
+		p, err := r.ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p, data[:n]) {
+			t.Errorf("got %d bytes expected %d", len(p), n)
+		}
+	}
+	if _, err := r.ReadFrame(); err != io.EOF {
+		t.Errorf("got error %v expected %v", err, io.EOF)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	a := xxhframe.NewCodec(c1, xxhframe.Options{})
+	b := xxhframe.NewCodec(c2, xxhframe.Options{})
+	go func() {
+		for {
+			p, err := b.ReadFrame()
+			if err != nil {
+				return
+			}
+			b.WriteFrame(append([]byte("re: "), p...))
+		}
+	}()
+	for _, msg := range []string{"hello", "", "world"} {
+		if err := a.WriteFrame([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		p, err := a.ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(p), "re: "+msg; got != want {
+			t.Errorf("got %q expected %q", got, want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := xxhframe.NewWriter(&buf, xxhframe.Options{MaxFrameSize: 10})
+	if err := w.WriteFrame(testvectors.Input(11)); err != xxhframe.ErrFrameTooLarge {
+		t.Errorf("got error %v expected %v", err, xxhframe.ErrFrameTooLarge)
+	}
+	if err := w.WriteFrame(testvectors.Input(10)); err != nil {
+		t.Fatal(err)
+	}
+	frame := append([]byte(nil), buf.Bytes()...)
+
+	if _, err := xxhframe.NewReader(bytes.NewReader(frame), xxhframe.Options{MaxFrameSize: 9}).ReadFrame(); err != xxhframe.ErrFrameTooLarge {
+		t.Errorf("got error %v expected %v", err, xxhframe.ErrFrameTooLarge)
+	}
+	for _, n := range []int{2, 6, len(frame) - 1} {
+		if _, err := xxhframe.NewReader(bytes.NewReader(frame[:n]), xxhframe.Options{}).ReadFrame(); err != io.ErrUnexpectedEOF {
+			t.Errorf("%d bytes: got error %v expected %v", n, err, io.ErrUnexpectedEOF)
+		}
+	}
+	for _, i := range []int{0, 5, len(frame) - 1} {
+		bad := append([]byte(nil), frame...)
+		bad[i] ^= 1
+		_, err := xxhframe.NewReader(bytes.NewReader(bad), xxhframe.Options{}).ReadFrame()
+		var cerr *xxhframe.ChecksumError
+		if i == 0 {
+			// The length is now 11, past the end of the frame.
+			if err != io.ErrUnexpectedEOF {
+				t.Errorf("byte %d: got error %v expected %v", i, err, io.ErrUnexpectedEOF)
+			}
+			continue
+		}
+		if !errors.As(err, &cerr) || cerr.Want == cerr.Got {
+			t.Errorf("byte %d: got error %v", i, err)
+		}
+	}
+	// Frames written with another seed do not verify.
+	if _, err := xxhframe.NewReader(bytes.NewReader(frame), xxhframe.Options{Seed: 1}).ReadFrame(); err == nil {
+		t.Error("expected a checksum error")
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/pierrec/xxHash/xxhgrpc
+
+go 1.22
+
+require (
+	github.com/pierrec/xxHash v0.0.0-20261017201621-df3f1d9f6ec8
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
+
+// Local development builds against the root module of this repository.
+replace github.com/pierrec/xxHash => ../
@@ -0,0 +1,263 @@
+// Package xxhgrpc provides gRPC interceptors attaching xxHash64 (https://github.com/Cyan4973/xxHash/)
+// checksums of the messages to the metadata of calls and verifying them on receipt,
+// to detect messages corrupted along the way, for instance by proxies.
+//
+// The checksum of a message is the 64bits Hash value with a zero seed of its protocol buffers
+// encoding as sent on the wire, sent in the x-checksum-xxh64 metadata as 16 hexadecimal digits.
+// ServerOptions and DialOptions install the interceptors along with a codec compatible with
+// the standard protocol buffers one, which sends the exact encoding hashed by the interceptors
+// and hashes the received bytes before decoding them. Messages are never encoded again
+// for verification, so peers using other protocol buffers implementations or versions,
+// or messages with unknown fields, are verified on the bytes actually sent.
+// Calls failing verification fail with the codes.DataLoss status code.
+// Messages without a checksum, sent by peers not using xxhgrpc, are not verified.
+//
+// Unary calls carry the checksum of the request in the request headers
+// and the checksum of the response in the response trailers.
+// gRPC has no client trailers and metadata cannot be sent along with every message of a stream,
+// so streams only carry a checksum of all the messages sent by the server, in the response trailers:
+// the hash of the concatenation of the encodings of the messages, each prefixed by its length
+// as a little endian 64bits integer.
+package xxhgrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// MetadataKey is the metadata key holding checksums.
+const MetadataKey = "x-checksum-xxh64"
+
+// ServerOptions returns the options installing the codec and the interceptors
+// verifying the requests and sending the checksums of the responses on a server.
+// They must precede the other interceptor options, so that the other interceptors
+// see the messages and not their encodings.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ForceServerCodec(codec{}),
+		grpc.ChainUnaryInterceptor(unaryServerInterceptor),
+		grpc.ChainStreamInterceptor(streamServerInterceptor),
+	}
+}
+
+// DialOptions returns the options installing the codec and the interceptors
+// sending the checksums of the requests and verifying the responses on a client connection.
+// They must follow the other interceptor options, so that the other interceptors
+// see the messages and not their encodings.
+func DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{})),
+		grpc.WithChainUnaryInterceptor(unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(streamClientInterceptor),
+	}
+}
+
+// encoded is the encoding of a message, sent as is by codec.
+type encoded []byte
+
+// received is a message to be decoded by codec, recording the checksum of its encoding,
+// or adding the encoding to h if set.
+type received struct {
+	m   any
+	sum uint64
+	h   *streamHash
+}
+
+// decoded holds the checksum of the encoding of the unary requests decoded by codec,
+// until they are verified by the server interceptor.
+var decoded sync.Map // map[proto.Message]uint64
+
+// codec is the protocol buffers codec used with the interceptors.
+type codec struct{}
+
+// Name returns the name of the standard protocol buffers codec, which the peers need not replace.
+func (codec) Name() string {
+	return "proto"
+}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case encoded:
+		return m, nil
+	case proto.Message:
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("xxhgrpc: cannot marshal %T", v)
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	r, _ := v.(*received)
+	if r != nil {
+		v = r.m
+	}
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("xxhgrpc: cannot unmarshal %T", v)
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return err
+	}
+	switch {
+	case r == nil:
+		decoded.Store(m, xxHash64.Checksum(data, 0))
+	case r.h != nil:
+		r.h.add(data)
+	default:
+		r.sum = xxHash64.Checksum(data, 0)
+	}
+	return nil
+}
+
+// encode returns the encoding of m and its checksum.
+func encode(m any) (encoded, string, error) {
+	pm, ok := m.(proto.Message)
+	if !ok {
+		return nil, "", fmt.Errorf("xxhgrpc: cannot marshal %T", m)
+	}
+	b, err := proto.Marshal(pm)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, checksum(xxHash64.Checksum(b, 0)), nil
+}
+
+func checksum(h uint64) string {
+	return xxHash64.Hash64(h).String()
+}
+
+// verify checks the checksum sum of a received message against the one found in md, if any.
+func verify(sum uint64, md metadata.MD, what string) error {
+	want := md.Get(MetadataKey)
+	if len(want) > 0 && checksum(sum) != want[len(want)-1] {
+		return status.Errorf(codes.DataLoss, "xxhgrpc: %s checksum mismatch", what)
+	}
+	return nil
+}
+
+// unaryClientInterceptor sends the checksum of the requests of unary calls
+// and verifies the checksum of their responses.
+func unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	b, sum, err := encode(req)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, sum)
+	r := &received{m: reply}
+	var trailer metadata.MD
+	if err := invoker(ctx, method, b, r, cc, append(opts, grpc.Trailer(&trailer))...); err != nil {
+		return err
+	}
+	return verify(r.sum, trailer, "response")
+}
+
+// unaryServerInterceptor verifies the checksum of the requests of unary calls
+// and sends the checksum of their responses.
+func unaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if sum, ok := decoded.LoadAndDelete(req); ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if err := verify(sum.(uint64), md, "request"); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	b, sum, err := encode(resp)
+	if err != nil {
+		return nil, err
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs(MetadataKey, sum))
+	return b, nil
+}
+
+// streamHash is the running checksum of the messages of a stream.
+type streamHash struct {
+	xxh hash.Hash64
+	buf [8]byte
+}
+
+func newStreamHash() *streamHash {
+	return &streamHash{xxh: xxHash64.New(0)}
+}
+
+// add adds the encoding b of a message.
+func (s *streamHash) add(b []byte) {
+	binary.LittleEndian.PutUint64(s.buf[:], uint64(len(b)))
+	s.xxh.Write(s.buf[:])
+	s.xxh.Write(b)
+}
+
+func (s *streamHash) sum() string {
+	return checksum(s.xxh.Sum64())
+}
+
+// streamClientInterceptor verifies the checksum of the messages received on streams.
+func streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &clientStream{ClientStream: cs, h: newStreamHash()}, nil
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	h *streamHash
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(&received{m: m, h: s.h})
+	if err == io.EOF {
+		// The trailers are available once the stream is done.
+		want := s.Trailer().Get(MetadataKey)
+		if len(want) > 0 && s.h.sum() != want[len(want)-1] {
+			return status.Error(codes.DataLoss, "xxhgrpc: stream checksum mismatch")
+		}
+	}
+	return err
+}
+
+// streamServerInterceptor sends the checksum of the messages sent on streams.
+func streamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	s := &serverStream{ServerStream: ss, h: newStreamHash()}
+	err := handler(srv, s)
+	if err == nil {
+		ss.SetTrailer(metadata.Pairs(MetadataKey, s.h.sum()))
+	}
+	return err
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	h *streamHash
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	b, _, err := encode(m)
+	if err != nil {
+		return err
+	}
+	if err := s.ServerStream.SendMsg(b); err != nil {
+		return err
+	}
+	s.h.add(b)
+	return nil
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	// Messages received on streams are not verified: keep them out of decoded.
+	return s.ServerStream.RecvMsg(&received{m: m})
+}
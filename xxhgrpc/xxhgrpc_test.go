@@ -0,0 +1,201 @@
+package xxhgrpc_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhgrpc"
+)
+
+// echoDesc describes a service echoing its request once with Echo and three times with Repeat.
+var echoDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(wrapperspb.StringValue)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/test.Echo/Echo"}
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return wrapperspb.String(req.(*wrapperspb.StringValue).Value), nil
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Repeat",
+		ServerStreams: true,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			req := new(wrapperspb.StringValue)
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			for i := 0; i < 3; i++ {
+				if err := stream.SendMsg(wrapperspb.String(req.Value)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}},
+}
+
+// corruptListener corrupts the messages sent on its connections, replacing hello with jello.
+type corruptListener struct {
+	net.Listener
+}
+
+func (l corruptListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	return corruptConn{c}, err
+}
+
+type corruptConn struct {
+	net.Conn
+}
+
+func (c corruptConn) Write(p []byte) (int, error) {
+	return c.Conn.Write(bytes.ReplaceAll(p, []byte("hello"), []byte("jello")))
+}
+
+// dial starts a server with xxhgrpc, corrupting its responses if requested,
+// and returns a client connection with or without xxhgrpc.
+func dial(t *testing.T, corrupted, intercept bool) *grpc.ClientConn {
+	t.Helper()
+	srv := grpc.NewServer(xxhgrpc.ServerOptions()...)
+	srv.RegisterService(&echoDesc, nil)
+	lis := bufconn.Listen(1 << 20)
+	if corrupted {
+		go srv.Serve(corruptListener{lis})
+	} else {
+		go srv.Serve(lis)
+	}
+	t.Cleanup(srv.Stop)
+
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if intercept {
+		opts = append(opts, xxhgrpc.DialOptions()...)
+	}
+	cc, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return cc
+}
+
+func echo(ctx context.Context, cc *grpc.ClientConn) (string, error) {
+	reply := new(wrapperspb.StringValue)
+	err := cc.Invoke(ctx, "/test.Echo/Echo", wrapperspb.String("hello"), reply)
+	return reply.Value, err
+}
+
+func repeat(cc *grpc.ClientConn) ([]string, error) {
+	s, err := cc.NewStream(context.Background(), &echoDesc.Streams[0], "/test.Echo/Repeat")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SendMsg(wrapperspb.String("hello")); err != nil {
+		return nil, err
+	}
+	if err := s.CloseSend(); err != nil {
+		return nil, err
+	}
+	var res []string
+	for {
+		m := new(wrapperspb.StringValue)
+		if err := s.RecvMsg(m); err == io.EOF {
+			return res, nil
+		} else if err != nil {
+			return res, err
+		}
+		res = append(res, m.Value)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	for _, intercept := range []bool{true, false} {
+		cc := dial(t, false, intercept)
+		if v, err := echo(context.Background(), cc); err != nil || v != "hello" {
+			t.Errorf("echo: got %q, %v", v, err)
+		}
+		if v, err := repeat(cc); err != nil || len(v) != 3 {
+			t.Errorf("repeat: got %q, %v", v, err)
+		}
+	}
+}
+
+func TestInterceptorsCorrupted(t *testing.T) {
+	cc := dial(t, true, true)
+	if _, err := echo(context.Background(), cc); status.Code(err) != codes.DataLoss {
+		t.Errorf("echo: got error %v expected %v", err, codes.DataLoss)
+	}
+	if _, err := repeat(cc); status.Code(err) != codes.DataLoss {
+		t.Errorf("repeat: got error %v expected %v", err, codes.DataLoss)
+	}
+
+	// The corruption goes unnoticed without the client interceptors.
+	cc = dial(t, true, false)
+	if v, err := echo(context.Background(), cc); err != nil || v != "jello" {
+		t.Errorf("echo: got %q, %v", v, err)
+	}
+}
+
+func TestInvalidRequestChecksum(t *testing.T) {
+	cc := dial(t, false, false)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), xxhgrpc.MetadataKey, "0123456789abcdef")
+	if _, err := echo(ctx, cc); status.Code(err) != codes.DataLoss {
+		t.Errorf("got error %v expected %v", err, codes.DataLoss)
+	}
+}
+
+// rawCodec sends and receives encodings as is, as a peer with another protocol buffers implementation.
+type rawCodec struct{}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	return v.([]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	*v.(*[]byte) = append([]byte(nil), data...)
+	return nil
+}
+
+func TestNonCanonicalRequest(t *testing.T) {
+	cc := dial(t, false, false)
+	// An unknown field 2 before the value "hello": encoding the decoded message again
+	// would move the unknown field after the value.
+	req := []byte{0x10, 0x01, 0x0a, 0x05, 'h', 'e', 'l', 'l', 'o'}
+	sum := xxHash64.Hash64(xxHash64.Checksum(req, 0)).String()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), xxhgrpc.MetadataKey, sum)
+	var reply []byte
+	if err := cc.Invoke(ctx, "/test.Echo/Echo", req, &reply, grpc.ForceCodec(rawCodec{})); err != nil {
+		t.Fatal(err)
+	}
+	var m wrapperspb.StringValue
+	if err := proto.Unmarshal(reply, &m); err != nil || m.Value != "hello" {
+		t.Errorf("got %q, %v", m.Value, err)
+	}
+}
@@ -0,0 +1,187 @@
+package xxhhttp
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Digest fields defined by RFC 9530.
+const (
+	ReprDigestHeader        = "Repr-Digest"
+	WantReprDigestHeader    = "Want-Repr-Digest"
+	ContentDigestHeader     = "Content-Digest"
+	WantContentDigestHeader = "Want-Content-Digest"
+)
+
+// DigestAlgorithm is the digest algorithm key of the 64bits Hash value with a zero seed.
+// The xxh3 key is not supported, as XXH3 is not implemented by this module.
+const DigestAlgorithm = "xxh64"
+
+// ErrDigest is returned when parsing an invalid digest field value.
+var ErrDigest = errors.New("xxhhttp: invalid digest field")
+
+// FormatDigest returns the value of a Repr-Digest or Content-Digest field holding h,
+// the 64bits Hash value of the representation or the content with a zero seed:
+// the xxh64 key with the canonical (big endian) form of h as a byte sequence,
+// for instance for an empty representation:
+//
+//	xxh64=:70bbN1HY6Zk=:
+func FormatDigest(h uint64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], h)
+	return DigestAlgorithm + "=:" + base64.StdEncoding.EncodeToString(b[:]) + ":"
+}
+
+// ParseDigest returns the xxh64 hash value held by the value of a Repr-Digest or Content-Digest field,
+// with ok set to false if there is none.
+// The digests of other algorithms are ignored.
+func ParseDigest(v string) (h uint64, ok bool, err error) {
+	members, err := parseDictionary(v)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, m := range members {
+		if m.key != DigestAlgorithm {
+			continue
+		}
+		if len(m.value) < 2 || m.value[0] != ':' || m.value[len(m.value)-1] != ':' {
+			return 0, false, ErrDigest
+		}
+		b, err := base64.StdEncoding.DecodeString(m.value[1 : len(m.value)-1])
+		if err != nil || len(b) != 8 {
+			return 0, false, ErrDigest
+		}
+		h, ok = binary.BigEndian.Uint64(b), true
+	}
+	return h, ok, nil
+}
+
+// Negotiate returns the algorithm with the highest preference in the value of a
+// Want-Repr-Digest or Want-Content-Digest field among the given ones, DigestAlgorithm if none is given.
+// Ties are resolved by the order of the given algorithms.
+// It returns an empty string if none of them is acceptable, that is listed with a non zero preference.
+func Negotiate(want string, algorithms ...string) (string, error) {
+	if len(algorithms) == 0 {
+		algorithms = []string{DigestAlgorithm}
+	}
+	members, err := parseDictionary(want)
+	if err != nil {
+		return "", err
+	}
+	prefs := map[string]int{}
+	for _, m := range members {
+		p, err := strconv.Atoi(m.value)
+		if err != nil || p < 0 || p > 10 {
+			return "", ErrDigest
+		}
+		prefs[m.key] = p
+	}
+	best, bestPref := "", 0
+	for _, a := range algorithms {
+		if p := prefs[a]; p > bestPref {
+			best, bestPref = a, p
+		}
+	}
+	return best, nil
+}
+
+// member is a member of a structured field dictionary (RFC 8941), without its parameters.
+type member struct {
+	key, value string
+}
+
+// parseDictionary parses the members of a structured field dictionary with bare item values,
+// which is all the digest fields use. Later members override earlier ones with the same key.
+func parseDictionary(v string) ([]member, error) {
+	var members []member
+	v = strings.Trim(v, " \t")
+	for v != "" {
+		var m member
+		i := strings.IndexFunc(v, func(r rune) bool { return !isKeyChar(r) })
+		if i < 0 {
+			i = len(v)
+		}
+		if i == 0 || v[0] < 'a' && v[0] != '*' || v[0] > 'z' {
+			return nil, ErrDigest
+		}
+		m.key, v = v[:i], v[i:]
+		if strings.HasPrefix(v, "=") {
+			var err error
+			if m.value, v, err = bareItem(v[1:]); err != nil {
+				return nil, err
+			}
+		} else {
+			// Boolean true.
+			m.value = "?1"
+		}
+		// Skip the parameters.
+		for strings.HasPrefix(v, ";") {
+			j := strings.IndexAny(v[1:], ",;")
+			if j < 0 {
+				v = ""
+				break
+			}
+			v = v[1+j:]
+		}
+		for k := range members {
+			if members[k].key == m.key {
+				members = append(members[:k], members[k+1:]...)
+				break
+			}
+		}
+		members = append(members, m)
+		v = strings.TrimLeft(v, " \t")
+		if v == "" {
+			break
+		}
+		if v[0] != ',' {
+			return nil, ErrDigest
+		}
+		v = strings.TrimLeft(v[1:], " \t")
+		if v == "" {
+			// Trailing comma.
+			return nil, ErrDigest
+		}
+	}
+	return members, nil
+}
+
+// bareItem returns the bare item at the start of v and the rest of v.
+func bareItem(v string) (string, string, error) {
+	if v == "" {
+		return "", "", ErrDigest
+	}
+	var end int
+	switch c := v[0]; {
+	case c == ':':
+		end = strings.IndexByte(v[1:], ':')
+		if end < 0 {
+			return "", "", ErrDigest
+		}
+		end += 2
+	case c == '"':
+		end = 1
+		for ; end < len(v) && v[end] != '"'; end++ {
+			if v[end] == '\\' {
+				end++
+			}
+		}
+		end++
+	default:
+		end = strings.IndexAny(v, ";, \t")
+		if end < 0 {
+			end = len(v)
+		}
+	}
+	if end <= 0 || end > len(v) {
+		return "", "", ErrDigest
+	}
+	return v[:end], v[end:], nil
+}
+
+func isKeyChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.' || r == '*'
+}
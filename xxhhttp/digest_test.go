@@ -0,0 +1,64 @@
+package xxhhttp_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhhttp"
+)
+
+func TestFormatDigest(t *testing.T) {
+	if got, want := xxhhttp.FormatDigest(xxHash64.Checksum(nil, 0)), "xxh64=:70bbN1HY6Zk=:"; got != want {
+		t.Errorf("got %q expected %q", got, want)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	h := xxHash64.Checksum([]byte("hello"), 0)
+	for _, v := range []string{
+		xxhhttp.FormatDigest(h),
+		"sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:, " + xxhhttp.FormatDigest(h),
+		xxhhttp.FormatDigest(h) + ";foo=bar,unixtime=1",
+		"xxh64=:AAAAAAAAAAA=:, " + xxhhttp.FormatDigest(h),
+	} {
+		got, ok, err := xxhhttp.ParseDigest(v)
+		if err != nil || !ok || got != h {
+			t.Errorf("%q: got 0x%x, %v, %v expected 0x%x", v, got, ok, err, h)
+		}
+	}
+
+	if _, ok, err := xxhhttp.ParseDigest("sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"); ok || err != nil {
+		t.Errorf("got %v, %v expected no digest", ok, err)
+	}
+	for _, v := range []string{"xxh64=:AAAA:", "xxh64=1", "xxh64=:AAAA", "Xxh64=:AAAAAAAAAAA=:", "xxh64=:AAAAAAAAAAA=:,", "a b"} {
+		if _, _, err := xxhhttp.ParseDigest(v); err != xxhhttp.ErrDigest {
+			t.Errorf("%q: got error %v expected %v", v, err, xxhhttp.ErrDigest)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		want       string
+		algorithms []string
+		res        string
+	}{
+		{"xxh64=3, sha-256=10", nil, "xxh64"},
+		{"sha-256=10", nil, ""},
+		{"xxh64=0", nil, ""},
+		{"", nil, ""},
+		{"xxh64=3, sha-256=10", []string{"xxh64", "sha-256"}, "sha-256"},
+		{"xxh64=5, sha-256=5", []string{"sha-256", "xxh64"}, "sha-256"},
+		{"xxh3=7, xxh64=5", []string{"xxh64"}, "xxh64"},
+	} {
+		res, err := xxhhttp.Negotiate(tc.want, tc.algorithms...)
+		if err != nil || res != tc.res {
+			t.Errorf("%q %v: got %q, %v expected %q", tc.want, tc.algorithms, res, err, tc.res)
+		}
+	}
+	for _, v := range []string{"xxh64=11", "xxh64=-1", "xxh64=:AAAA:", "xxh64"} {
+		if _, err := xxhhttp.Negotiate(v); err != xxhhttp.ErrDigest {
+			t.Errorf("%q: got error %v expected %v", v, err, xxhhttp.ErrDigest)
+		}
+	}
+}
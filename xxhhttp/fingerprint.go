@@ -0,0 +1,125 @@
+package xxhhttp
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// DefaultFingerprintLen is the default length of fingerprints, holding 48 bits.
+const DefaultFingerprintLen = 8
+
+// Fingerprinter generates short URL safe fingerprints of asset contents for cache busting file names,
+// such as app.3q2-7wEj.js for app.js, and serves the assets under these names.
+//
+// A fingerprint is the prefix of the unpadded URL safe base64 form of the canonical
+// 64bits Hash value of the content seeded with Key. Each character holds 6 bits,
+// the full 11 characters holding the 64 bits. The probability that two of n assets share
+// the same fingerprint is given by xxHash64.CollisionProbability with 6 * Len bits:
+// with the default length, it is below 2e-5 for 100000 assets.
+//
+// Key makes fingerprints specific to a deployment. It is not a secret:
+// xxHash64 is not a message authentication code.
+type Fingerprinter struct {
+	// Key seeds the hash of the contents.
+	Key uint64
+	// Len is the length of the fingerprints, in [1, 11], DefaultFingerprintLen if zero.
+	Len int
+}
+
+func (f Fingerprinter) len() int {
+	switch n := f.Len; {
+	case n == 0:
+		return DefaultFingerprintLen
+	case n < 0 || n > 11:
+		panic("xxhhttp: invalid fingerprint length")
+	default:
+		return n
+	}
+}
+
+// Fingerprint returns the fingerprint of data.
+// It panics if f.Len is invalid.
+func (f Fingerprinter) Fingerprint(data []byte) string {
+	return xxHash64.Hash64(xxHash64.Checksum(data, f.Key)).Base64()[:f.len()]
+}
+
+// FingerprintReader returns the fingerprint of the data read from r until io.EOF.
+// It panics if f.Len is invalid.
+func (f Fingerprinter) FingerprintReader(r io.Reader) (string, error) {
+	h := xxHash64.New(f.Key)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return xxHash64.Hash64(h.Sum64()).Base64()[:f.len()], nil
+}
+
+// Verify reports whether fp is the fingerprint of data.
+func (f Fingerprinter) Verify(data []byte, fp string) bool {
+	return len(fp) == f.len() && f.Fingerprint(data) == fp
+}
+
+// Name returns name with the fingerprint fp inserted before its extension,
+// or appended to it if it has none.
+func (f Fingerprinter) Name(name, fp string) string {
+	ext := path.Ext(name)
+	return name[:len(name)-len(ext)] + "." + fp + ext
+}
+
+// Split returns the original name and the fingerprint of a name returned by Name.
+// It reports false if name does not hold a fingerprint of the configured length.
+// As extensions may look like fingerprints, the caller should check that the original name exists.
+func (f Fingerprinter) Split(name string) (orig, fp string, ok bool) {
+	n := f.len()
+	dir, base := path.Split(name)
+	ext := path.Ext(base)
+	stem := base[:len(base)-len(ext)]
+	if e := path.Ext(stem); len(e) == n+1 && isFingerprint(e[1:]) {
+		return dir + stem[:len(stem)-len(e)] + ext, e[1:], true
+	}
+	if len(ext) == n+1 && stem != "" && isFingerprint(ext[1:]) {
+		return dir + stem, ext[1:], true
+	}
+	return "", "", false
+}
+
+func isFingerprint(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Handler returns a handler serving the files of fsys.
+//
+// Files requested under a fingerprinted name are served with a long lived immutable
+// Cache-Control header if the fingerprint matches their content.
+// Other names, including stale fingerprinted names, are served as is with a no-cache
+// Cache-Control header, and are not found unless a file has that exact name,
+// so that stale names are never cached with fresh content.
+func (f Fingerprinter) Handler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if orig, fp, ok := f.Split(name); ok {
+			if data, err := fs.ReadFile(fsys, orig); err == nil && f.Verify(data, fp) {
+				w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+				http.ServeContent(w, r, orig, time.Time{}, bytes.NewReader(data))
+				return
+			}
+			// The name only looks fingerprinted, such as app.polyfill.js,
+			// or the fingerprint is stale and the file does not exist.
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeFileFS(w, r, fsys, name)
+	})
+}
@@ -0,0 +1,103 @@
+package xxhhttp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhhttp"
+)
+
+func TestFingerprint(t *testing.T) {
+	data := testvectors.Input(1000)
+	var f xxhhttp.Fingerprinter
+	fp := f.Fingerprint(data)
+	if len(fp) != xxhhttp.DefaultFingerprintLen {
+		t.Errorf("got fingerprint %q", fp)
+	}
+	if want := xxHash64.ChecksumBase64(data, 0)[:8]; fp != want {
+		t.Errorf("got fingerprint %q expected %q", fp, want)
+	}
+	if !f.Verify(data, fp) || f.Verify(data[1:], fp) || f.Verify(data, fp[1:]) {
+		t.Error("invalid verification")
+	}
+
+	keyed := xxhhttp.Fingerprinter{Key: 1, Len: 11}
+	full := keyed.Fingerprint(data)
+	if want := xxHash64.ChecksumBase64(data, 1); full != want {
+		t.Errorf("got fingerprint %q expected %q", full, want)
+	}
+	got, err := keyed.FingerprintReader(bytes.NewReader(data))
+	if err != nil || got != full {
+		t.Errorf("got %q, %v expected %q", got, err, full)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	xxhhttp.Fingerprinter{Len: 12}.Fingerprint(data)
+}
+
+func TestFingerprintName(t *testing.T) {
+	f := xxhhttp.Fingerprinter{Len: 4}
+	for _, tc := range []struct{ name, fingerprinted string }{
+		{"app.js", "app.ab-_.js"},
+		{"css/site.min.css", "css/site.min.ab-_.css"},
+		{"LICENSE", "LICENSE.ab-_"},
+		{"dir.d/LICENSE", "dir.d/LICENSE.ab-_"},
+	} {
+		if got := f.Name(tc.name, "ab-_"); got != tc.fingerprinted {
+			t.Errorf("got %q expected %q", got, tc.fingerprinted)
+		}
+		orig, fp, ok := f.Split(tc.fingerprinted)
+		if !ok || orig != tc.name || fp != "ab-_" {
+			t.Errorf("%q: got %q, %q, %v", tc.fingerprinted, orig, fp, ok)
+		}
+	}
+	for _, name := range []string{"app.js", "app.abc.js", "app.ab.d.js", "/.abcd"} {
+		if orig, fp, ok := f.Split(name); ok {
+			t.Errorf("%q: got %q, %q", name, orig, fp)
+		}
+	}
+}
+
+func TestFingerprintHandler(t *testing.T) {
+	content := []byte("console.log(1)")
+	polyfill := []byte("console.log(2)")
+	fsys := fstest.MapFS{"app.js": {Data: content}, "app.polyfill.js": {Data: polyfill}}
+	f := xxhhttp.Fingerprinter{Key: 42}
+	h := f.Handler(fsys)
+	name := f.Name("app.js", f.Fingerprint(content))
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		cache  string
+		body   []byte
+	}{
+		{"/" + name, http.StatusOK, "public, max-age=31536000, immutable", content},
+		{"/app.js", http.StatusOK, "no-cache", content},
+		// polyfill looks like a fingerprint of app.js.
+		{"/app.polyfill.js", http.StatusOK, "no-cache", polyfill},
+		{"/app.AAAAAAAA.js", http.StatusNotFound, "no-cache", nil},
+		{"/missing.AAAAAAAA.js", http.StatusNotFound, "no-cache", nil},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.status {
+			t.Errorf("%s: got status %d expected %d", tc.path, w.Code, tc.status)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tc.cache {
+			t.Errorf("%s: got Cache-Control %q expected %q", tc.path, got, tc.cache)
+		}
+		if tc.status == http.StatusOK && w.Body.String() != string(tc.body) {
+			t.Errorf("%s: got body %q", tc.path, w.Body)
+		}
+	}
+}
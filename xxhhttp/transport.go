@@ -0,0 +1,84 @@
+package xxhhttp
+
+import (
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var (
+	// ErrMismatch is returned when reading a response body that does not match its checksum.
+	ErrMismatch = errors.New("xxhhttp: checksum mismatch")
+	// ErrInvalidChecksum is returned when reading a response body with an invalid checksum.
+	ErrInvalidChecksum = errors.New("xxhhttp: invalid checksum")
+)
+
+// Transport is an http.RoundTripper verifying the checksums of response bodies
+// sent in the X-Checksum-XXH64 header or trailer, as added by Handler.
+//
+// Bodies are hashed as they are read, without buffering: reading the end of a body
+// returns ErrMismatch instead of io.EOF if it does not match its checksum,
+// and ErrInvalidChecksum if the checksum cannot be parsed.
+// Responses without a checksum are not verified, nor are the ones transparently
+// decompressed by the Base transport, as the checksum covers the compressed body.
+type Transport struct {
+	// Base is the RoundTripper sending the requests, http.DefaultTransport if nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.Body == nil || resp.Body == http.NoBody || resp.Uncompressed || req.Method == http.MethodHead {
+		return resp, err
+	}
+	resp.Body = &verifier{ReadCloser: resp.Body, xxh: xxHash64.New(0), resp: resp}
+	return resp, nil
+}
+
+// verifier checks the checksum of a response body when reaching its end.
+type verifier struct {
+	io.ReadCloser
+	xxh  hash.Hash64
+	resp *http.Response
+	err  error // set once the end is reached
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	n, err := v.ReadCloser.Read(p)
+	v.xxh.Write(p[:n])
+	if err == io.EOF {
+		err = v.verify()
+		v.err = err
+	}
+	return n, err
+}
+
+func (v *verifier) verify() error {
+	// Trailers are only available once the body has been read.
+	sum := v.resp.Header.Get(HeaderName)
+	if sum == "" {
+		sum = v.resp.Trailer.Get(HeaderName)
+	}
+	if sum == "" {
+		return io.EOF
+	}
+	want, err := xxHash64.ParseHex(sum)
+	if err != nil || len(sum) != 16 {
+		return ErrInvalidChecksum
+	}
+	if v.xxh.Sum64() != want {
+		return ErrMismatch
+	}
+	return io.EOF
+}
@@ -0,0 +1,67 @@
+package xxhhttp_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhhttp"
+)
+
+func TestTransport(t *testing.T) {
+	data := testvectors.Input(100000)
+	mux := http.NewServeMux()
+	// Served with a checksum header.
+	mux.Handle("/small", xxhhttp.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data[:100])
+	})))
+	// Served with a checksum trailer.
+	mux.Handle("/large", xxhhttp.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})))
+	mux.HandleFunc("/none", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data[:100])
+	})
+	mux.HandleFunc("/corrupt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(xxhhttp.HeaderName, checksum(data[:100]))
+		w.Write(data[1:101])
+	})
+	mux.HandleFunc("/corrupt-trailer", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", xxhhttp.HeaderName)
+		w.Write(data[1:101])
+		w.Header().Set(xxhhttp.HeaderName, checksum(data[:100]))
+	})
+	mux.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(xxhhttp.HeaderName, "xyz")
+		w.Write(data[:100])
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := &http.Client{Transport: &xxhhttp.Transport{Base: srv.Client().Transport}}
+
+	for _, tc := range []struct {
+		path string
+		body []byte
+		err  error
+	}{
+		{"/small", data[:100], nil},
+		{"/large", data, nil},
+		{"/none", data[:100], nil},
+		{"/corrupt", data[1:101], xxhhttp.ErrMismatch},
+		{"/corrupt-trailer", data[1:101], xxhhttp.ErrMismatch},
+		{"/invalid", data[:100], xxhhttp.ErrInvalidChecksum},
+	} {
+		resp, err := client.Get(srv.URL + tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != tc.err || !bytes.Equal(body, tc.body) {
+			t.Errorf("%s: got %d bytes and error %v expected %d bytes and error %v", tc.path, len(body), err, len(tc.body), tc.err)
+		}
+	}
+}
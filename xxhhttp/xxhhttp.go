@@ -0,0 +1,169 @@
+// Package xxhhttp adds xxHash64 (https://github.com/Cyan4973/xxHash/) checksums
+// of response bodies to HTTP responses.
+//
+// The checksum is sent in the X-Checksum-XXH64 header as the canonical hexadecimal form
+// of the 64bits Hash value of the body with a zero seed, as printed by xxhsum.
+// As headers are sent before the body, responses larger than the buffer size of
+// the middleware, or flushed by the handler, carry it in a trailer instead.
+// HTTP/1.1 responses only carry trailers when they are chunked, that is without a Content-Length header.
+//
+// FormatDigest, ParseDigest and Negotiate support the standard digest fields of RFC 9530,
+// such as Repr-Digest, with the xxh64 algorithm key.
+//
+// Fingerprinter generates cache busting asset names and serves the assets requested by these names.
+package xxhhttp
+
+import (
+	"bytes"
+	"hash"
+	"net/http"
+	"strconv"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// HeaderName is the name of the header or trailer holding the checksum.
+const HeaderName = "X-Checksum-XXH64"
+
+// DefaultBufferSize is the default size of the buffer holding the response body until it is complete.
+const DefaultBufferSize = 64 << 10
+
+// Options configures the middleware.
+type Options struct {
+	// BufferSize is the size of the response body up to which the checksum is sent in a header,
+	// DefaultBufferSize if zero. Larger bodies are streamed and the checksum sent in a trailer.
+	// A negative BufferSize always streams the body.
+	BufferSize int
+}
+
+// Handler returns a handler calling next and adding the checksum of its response bodies.
+func Handler(next http.Handler) http.Handler {
+	return HandlerWith(next, Options{})
+}
+
+// HandlerWith is like Handler but configured by opts.
+//
+// The checksum covers the bytes written by next, after any content encoding it applies.
+// It is not added to the responses to HEAD requests and to responses that cannot have a body.
+func HandlerWith(next http.Handler, opts Options) http.Handler {
+	size := opts.BufferSize
+	if size == 0 {
+		size = DefaultBufferSize
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &writer{ResponseWriter: w, xxh: xxHash64.New(0), size: size}
+		next.ServeHTTP(cw, r)
+		cw.finish()
+	})
+}
+
+// writer buffers the response up to size bytes while hashing it.
+type writer struct {
+	http.ResponseWriter
+	xxh       hash.Hash64
+	size      int
+	buf       bytes.Buffer
+	status    int // 0 until WriteHeader is called
+	streaming bool
+}
+
+func (w *writer) WriteHeader(status int) {
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		// Informational responses precede the final one.
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+		if !bodyAllowed(status) {
+			w.stream()
+		}
+	}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.streaming && w.buf.Len()+len(p) > w.size {
+		if err := w.stream(); err != nil {
+			return 0, err
+		}
+	}
+	if w.streaming {
+		n, err := w.ResponseWriter.Write(p)
+		w.xxh.Write(p[:n])
+		return n, err
+	}
+	w.xxh.Write(p)
+	return w.buf.Write(p)
+}
+
+// Flush implements http.Flusher, streaming the body from then on.
+func (w *writer) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.stream() == nil {
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// Unwrap returns the wrapped ResponseWriter, for use by http.ResponseController.
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// stream sends the header and the buffered body.
+func (w *writer) stream() error {
+	if w.streaming {
+		return nil
+	}
+	w.streaming = true
+	if bodyAllowed(w.status) {
+		// Announcing the trailer keeps the server from buffering the body to send its length.
+		w.Header().Add("Trailer", HeaderName)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish sends the response with its checksum.
+func (w *writer) finish() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	sum := xxHash64.Hash64(w.xxh.Sum64()).String()
+	if w.streaming {
+		if bodyAllowed(w.status) {
+			w.Header().Set(HeaderName, sum)
+		}
+		return
+	}
+	// The whole body is known: send it with its length and the checksum in a header,
+	// leaving the trailer to the streamed responses.
+	w.streaming = true
+	w.Header().Set(HeaderName, sum)
+	if w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.buf.Len()))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+// bodyAllowed reports whether a response with the given status can have a body.
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
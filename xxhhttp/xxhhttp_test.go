@@ -0,0 +1,117 @@
+package xxhhttp_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhhttp"
+)
+
+func checksum(data []byte) string {
+	return xxHash64.Hash64(xxHash64.Checksum(data, 0)).String()
+}
+
+// get requests path from a server running h and returns the response with its body read.
+func get(t *testing.T, h http.Handler, method, path string) (*http.Response, []byte) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	req, err := http.NewRequest(method, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func TestHandler(t *testing.T) {
+	data := testvectors.Input(100000)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/small", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(data[:10])
+		w.Write(data[10:100])
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+	mux.HandleFunc("/flush", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data[:10])
+		w.(http.Flusher).Flush()
+		w.Write(data[10:100])
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/nocontent", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := xxhhttp.Handler(mux)
+
+	resp, body := get(t, h, http.MethodGet, "/small")
+	if resp.StatusCode != http.StatusNotFound || !bytes.Equal(body, data[:100]) {
+		t.Errorf("small: got status %d and %d bytes", resp.StatusCode, len(body))
+	}
+	if got, want := resp.Header.Get(xxhhttp.HeaderName), checksum(data[:100]); got != want {
+		t.Errorf("small: got header %q expected %q", got, want)
+	}
+	if resp.ContentLength != 100 || len(resp.TransferEncoding) > 0 || resp.Trailer.Get(xxhhttp.HeaderName) != "" {
+		t.Errorf("small: got length %d, transfer encoding %v and trailer %v", resp.ContentLength, resp.TransferEncoding, resp.Trailer)
+	}
+
+	for path, want := range map[string][]byte{"/large": data, "/flush": data[:100]} {
+		resp, body = get(t, h, http.MethodGet, path)
+		if !bytes.Equal(body, want) {
+			t.Errorf("%s: got %d bytes expected %d", path, len(body), len(want))
+		}
+		if got := resp.Header.Get(xxhhttp.HeaderName); got != "" {
+			t.Errorf("%s: unexpected header %q", path, got)
+		}
+		if got, want := resp.Trailer.Get(xxhhttp.HeaderName), checksum(want); got != want {
+			t.Errorf("%s: got trailer %q expected %q", path, got, want)
+		}
+	}
+
+	resp, _ = get(t, h, http.MethodGet, "/empty")
+	if got, want := resp.Header.Get(xxhhttp.HeaderName), checksum(nil); got != want {
+		t.Errorf("empty: got header %q expected %q", got, want)
+	}
+
+	resp, _ = get(t, h, http.MethodGet, "/nocontent")
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get(xxhhttp.HeaderName) != "" || resp.Trailer.Get(xxhhttp.HeaderName) != "" {
+		t.Errorf("nocontent: got status %d header %v trailer %v", resp.StatusCode, resp.Header, resp.Trailer)
+	}
+
+	resp, _ = get(t, h, http.MethodHead, "/small")
+	if got := resp.Header.Get(xxhhttp.HeaderName); got != "" {
+		t.Errorf("head: unexpected header %q", got)
+	}
+}
+
+func TestHandlerWith(t *testing.T) {
+	data := testvectors.Input(100)
+	h := xxhhttp.HandlerWith(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}), xxhhttp.Options{BufferSize: -1})
+	resp, _ := get(t, h, http.MethodGet, "/")
+	if got, want := resp.Trailer.Get(xxhhttp.HeaderName), checksum(data); got != want || resp.Header.Get(xxhhttp.HeaderName) != "" {
+		t.Errorf("got trailer %q expected %q", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got, want := rec.Result().Trailer.Get(xxhhttp.HeaderName), checksum(data); got != want {
+		t.Errorf("got recorded trailer %q expected %q", got, want)
+	}
+}
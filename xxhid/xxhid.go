@@ -0,0 +1,113 @@
+// Package xxhid generates unique identifiers sortable by creation time,
+// made of a timestamp and of bits mixed by xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// An ID is 16 bytes long: a 48 bits big endian Unix time in milliseconds,
+// 16 bits of the hash of the generating node name and the 64 bits xxHash64 ChecksumUint64
+// of a per Generator sequence number seeded with the node hash.
+// As ChecksumUint64 is a bijection for a given seed, a Generator never yields the same ID twice
+// before 2^64 IDs. IDs from distinct nodes collide only if their node hashes share
+// the same 16 bits and their mixed sequences the same 64 bits within the same millisecond.
+//
+// IDs are K-sortable: they are ordered by their millisecond timestamp,
+// in random order within a millisecond. Their string representation
+// preserves that order.
+//
+// IDs are not cryptographically random and must not be used as secrets.
+package xxhid
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// EncodedLen is the length of the string representation of an ID.
+const EncodedLen = 26
+
+// ErrID is returned when parsing an invalid ID.
+var ErrID = errors.New("xxhid: invalid ID")
+
+// encoding is the Crockford base32 alphabet, whose characters are in ascending order.
+var encoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// ID is a unique identifier.
+type ID [16]byte
+
+// Time returns the creation time of id, with a millisecond precision.
+func (id ID) Time() time.Time {
+	var b [8]byte
+	copy(b[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+}
+
+// String returns the 26 characters Crockford base32 representation of id.
+func (id ID) String() string {
+	return encoding.EncodeToString(id[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (id ID) MarshalText() ([]byte, error) {
+	b := make([]byte, EncodedLen)
+	encoding.Encode(b, id[:])
+	return b, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (id *ID) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// Parse parses the string representation of an ID.
+func Parse(s string) (ID, error) {
+	var id ID
+	if len(s) != EncodedLen {
+		return id, ErrID
+	}
+	n, err := encoding.Decode(id[:], []byte(s))
+	// The last character only holds 3 bits.
+	if err != nil || n != len(id) || id.String() != s {
+		return ID{}, ErrID
+	}
+	return id, nil
+}
+
+// Generator generates IDs for a node.
+// It is safe for concurrent use.
+type Generator struct {
+	node uint64
+	seq  atomic.Uint64
+}
+
+// NewGenerator returns a Generator for the node identified by name,
+// such as a host name or a process identifier, which should be unique among
+// the nodes generating IDs.
+func NewGenerator(name string) *Generator {
+	return &Generator{node: xxHash64.ChecksumString(name, 0)}
+}
+
+// New returns a new ID with the current time.
+func (g *Generator) New() ID {
+	return g.NewAt(time.Now())
+}
+
+// NewAt returns a new ID with the time t, which must be after the Unix epoch
+// and before the year 10889.
+func (g *Generator) NewAt(t time.Time) ID {
+	var id ID
+	binary.BigEndian.PutUint64(id[8:], xxHash64.ChecksumUint64(g.seq.Add(1)-1, g.node))
+	binary.BigEndian.PutUint16(id[6:], uint16(g.node>>48))
+	ms := uint64(t.UnixMilli())
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(id[2:], uint32(ms))
+	return id
+}
@@ -0,0 +1,109 @@
+package xxhid_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/xxhid"
+)
+
+func TestGenerator(t *testing.T) {
+	g := xxhid.NewGenerator("host-1")
+	now := time.UnixMilli(1700000000123)
+	seen := map[xxhid.ID]bool{}
+	for i := 0; i < 1000; i++ {
+		id := g.NewAt(now)
+		if seen[id] {
+			t.Fatalf("duplicate ID %v", id)
+		}
+		seen[id] = true
+		if got := id.Time(); !got.Equal(now) {
+			t.Fatalf("got time %v expected %v", got, now)
+		}
+	}
+
+	// Distinct nodes generate distinct IDs from the same sequence numbers.
+	g1, g2 := xxhid.NewGenerator("host-1"), xxhid.NewGenerator("host-2")
+	if id1, id2 := g1.NewAt(now), g2.NewAt(now); id1 == id2 {
+		t.Errorf("got the same ID %v for two nodes", id1)
+	}
+	if id := g.New(); time.Since(id.Time()) > time.Minute {
+		t.Errorf("got time %v", id.Time())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	g := xxhid.NewGenerator("host")
+	const workers, n = 4, 1000
+	ids := make([][]xxhid.ID, workers)
+	var wg sync.WaitGroup
+	for w := range ids {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				ids[w] = append(ids[w], g.New())
+			}
+		}(w)
+	}
+	wg.Wait()
+	seen := map[xxhid.ID]bool{}
+	for _, l := range ids {
+		for _, id := range l {
+			if seen[id] {
+				t.Fatalf("duplicate ID %v", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestSortable(t *testing.T) {
+	g := xxhid.NewGenerator("host")
+	start := time.UnixMilli(1700000000000)
+	var ids []xxhid.ID
+	var strs []string
+	for i := 0; i < 100; i++ {
+		id := g.NewAt(start.Add(time.Duration(i*7919%1000) * time.Millisecond))
+		ids = append(ids, id)
+		strs = append(strs, id.String())
+	}
+	sort.Slice(ids, func(i, j int) bool { return string(ids[i][:]) < string(ids[j][:]) })
+	sort.Strings(strs)
+	for i, id := range ids {
+		if id.String() != strs[i] {
+			t.Fatalf("string order differs at %d", i)
+		}
+		if i > 0 && id.Time().Before(ids[i-1].Time()) {
+			t.Fatalf("IDs are not sorted by time at %d", i)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	id := xxhid.NewGenerator("host").New()
+	s := id.String()
+	if len(s) != xxhid.EncodedLen {
+		t.Errorf("got length %d expected %d", len(s), xxhid.EncodedLen)
+	}
+	got, err := xxhid.Parse(s)
+	if err != nil || got != id {
+		t.Errorf("got %v, %v expected %v", got, err, id)
+	}
+
+	var v xxhid.ID
+	if err := v.UnmarshalText([]byte(s)); err != nil || v != id {
+		t.Errorf("got %v, %v expected %v", v, err, id)
+	}
+	if text, _ := id.MarshalText(); string(text) != s {
+		t.Errorf("got %q expected %q", text, s)
+	}
+
+	for _, s := range []string{"", s[1:], s + "0", "U" + s[1:], s[:25] + "Z", "0000000000000000000000000I"} {
+		if _, err := xxhid.Parse(s); err != xxhid.ErrID {
+			t.Errorf("%q: got error %v expected %v", s, err, xxhid.ErrID)
+		}
+	}
+}
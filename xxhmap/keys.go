@@ -0,0 +1,48 @@
+package xxhmap
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// HashAddr returns the hash of a using seed, without allocating.
+// IPv4 and IPv4-mapped IPv6 addresses, which are not equal, have different hashes.
+func HashAddr(a netip.Addr, seed uint64) uint64 {
+	var b [17]byte
+	return hashAddr(b[:], a, seed)
+}
+
+// HashAddrPort returns the hash of ap using seed, without allocating.
+func HashAddrPort(ap netip.AddrPort, seed uint64) uint64 {
+	var b [19]byte
+	binary.LittleEndian.PutUint16(b[17:], ap.Port())
+	return hashAddr(b[:], ap.Addr(), seed)
+}
+
+// hashAddr returns the hash of b, with its first 17 bytes set to the representation of a.
+func hashAddr(b []byte, a netip.Addr, seed uint64) uint64 {
+	a16 := a.As16()
+	copy(b, a16[:])
+	b[16] = byte(a.BitLen())
+	h := xxHash64.Checksum(b, seed)
+	if zone := a.Zone(); zone != "" {
+		h = xxHash64.ChecksumString(zone, h)
+	}
+	return h
+}
+
+// HashTime returns the hash of t using seed, without allocating.
+// Times representing the same instant (see time.Time.Equal) have the same hash,
+// regardless of their location and monotonic clock reading.
+func HashTime(t time.Time, seed uint64) uint64 {
+	return xxHash64.ChecksumUint64x2(uint64(t.Unix()), uint64(t.Nanosecond()), seed)
+}
+
+// HashUUID returns the hash of the 16 bytes UUID u using seed, without allocating.
+// It is equal to the xxHash64 Checksum of u.
+func HashUUID(u [16]byte, seed uint64) uint64 {
+	return xxHash64.ChecksumUint64x2(binary.LittleEndian.Uint64(u[:8]), binary.LittleEndian.Uint64(u[8:]), seed)
+}
@@ -0,0 +1,64 @@
+package xxhmap_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhmap"
+)
+
+func TestHashAddr(t *testing.T) {
+	addrs := []netip.Addr{
+		netip.MustParseAddr("1.2.3.4"),
+		netip.MustParseAddr("::ffff:1.2.3.4"),
+		netip.MustParseAddr("fe80::1"),
+		netip.MustParseAddr("fe80::1%eth0"),
+		netip.MustParseAddr("fe80::1%eth1"),
+		{},
+	}
+	testEqualKeys(t, append(addrs, netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("fe80::1%eth0"))...)
+	for _, a := range addrs {
+		if got, want := xxhmap.NewHasher[netip.Addr](1).Hash(a), xxhmap.HashAddr(a, 1); got != want {
+			t.Errorf("%v: got 0x%x expected 0x%x", a, got, want)
+		}
+	}
+
+	testEqualKeys(t,
+		netip.MustParseAddrPort("1.2.3.4:80"),
+		netip.MustParseAddrPort("1.2.3.4:81"),
+		netip.MustParseAddrPort("[::1]:80"),
+		netip.MustParseAddrPort("1.2.3.4:80"),
+	)
+
+	a := netip.MustParseAddr("fe80::1%eth0")
+	ap := netip.AddrPortFrom(a, 443)
+	if n := testing.AllocsPerRun(10, func() { xxhmap.HashAddr(a, 0); xxhmap.HashAddrPort(ap, 0) }); n != 0 {
+		t.Errorf("got %f allocations", n)
+	}
+}
+
+func TestHashTime(t *testing.T) {
+	now := time.Now()
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		paris = time.FixedZone("CET", 3600)
+	}
+	h := xxhmap.HashTime(now, 0)
+	for _, same := range []time.Time{now.Round(0), now.UTC(), now.In(paris)} {
+		if got := xxhmap.HashTime(same, 0); got != h {
+			t.Errorf("%v: got 0x%x expected 0x%x", same, got, h)
+		}
+	}
+	if xxhmap.HashTime(now.Add(1), 0) == h {
+		t.Errorf("got the same hash for different times")
+	}
+}
+
+func TestHashUUID(t *testing.T) {
+	u := [16]byte{0xf8, 0x1d, 0x4f, 0xae, 0x7d, 0xec, 0x11, 0xd0, 0xa7, 0x65, 0x00, 0xa0, 0xc9, 0x1e, 0x6b, 0xf6}
+	if got, want := xxhmap.HashUUID(u, 3), xxHash64.Checksum(u[:], 3); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+}
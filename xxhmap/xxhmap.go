@@ -0,0 +1,286 @@
+// Package xxhmap provides xxHash64 (https://github.com/Cyan4973/xxHash/) based hash functions
+// for comparable key types, to be plugged into hash map implementations.
+//
+// Equal keys always have the same hash. Hashes are stable across processes for
+// strings, booleans and integers; other types may depend on the platform memory layout.
+package xxhmap
+
+import (
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Hashable is implemented by types defining their own canonical hash.
+// Equal values must return the same hash for the same seed.
+type Hashable interface {
+	XXHash64(seed uint64) uint64
+}
+
+// Hasher hashes keys of type K.
+// Use NewHasher to create one, the zero value is not usable.
+type Hasher[K comparable] struct {
+	seed uint64
+	hash func(K, uint64) uint64
+}
+
+// NewHasher returns a Hasher using seed.
+//
+// Keys implementing Hashable are hashed by their XXHash64 method.
+// Strings are hashed as their content, netip.Addr, netip.AddrPort and time.Time
+// values with HashAddr, HashAddrPort and HashTime, booleans and integers as their
+// little endian encoding of the same size.
+// Structs and arrays made only of booleans and integers, without padding,
+// such as [N]byte arrays, are hashed as their memory representation, in the native byte order,
+// without any reflection: their layout is only checked once by NewHasher.
+// Other types are hashed through a canonical encoding obtained by reflection,
+// in which the exported fields implementing Hashable are represented by their XXHash64 with a zero seed.
+func NewHasher[K comparable](seed uint64) Hasher[K] {
+	return Hasher[K]{
+		seed: seed,
+		hash: hashFunc[K](),
+	}
+}
+
+// NewFoldHasher returns a Hasher of string keys using seed that ignores case differences,
+// as defined by fold (see xxHash64.ChecksumStringFold).
+// It does not allocate folded copies of the keys.
+func NewFoldHasher[K ~string](seed uint64, fold xxHash64.Folding) Hasher[K] {
+	return Hasher[K]{
+		seed: seed,
+		hash: func(k K, seed uint64) uint64 {
+			return xxHash64.ChecksumStringFold(string(k), seed, fold)
+		},
+	}
+}
+
+// Hash returns the hash of key.
+func (h Hasher[K]) Hash(key K) uint64 {
+	return h.hash(key, h.seed)
+}
+
+// Func returns the hash function of h.
+func (h Hasher[K]) Func() func(K) uint64 {
+	hash, seed := h.hash, h.seed
+	return func(key K) uint64 {
+		return hash(key, seed)
+	}
+}
+
+// HashFuncFor returns the hash function for values of type T, as used by Hasher[T].
+// Byte slices are also supported and hashed as their content.
+// The returned function does not allocate, unless values of type T require reflection.
+// It panics on the first call if T is neither a byte slice nor a comparable type.
+func HashFuncFor[T any]() func(T, uint64) uint64 {
+	return hashFunc[T]()
+}
+
+// hashFunc returns the hash function for type K.
+func hashFunc[K any]() func(K, uint64) uint64 {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	switch {
+	case t.Implements(hashableType):
+		return func(k K, seed uint64) uint64 {
+			return any(k).(Hashable).XXHash64(seed)
+		}
+	case reflect.PointerTo(t).Implements(hashableType):
+		return func(k K, seed uint64) uint64 {
+			return any(&k).(Hashable).XXHash64(seed)
+		}
+	}
+	switch t {
+	case addrType:
+		return func(k K, seed uint64) uint64 {
+			return HashAddr(*(*netip.Addr)(unsafe.Pointer(&k)), seed)
+		}
+	case addrPortType:
+		return func(k K, seed uint64) uint64 {
+			return HashAddrPort(*(*netip.AddrPort)(unsafe.Pointer(&k)), seed)
+		}
+	case timeType:
+		return func(k K, seed uint64) uint64 {
+			return HashTime(*(*time.Time)(unsafe.Pointer(&k)), seed)
+		}
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(k K, seed uint64) uint64 {
+				return xxHash64.Checksum(*(*[]byte)(unsafe.Pointer(&k)), seed)
+			}
+		}
+	case reflect.String:
+		return func(k K, seed uint64) uint64 {
+			return xxHash64.ChecksumString(*(*string)(unsafe.Pointer(&k)), seed)
+		}
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return func(k K, seed uint64) uint64 {
+			b := [1]byte{*(*uint8)(unsafe.Pointer(&k))}
+			return xxHash64.Checksum(b[:], seed)
+		}
+	case reflect.Int16, reflect.Uint16:
+		return func(k K, seed uint64) uint64 {
+			var b [2]byte
+			binary.LittleEndian.PutUint16(b[:], *(*uint16)(unsafe.Pointer(&k)))
+			return xxHash64.Checksum(b[:], seed)
+		}
+	case reflect.Int32, reflect.Uint32:
+		return func(k K, seed uint64) uint64 {
+			return xxHash64.ChecksumUint32(*(*uint32)(unsafe.Pointer(&k)), seed)
+		}
+	case reflect.Int64, reflect.Uint64:
+		return func(k K, seed uint64) uint64 {
+			return xxHash64.ChecksumUint64(*(*uint64)(unsafe.Pointer(&k)), seed)
+		}
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return func(k K, seed uint64) uint64 {
+			return xxHash64.ChecksumUint64(uint64(*(*uint)(unsafe.Pointer(&k))), seed)
+		}
+	}
+	if isMemHashable(t) {
+		return memHashFunc[K](int(t.Size()))
+	}
+	return func(k K, seed uint64) uint64 {
+		e := encoders.Get().(*encoder)
+		e.buf = e.buf[:0]
+		e.encode(reflect.ValueOf(&k).Elem())
+		h := xxHash64.Checksum(e.buf, seed)
+		encoders.Put(e)
+		return h
+	}
+}
+
+// memHashFunc returns the hash function of the size bytes memory representation of type K.
+func memHashFunc[K any](size int) func(K, uint64) uint64 {
+	// Sizes fitting in one or two 64 bits words use the specialized Checksum functions.
+	switch size {
+	case 4:
+		return func(k K, seed uint64) uint64 {
+			b := (*[4]byte)(unsafe.Pointer(&k))
+			return xxHash64.ChecksumUint32(binary.LittleEndian.Uint32(b[:]), seed)
+		}
+	case 8:
+		return func(k K, seed uint64) uint64 {
+			b := (*[8]byte)(unsafe.Pointer(&k))
+			return xxHash64.ChecksumUint64(binary.LittleEndian.Uint64(b[:]), seed)
+		}
+	case 16:
+		return func(k K, seed uint64) uint64 {
+			b := (*[16]byte)(unsafe.Pointer(&k))
+			return xxHash64.ChecksumUint64x2(binary.LittleEndian.Uint64(b[:]), binary.LittleEndian.Uint64(b[8:]), seed)
+		}
+	}
+	return func(k K, seed uint64) uint64 {
+		return xxHash64.Checksum(unsafe.Slice((*byte)(unsafe.Pointer(&k)), size), seed)
+	}
+}
+
+var (
+	hashableType = reflect.TypeOf((*Hashable)(nil)).Elem()
+	addrType     = reflect.TypeOf(netip.Addr{})
+	addrPortType = reflect.TypeOf(netip.AddrPort{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// isMemHashable reports whether values of type t are equal if and only if their memory representations are.
+func isMemHashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	case reflect.Array:
+		return isMemHashable(t.Elem())
+	case reflect.Struct:
+		var size uintptr
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Name == "_" || !isMemHashable(f.Type) {
+				return false
+			}
+			size += f.Type.Size()
+		}
+		// Padding bytes are undefined.
+		return size == t.Size()
+	}
+	return false
+}
+
+var encoders = sync.Pool{
+	New: func() interface{} { return new(encoder) },
+}
+
+// encoder builds the canonical encoding of comparable values.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) u64(v uint64) {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, v)
+}
+
+func (e *encoder) float(f float64) {
+	if f == 0 {
+		// -0 == +0
+		f = 0
+	}
+	e.u64(math.Float64bits(f))
+}
+
+func (e *encoder) encode(v reflect.Value) {
+	if v.Type().Implements(hashableType) && v.CanInterface() {
+		e.u64(v.Interface().(Hashable).XXHash64(0))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.u64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.u64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.float(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		e.float(real(c))
+		e.float(imag(c))
+	case reflect.String:
+		s := v.String()
+		e.u64(uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		e.u64(uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e.encode(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "_" {
+				e.encode(v.Field(i))
+			}
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0)
+			return
+		}
+		v = v.Elem()
+		e.buf = append(e.buf, 1)
+		e.encode(reflect.ValueOf(v.Type().String()))
+		e.encode(v)
+	default:
+		panic("xxhmap: unhashable type " + v.Type().String())
+	}
+}
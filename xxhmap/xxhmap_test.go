@@ -0,0 +1,206 @@
+package xxhmap_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"unsafe"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhmap"
+)
+
+func TestString(t *testing.T) {
+	type name string
+	for _, s := range []string{"", "a", "hello world"} {
+		want := xxHash64.Checksum([]byte(s), 123)
+		if got := xxhmap.NewHasher[string](123).Hash(s); got != want {
+			t.Errorf("%q: got 0x%x expected 0x%x", s, got, want)
+		}
+		if got := xxhmap.NewHasher[name](123).Func()(name(s)); got != want {
+			t.Errorf("%q: got 0x%x expected 0x%x", s, got, want)
+		}
+	}
+}
+
+func TestIntegers(t *testing.T) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.MaxUint64-1)
+	if got, want := xxhmap.NewHasher[int64](0).Hash(-2), xxHash64.Checksum(b[:], 0); got != want {
+		t.Errorf("int64: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[int](0).Hash(-2), xxHash64.Checksum(b[:], 0); got != want {
+		t.Errorf("int: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[uint16](0).Hash(0xFFFE), xxHash64.Checksum(b[:2], 0); got != want {
+		t.Errorf("uint16: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[bool](0).Hash(true), xxHash64.Checksum([]byte{1}, 0); got != want {
+		t.Errorf("bool: got 0x%x expected 0x%x", got, want)
+	}
+}
+
+// testEqualKeys checks that equal keys have the same hash and that distinct keys are unlikely to collide.
+func testEqualKeys[K comparable](t *testing.T, keys ...K) {
+	t.Helper()
+	h := xxhmap.NewHasher[K](0xCAFE)
+	for i, a := range keys {
+		for j, b := range keys {
+			ha, hb := h.Hash(a), h.Hash(b)
+			if a == b && ha != hb {
+				t.Errorf("equal keys %d and %d: got hashes 0x%x and 0x%x", i, j, ha, hb)
+			}
+			if a != b && ha == hb {
+				t.Errorf("distinct keys %d and %d: got the same hash 0x%x", i, j, ha)
+			}
+		}
+	}
+}
+
+func TestStructs(t *testing.T) {
+	type point struct {
+		X, Y int32
+	}
+	testEqualKeys(t, point{1, 2}, point{2, 1}, point{1, 2}, point{})
+
+	type padded struct {
+		A uint8
+		B uint64
+	}
+	testEqualKeys(t, padded{1, 2}, padded{2, 1}, padded{1, 2})
+
+	type mixed struct {
+		Name  string
+		Value float64
+		Ptr   *int
+		Any   interface{}
+	}
+	var i int
+	testEqualKeys(t,
+		mixed{"a", 1, nil, nil},
+		mixed{"a", 1, &i, nil},
+		mixed{"b", 1, nil, nil},
+		mixed{"a", math.Copysign(0, -1), nil, 1},
+		mixed{"a", 0, nil, 1},
+		mixed{"a", 0, nil, "1"},
+	)
+	testEqualKeys(t, [4]byte{1, 2, 3, 4}, [4]byte{4, 3, 2, 1}, [4]byte{1, 2, 3, 4})
+}
+
+// memory returns the memory representation of *v, in the native byte order.
+func memory[T any](v *T) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(v)), unsafe.Sizeof(*v))
+}
+
+func TestMemory(t *testing.T) {
+	type key struct {
+		A uint32
+		B uint16
+		C [2]uint8
+		D int64
+	}
+	k := key{A: 1, B: 2, C: [2]uint8{3, 4}, D: -1}
+	if got, want := xxhmap.NewHasher[key](0).Hash(k), xxHash64.Checksum(memory(&k), 0); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	w3 := [3]uint32{1, 2, 3}
+	if got, want := xxhmap.NewHasher[[3]uint32](0).Hash(w3), xxHash64.Checksum(memory(&w3), 0); got != want {
+		t.Errorf("[3]uint32: got 0x%x expected 0x%x", got, want)
+	}
+	w4 := [4]uint32{1, 2, 3, 4}
+	if got, want := xxhmap.NewHasher[[4]uint32](0).Hash(w4), xxHash64.Checksum(memory(&w4), 0); got != want {
+		t.Errorf("[4]uint32: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[[4]byte](0).Hash([4]byte{1, 2, 3, 4}), xxHash64.Checksum([]byte{1, 2, 3, 4}, 0); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[[8]byte](0).Hash([8]byte{1, 2, 3, 4, 5, 6, 7, 8}), xxHash64.Checksum([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 0); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+}
+
+func BenchmarkStructKey(b *testing.B) {
+	type key struct {
+		ID    uint64
+		Shard uint32
+		Kind  uint32
+	}
+	h := xxhmap.NewHasher[key](0)
+	for i := 0; i < b.N; i++ {
+		h.Hash(key{ID: uint64(i), Shard: 1, Kind: 2})
+	}
+}
+
+func TestHashFuncFor(t *testing.T) {
+	type raw []byte
+	data := []byte("hello world")
+	if got, want := xxhmap.HashFuncFor[raw]()(data, 1), xxHash64.Checksum(data, 1); got != want {
+		t.Errorf("[]byte: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.HashFuncFor[string]()(string(data), 1), xxHash64.Checksum(data, 1); got != want {
+		t.Errorf("string: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.HashFuncFor[uint32]()(7, 1), xxhmap.NewHasher[uint32](1).Hash(7); got != want {
+		t.Errorf("uint32: got 0x%x expected 0x%x", got, want)
+	}
+
+	fs, fb, fi := xxhmap.HashFuncFor[string](), xxhmap.HashFuncFor[[]byte](), xxhmap.HashFuncFor[int]()
+	for name, allocs := range map[string]float64{
+		"string": testing.AllocsPerRun(10, func() { fs("abc", 0) }),
+		"[]byte": testing.AllocsPerRun(10, func() { fb(data, 0) }),
+		"int":    testing.AllocsPerRun(10, func() { fi(1, 0) }),
+	} {
+		if allocs != 0 {
+			t.Errorf("%s: got %f allocations", name, allocs)
+		}
+	}
+}
+
+func TestFoldHasher(t *testing.T) {
+	type header string
+	h := xxhmap.NewFoldHasher[header](0, xxHash64.FoldASCII)
+	if h.Hash("Content-Type") != h.Hash("content-type") {
+		t.Errorf("got different hashes for equal keys")
+	}
+	if h.Hash("Content-Type") == h.Hash("Content-Length") {
+		t.Errorf("got the same hash for different keys")
+	}
+}
+
+// user is identified by its ID only.
+type user struct {
+	ID   uint64
+	Name string
+}
+
+func (u user) XXHash64(seed uint64) uint64 {
+	return xxHash64.ChecksumUint64(u.ID, seed)
+}
+
+// ptrUser is identified by its ID only, with a pointer receiver XXHash64.
+type ptrUser struct {
+	ID   uint64
+	Name string
+}
+
+func (u *ptrUser) XXHash64(seed uint64) uint64 {
+	return xxHash64.ChecksumUint64(u.ID, seed)
+}
+
+func TestHashable(t *testing.T) {
+	if got, want := xxhmap.NewHasher[user](1).Hash(user{ID: 7, Name: "a"}), xxHash64.ChecksumUint64(7, 1); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := xxhmap.NewHasher[ptrUser](1).Hash(ptrUser{ID: 7, Name: "a"}), xxHash64.ChecksumUint64(7, 1); got != want {
+		t.Errorf("pointer receiver: got 0x%x expected 0x%x", got, want)
+	}
+
+	type entry struct {
+		User  user
+		Count float64
+	}
+	h := xxhmap.NewHasher[entry](1)
+	if h.Hash(entry{user{1, "a"}, 2}) != h.Hash(entry{user{1, "b"}, 2}) {
+		t.Errorf("nested Hashable field not honored")
+	}
+}
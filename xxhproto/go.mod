@@ -0,0 +1,11 @@
+module github.com/pierrec/xxHash/xxhproto
+
+go 1.22
+
+require (
+	github.com/pierrec/xxHash v0.0.0-20261017201621-df3f1d9f6ec8
+	google.golang.org/protobuf v1.34.2
+)
+
+// Local development builds against the root module of this repository.
+replace github.com/pierrec/xxHash => ../
@@ -0,0 +1,27 @@
+// Package xxhproto computes xxHash64 (https://github.com/Cyan4973/xxHash/) fingerprints
+// of protocol buffers messages, to detect schema or content changes.
+//
+// A fingerprint is the 64bits Hash value of the deterministic binary encoding of a message,
+// in which map entries are sorted by key. Equal messages have the same fingerprint with a
+// given version of the protobuf module, but the deterministic encoding is not guaranteed to be
+// stable across versions or languages, so fingerprints should not be persisted for long.
+//
+// XXH3-128 is not implemented by this module, hence fingerprints are XXH64 hashes.
+package xxhproto
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+var deterministic = proto.MarshalOptions{Deterministic: true}
+
+// Fingerprint returns the 64bits Hash value of the deterministic encoding of m.
+func Fingerprint(m proto.Message, seed uint64) (uint64, error) {
+	b, err := deterministic.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	return xxHash64.Checksum(b, seed), nil
+}
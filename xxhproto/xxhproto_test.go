@@ -0,0 +1,43 @@
+package xxhproto_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhproto"
+)
+
+func TestFingerprint(t *testing.T) {
+	fields := map[string]any{"a": 1, "b": "two", "c": true, "d": []any{1.5, "x"}, "e": map[string]any{"f": nil}}
+	m1, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := xxhproto.Fingerprint(m1, 0xCAFE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := xxHash64.Checksum(b, 0xCAFE); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	// Map ordering does not change the fingerprint.
+	for i := 0; i < 10; i++ {
+		m2, _ := structpb.NewStruct(fields)
+		if got, _ := xxhproto.Fingerprint(m2, 0xCAFE); got != want {
+			t.Fatalf("got 0x%x expected 0x%x", got, want)
+		}
+	}
+
+	m1.Fields["a"] = structpb.NewNumberValue(2)
+	if got, _ := xxhproto.Fingerprint(m1, 0xCAFE); got == want {
+		t.Errorf("modified message: same fingerprint 0x%x", got)
+	}
+}
@@ -0,0 +1,21 @@
+package xxhrand
+
+import "github.com/pierrec/xxHash/xxHash64"
+
+// seedDomain separates seeds derived by SeedFrom from plain hashes of the same data.
+const seedDomain = 0x9e3779b97f4a7c15
+
+// SeedFrom returns a 64bits seed derived from arbitrary seed material,
+// such as a host name, a UUID or a configuration string.
+//
+// The derivation is stable across versions and platforms: it is the xxHash64
+// ChecksumUint64 of the Checksum of data, both seeded with 0x9e3779b97f4a7c15,
+// so that it differs from Checksum(data, 0) and similar ad-hoc derivations.
+func SeedFrom(data []byte) uint64 {
+	return xxHash64.ChecksumUint64(xxHash64.Checksum(data, seedDomain), seedDomain)
+}
+
+// SeedFromString is SeedFrom for a string.
+func SeedFromString(s string) uint64 {
+	return xxHash64.ChecksumUint64(xxHash64.ChecksumString(s, seedDomain), seedDomain)
+}
@@ -0,0 +1,36 @@
+package xxhrand_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhrand"
+)
+
+func TestSeedFrom(t *testing.T) {
+	seen := map[uint64]string{}
+	for _, s := range []string{"", "a", "b", "host-1.example.com", "host-2.example.com",
+		"123e4567-e89b-12d3-a456-426614174000"} {
+		seed := xxhrand.SeedFrom([]byte(s))
+		if got := xxhrand.SeedFromString(s); got != seed {
+			t.Errorf("%q: got %x from string expected %x", s, got, seed)
+		}
+		if seed == xxHash64.ChecksumString(s, 0) {
+			t.Errorf("%q: seed is the plain hash", s)
+		}
+		if prev, ok := seen[seed]; ok {
+			t.Errorf("%q and %q have the same seed", s, prev)
+		}
+		seen[seed] = s
+	}
+
+	// The derivation is stable.
+	for s, want := range map[string]uint64{
+		"":    0xa3b288aefcac98f9,
+		"abc": 0xc1fadd5ddc2e9ffd,
+	} {
+		if got := xxhrand.SeedFromString(s); got != want {
+			t.Errorf("%q: got %#x expected %#x", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package xxhrand
+
+import (
+	"encoding/binary"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// At returns the n-th value of the sequence, whatever the current position of s.
+func (s *Source) At(n uint64) uint64 {
+	return xxHash64.ChecksumUint64(n, s.seed)
+}
+
+// Offset returns the index of the next value returned by Uint64.
+func (s *Source) Offset() uint64 {
+	return s.n
+}
+
+// SetOffset moves s to the n-th value of the sequence.
+func (s *Source) SetOffset(n uint64) {
+	s.n = n
+	s.nbuf = 0
+}
+
+// Fill sets dst to the next len(dst) values of the sequence.
+func (s *Source) Fill(dst []uint64) {
+	n, seed := s.n, s.seed
+	for i := range dst {
+		dst[i] = xxHash64.ChecksumUint64(n, seed)
+		n++
+	}
+	s.n = n
+	s.nbuf = 0
+}
+
+// Read fills p with the little endian encoding of the next values of the sequence.
+// The unused bytes of the last value are returned by the next call to Read
+// and discarded by the other methods.
+// It always returns len(p) and a nil error.
+func (s *Source) Read(p []byte) (int, error) {
+	n := copy(p, s.buf[len(s.buf)-s.nbuf:])
+	s.nbuf -= n
+	for ; len(p)-n >= 8; n += 8 {
+		binary.LittleEndian.PutUint64(p[n:], xxHash64.ChecksumUint64(s.n, s.seed))
+		s.n++
+	}
+	if n < len(p) {
+		binary.LittleEndian.PutUint64(s.buf[:], xxHash64.ChecksumUint64(s.n, s.seed))
+		s.n++
+		c := copy(p[n:], s.buf[:])
+		s.nbuf = len(s.buf) - c
+		n += c
+	}
+	return n, nil
+}
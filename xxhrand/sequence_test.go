@@ -0,0 +1,75 @@
+package xxhrand_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxhrand"
+)
+
+func TestAt(t *testing.T) {
+	s := xxhrand.New(7)
+	s.SetOffset(10)
+	if got := s.Offset(); got != 10 {
+		t.Errorf("got offset %d expected 10", got)
+	}
+	want := s.At(10)
+	if got := s.Uint64(); got != want {
+		t.Errorf("got %x expected %x", got, want)
+	}
+	if got := s.Offset(); got != 11 {
+		t.Errorf("got offset %d expected 11", got)
+	}
+}
+
+func TestFill(t *testing.T) {
+	s := xxhrand.New(7)
+	s.Uint64()
+	dst := make([]uint64, 10)
+	s.Fill(dst)
+	for i, v := range dst {
+		if want := s.At(uint64(i + 1)); v != want {
+			t.Errorf("value %d: got %x expected %x", i, v, want)
+		}
+	}
+	if got := s.Offset(); got != 11 {
+		t.Errorf("got offset %d expected 11", got)
+	}
+}
+
+func TestRead(t *testing.T) {
+	const n = 100
+	want := make([]byte, 8*n)
+	s := xxhrand.New(3)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint64(want[8*i:], s.At(uint64(i)))
+	}
+
+	// Reads of any size yield the same stream.
+	for _, size := range []int{1, 3, 8, 13, 64} {
+		s := xxhrand.New(3)
+		var got bytes.Buffer
+		buf := make([]byte, size)
+		for got.Len() < len(want) {
+			k, err := s.Read(buf)
+			if k != size || err != nil {
+				t.Fatalf("got %d, %v", k, err)
+			}
+			got.Write(buf)
+		}
+		if !bytes.Equal(got.Bytes()[:len(want)], want) {
+			t.Errorf("read size %d: stream mismatch", size)
+		}
+	}
+
+	// Partially read values are discarded by Uint64.
+	s = xxhrand.New(3)
+	if _, err := io.ReadFull(s, make([]byte, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Uint64(), s.At(1); got != want {
+		t.Errorf("got %x expected %x", got, want)
+	}
+}
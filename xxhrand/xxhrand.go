@@ -0,0 +1,48 @@
+// Package xxhrand implements a deterministic pseudo-random source based on xxHash64
+// (https://github.com/Cyan4973/xxHash/).
+//
+// The n-th value of a Source is the xxHash64 ChecksumUint64 of n seeded with the Source seed,
+// so that a sequence only depends on its seed and any value can be computed
+// without generating the previous ones.
+// It is fast and well distributed but not cryptographically secure.
+package xxhrand
+
+import "github.com/pierrec/xxHash/xxHash64"
+
+// Source is a pseudo-random source implementing both math/rand/v2.Source
+// and math/rand.Source64.
+// It generates the sequence of values at consecutive offsets, which can
+// also be read as bytes or accessed randomly with At.
+//
+// A Source is not safe for concurrent use.
+type Source struct {
+	seed uint64
+	n    uint64
+	buf  [8]byte // last value partially returned by Read
+	nbuf int     // number of unread bytes at the end of buf
+}
+
+// New returns a new Source with the given seed.
+func New(seed uint64) *Source {
+	return &Source{seed: seed}
+}
+
+// Uint64 returns the next pseudo-random value of the sequence.
+func (s *Source) Uint64() uint64 {
+	v := xxHash64.ChecksumUint64(s.n, s.seed)
+	s.n++
+	s.nbuf = 0
+	return v
+}
+
+// Int63 returns the next pseudo-random value of the sequence as a non negative int64.
+func (s *Source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Seed restarts the sequence with the given seed.
+func (s *Source) Seed(seed int64) {
+	s.seed = uint64(seed)
+	s.n = 0
+	s.nbuf = 0
+}
@@ -0,0 +1,89 @@
+package xxhrand_test
+
+import (
+	"math/bits"
+	mrand "math/rand"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhrand"
+)
+
+var (
+	_ rand.Source    = (*xxhrand.Source)(nil)
+	_ mrand.Source64 = (*xxhrand.Source)(nil)
+)
+
+func TestSequence(t *testing.T) {
+	s := xxhrand.New(42)
+	for i := uint64(0); i < 100; i++ {
+		if got, want := s.Uint64(), xxHash64.ChecksumUint64(i, 42); got != want {
+			t.Fatalf("value %d: got %x expected %x", i, got, want)
+		}
+	}
+
+	s.Seed(42)
+	if got, want := s.Uint64(), xxHash64.ChecksumUint64(0, 42); got != want {
+		t.Errorf("got %x after Seed expected %x", got, want)
+	}
+	for i := 0; i < 100; i++ {
+		if v := s.Int63(); v < 0 {
+			t.Fatalf("got negative Int63 %d", v)
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	r1 := rand.New(xxhrand.New(1))
+	r2 := rand.New(xxhrand.New(1))
+	r3 := rand.New(xxhrand.New(2))
+	same := 0
+	for i := 0; i < 100; i++ {
+		a, b, c := r1.IntN(1000), r2.IntN(1000), r3.IntN(1000)
+		if a != b {
+			t.Fatalf("got %d and %d for the same seed", a, b)
+		}
+		if a == c {
+			same++
+		}
+	}
+	if same > 10 {
+		t.Errorf("got %d identical values for different seeds", same)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	const n = 1 << 16
+	s := xxhrand.New(0)
+	var ones [64]int
+	for i := 0; i < n; i++ {
+		v := s.Uint64()
+		for b := 0; b < 64; b++ {
+			ones[b] += int(v >> b & 1)
+		}
+	}
+	// Each bit is set with a 1/2 probability: allow 5 standard deviations.
+	for b, c := range ones {
+		if d := c - n/2; d < -640 || d > 640 {
+			t.Errorf("bit %d set %d times out of %d", b, c, n)
+		}
+	}
+
+	var total int
+	for i := 0; i < n; i++ {
+		total += bits.OnesCount64(s.Uint64())
+	}
+	if mean := float64(total) / n; mean < 31.9 || mean > 32.1 {
+		t.Errorf("got %.3f bits set on average", mean)
+	}
+}
+
+func BenchmarkUint64(b *testing.B) {
+	s := xxhrand.New(0)
+	var v uint64
+	for i := 0; i < b.N; i++ {
+		v += s.Uint64()
+	}
+	_ = v
+}
@@ -0,0 +1,110 @@
+package xxhroll
+
+import (
+	"io"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Detector reports the windows of a stream that repeat earlier data.
+//
+// The stream is indexed by blocks of the window size, aligned on multiples of it,
+// while every window is looked up as the stream slides by one byte, so that
+// repeated data is found whatever its offset. Windows whose rolling hash match
+// are confirmed by their xxHash64 Checksum, and a match skips the following
+// overlapping windows. The memory used grows with the size of the stream
+// divided by the window size.
+type Detector struct {
+	h      Hash
+	ring   []byte // last window, its first byte being at n modulo the window size
+	n      int64  // number of bytes written
+	skip   int64  // windows starting before skip are not reported
+	blocks map[uint64][]block
+	d      xxHash64.Digest
+	f      func(pos, prev int64)
+}
+
+type block struct {
+	pos int64
+	sum uint64 // xxHash64 Checksum of the block
+}
+
+// NewDetector returns a Detector for windows of the given size using the table t,
+// DefaultTable if nil. It calls f with the position of every repeated window
+// and the position of the indexed block it repeats.
+// It panics if window is not strictly positive.
+func NewDetector(window int, t *Table, f func(pos, prev int64)) *Detector {
+	if window <= 0 {
+		panic("xxhroll: invalid window size")
+	}
+	return &Detector{
+		h:      Hash{t: t},
+		ring:   make([]byte, window),
+		blocks: map[uint64][]block{},
+		f:      f,
+	}
+}
+
+// Write adds p to the stream.
+// It never returns an error.
+func (d *Detector) Write(p []byte) (int, error) {
+	w := int64(len(d.ring))
+	for _, c := range p {
+		if d.n < w {
+			d.ring[d.n] = c
+			d.n++
+			if d.n == w {
+				d.h.Init(d.ring)
+				d.check()
+			}
+			continue
+		}
+		i := d.n % w
+		d.h.Slide(c, d.ring[i])
+		d.ring[i] = c
+		d.n++
+		d.check()
+	}
+	return len(p), nil
+}
+
+// check looks up the last window and indexes it if it is aligned.
+func (d *Detector) check() {
+	w := int64(len(d.ring))
+	pos := d.n - w
+	h := d.h.Sum64()
+	blocks, ok := d.blocks[h]
+	var sum uint64
+	if ok || pos%w == 0 {
+		sum = d.sum()
+	}
+	if ok && pos >= d.skip {
+		for _, b := range blocks {
+			if b.sum == sum {
+				d.f(pos, b.pos)
+				d.skip = pos + w
+				// The earlier block is enough to find further repeats.
+				return
+			}
+		}
+	}
+	if pos%w == 0 {
+		d.blocks[h] = append(blocks, block{pos, sum})
+	}
+}
+
+// sum returns the xxHash64 Checksum of the last window.
+func (d *Detector) sum() uint64 {
+	i := d.n % int64(len(d.ring))
+	xxHash64.NewInto(&d.d, 0)
+	d.d.Write(d.ring[i:])
+	d.d.Write(d.ring[:i])
+	return d.d.Sum64()
+}
+
+// Duplicates reads r until io.EOF and calls f with the repeated windows
+// of the given size, as reported by a Detector using DefaultTable.
+func Duplicates(r io.Reader, window int, f func(pos, prev int64)) error {
+	_, err := io.Copy(NewDetector(window, nil, f), r)
+	return err
+}
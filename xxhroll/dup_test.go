@@ -0,0 +1,66 @@
+package xxhroll_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhroll"
+)
+
+func TestDetector(t *testing.T) {
+	data := testvectors.Input(8192)
+	// data[1000:2000] is repeated at 4096.
+	stream := append(append(append([]byte{}, data[:4096]...), data[1000:2000]...), data[4096:]...)
+
+	var want []string
+	for prev := int64(1024); prev+64 <= 2000; prev += 64 {
+		want = append(want, fmt.Sprint(prev+3096, ":", prev))
+	}
+
+	var got []string
+	d := xxhroll.NewDetector(64, nil, func(pos, prev int64) {
+		got = append(got, fmt.Sprint(pos, ":", prev))
+	})
+	d.Write(stream)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+
+	got = nil
+	err := xxhroll.Duplicates(iotest.OneByteReader(bytes.NewReader(stream)), 64, func(pos, prev int64) {
+		got = append(got, fmt.Sprint(pos, ":", prev))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Duplicates: got %v expected %v", got, want)
+	}
+}
+
+func TestDetectorRun(t *testing.T) {
+	// A run of a single byte repeats the first block at every window.
+	var got []int64
+	d := xxhroll.NewDetector(8, nil, func(pos, prev int64) {
+		if prev != 0 {
+			t.Errorf("at %d: got previous position %d expected 0", pos, prev)
+		}
+		got = append(got, pos)
+	})
+	d.Write(bytes.Repeat([]byte{'a'}, 40))
+	if want := "[1 9 17 25]"; fmt.Sprint(got) != want {
+		t.Errorf("got %v expected %s", got, want)
+	}
+}
+
+func TestDetectorPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic on an invalid window size")
+		}
+	}()
+	xxhroll.NewDetector(0, nil, nil)
+}
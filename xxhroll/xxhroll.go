@@ -0,0 +1,94 @@
+// Package xxhroll implements a rolling hash over a sliding window of bytes,
+// the cyclic polynomial hash also known as buzhash, whose per byte values
+// are derived from xxHash64 (https://github.com/Cyan4973/xxHash/).
+//
+// The hash of the window c[0], ..., c[n-1] is the xor of the values t[c[i]]
+// rotated left by n-1-i bits. Sliding the window by one byte takes constant time,
+// whatever its size, which is the building block of deduplication
+// and pattern scanning algorithms.
+//
+// The hash is not a Checksum of the window: hashes that match must be confirmed,
+// for instance by comparing the xxHash64 Checksum of the windows.
+package xxhroll
+
+import (
+	"math/bits"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Table holds the per byte values of the rolling hash.
+type Table [256]uint64
+
+// NewTable returns the table derived from seed:
+// the value for byte b is the xxHash64 Checksum of b with the seed.
+func NewTable(seed uint64) *Table {
+	var t Table
+	for i := range t {
+		t[i] = xxHash64.Checksum([]byte{byte(i)}, seed)
+	}
+	return &t
+}
+
+// DefaultTable is the table derived from a zero seed.
+var DefaultTable = NewTable(0)
+
+// Hash is the rolling hash of a window.
+// The zero Hash uses DefaultTable and hashes an empty window.
+type Hash struct {
+	t *Table
+	h uint64
+	n int // window size
+}
+
+// New returns a Hash using the table t, DefaultTable if nil.
+func New(t *Table) *Hash {
+	return &Hash{t: t}
+}
+
+func (h *Hash) table() *Table {
+	if h.t == nil {
+		h.t = DefaultTable
+	}
+	return h.t
+}
+
+// Init sets the window to window, whose size is kept by the following slides.
+func (h *Hash) Init(window []byte) {
+	t := h.table()
+	var v uint64
+	for _, c := range window {
+		v = bits.RotateLeft64(v, 1) ^ t[c]
+	}
+	h.h = v
+	h.n = len(window)
+}
+
+// Slide slides the window by one byte: in is appended to the window
+// and out, the first byte of the window, is removed.
+func (h *Hash) Slide(in, out byte) {
+	t := h.table()
+	h.h = bits.RotateLeft64(h.h, 1) ^ bits.RotateLeft64(t[out], h.n) ^ t[in]
+}
+
+// Size returns the size of the window.
+func (h *Hash) Size() int {
+	return h.n
+}
+
+// Sum64 returns the 64bits hash of the window.
+func (h *Hash) Sum64() uint64 {
+	return h.h
+}
+
+// Sum32 returns the 32bits hash of the window, the xor of the halves of Sum64.
+func (h *Hash) Sum32() uint32 {
+	return uint32(h.h ^ h.h>>32)
+}
+
+// Sum64 returns the 64bits rolling hash of window using the table t, DefaultTable if nil.
+func Sum64(window []byte, t *Table) uint64 {
+	h := Hash{t: t}
+	h.Init(window)
+	return h.h
+}
@@ -0,0 +1,65 @@
+package xxhroll_test
+
+import (
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhroll"
+)
+
+func TestSlide(t *testing.T) {
+	data := testvectors.Input(1000)
+	table := xxhroll.NewTable(42)
+	for _, w := range []int{1, 7, 16, 48, 64, 65, 200} {
+		h := xxhroll.New(table)
+		h.Init(data[:w])
+		if h.Size() != w {
+			t.Errorf("got size %d expected %d", h.Size(), w)
+		}
+		for i := w; i < len(data); i++ {
+			h.Slide(data[i], data[i-w])
+			if got, want := h.Sum64(), xxhroll.Sum64(data[i-w+1:i+1], table); got != want {
+				t.Fatalf("window %d at %d: got %x expected %x", w, i, got, want)
+			}
+			if got, want := h.Sum32(), uint32(h.Sum64()^h.Sum64()>>32); got != want {
+				t.Fatalf("window %d at %d: got %x expected %x", w, i, got, want)
+			}
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	table := xxhroll.NewTable(7)
+	seen := map[uint64]bool{}
+	for i, v := range table {
+		if want := xxHash64.Checksum([]byte{byte(i)}, 7); v != want {
+			t.Errorf("byte %d: got %x expected %x", i, v, want)
+		}
+		seen[v] = true
+	}
+	if len(seen) != len(table) {
+		t.Errorf("got %d distinct values", len(seen))
+	}
+
+	var h xxhroll.Hash
+	h.Init([]byte("abc"))
+	if got, want := h.Sum64(), xxhroll.Sum64([]byte("abc"), xxhroll.DefaultTable); got != want {
+		t.Errorf("zero Hash: got %x expected %x", got, want)
+	}
+	if xxhroll.Sum64([]byte("abc"), table) == h.Sum64() {
+		t.Error("tables with distinct seeds give the same hash")
+	}
+}
+
+func BenchmarkSlide(b *testing.B) {
+	data := testvectors.Input(64 << 10)
+	h := xxhroll.New(nil)
+	h.Init(data[:64])
+	b.SetBytes(int64(len(data) - 64))
+	for n := 0; n < b.N; n++ {
+		for i := 64; i < len(data); i++ {
+			h.Slide(data[i], data[i-64])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package xxhsearch implements Rabin-Karp substring search with the xxhroll rolling hash,
+// candidate matches being confirmed by their xxHash32 Checksum (https://github.com/Cyan4973/xxHash/)
+// and then byte by byte.
+//
+// A Matcher searches many patterns in a single pass per pattern length,
+// which suits scanning large buffers, such as logs, for a set of long patterns.
+package xxhsearch
+
+import (
+	"bytes"
+	"math/bits"
+	"sort"
+
+	"github.com/pierrec/xxHash/xxHash32"
+	"github.com/pierrec/xxHash/xxhroll"
+)
+
+// Index returns the index of the first instance of needle in haystack, or -1 if there is none.
+// It is bytes.Index, which is vectorized on most platforms and faster than a rolling hash
+// for a single needle: the gain of this package comes from Matcher with many patterns.
+func Index(haystack, needle []byte) int {
+	return bytes.Index(haystack, needle)
+}
+
+// Matcher searches a set of patterns.
+// It is safe for concurrent use.
+type Matcher struct {
+	// groups of patterns with the same length, sorted by length.
+	groups []group
+	empty  int // index of the first empty pattern, -1 if none
+}
+
+type group struct {
+	size  int
+	cands map[uint64][]candidate // rolling hash to patterns
+	// filter has the bits of the low 16 bits of the pattern hashes set,
+	// sparing most windows a map lookup.
+	filter *[1 << 16 / 64]uint64
+}
+
+type candidate struct {
+	pattern []byte
+	index   int
+	sum     uint32 // xxHash32 Checksum of the pattern
+}
+
+// NewMatcher returns a Matcher for patterns, identified by their index.
+// The patterns must not be modified while the Matcher is in use.
+func NewMatcher(patterns ...[]byte) *Matcher {
+	m := &Matcher{empty: -1}
+	bySize := map[int]*group{}
+	for i, p := range patterns {
+		if len(p) == 0 {
+			if m.empty < 0 {
+				m.empty = i
+			}
+			continue
+		}
+		g := bySize[len(p)]
+		if g == nil {
+			g = &group{size: len(p), cands: map[uint64][]candidate{}, filter: new([1 << 16 / 64]uint64)}
+			bySize[len(p)] = g
+		}
+		h := xxhroll.Sum64(p, nil)
+		g.filter[uint16(h)/64] |= 1 << (h % 64)
+		g.cands[h] = append(g.cands[h], candidate{p, i, xxHash32.Checksum(p, 0)})
+	}
+	for _, g := range bySize {
+		m.groups = append(m.groups, *g)
+	}
+	sort.Slice(m.groups, func(i, j int) bool { return m.groups[i].size < m.groups[j].size })
+	return m
+}
+
+// Index returns the index in haystack of the leftmost match and the index of the matched pattern,
+// the first one given to NewMatcher if several patterns match there.
+// It returns -1, -1 if no pattern matches.
+func (m *Matcher) Index(haystack []byte) (pos, pattern int) {
+	pos, pattern = -1, -1
+	if m.empty >= 0 {
+		return 0, m.empty
+	}
+	for _, g := range m.groups {
+		end := len(haystack)
+		if pos >= 0 {
+			// Only look for an earlier match, or a match at pos with a lower pattern index.
+			end = pos + g.size
+			if end > len(haystack) {
+				end = len(haystack)
+			}
+		}
+		if i, p := g.index(haystack[:end]); i >= 0 && (pos < 0 || i < pos || i == pos && p < pattern) {
+			pos, pattern = i, p
+		}
+	}
+	return
+}
+
+// FindAll calls f with every match in haystack, by increasing position for each pattern length,
+// until f returns false. Overlapping matches are reported.
+func (m *Matcher) FindAll(haystack []byte, f func(pos, pattern int) bool) {
+	if m.empty >= 0 {
+		for i := 0; i <= len(haystack); i++ {
+			if !f(i, m.empty) {
+				return
+			}
+		}
+	}
+	for _, g := range m.groups {
+		if !g.scan(haystack, f) {
+			return
+		}
+	}
+}
+
+// index returns the first match of the group in s.
+func (g *group) index(s []byte) (pos, pattern int) {
+	pos, pattern = -1, -1
+	g.scan(s, func(i, p int) bool {
+		if pos < 0 || p < pattern {
+			pos, pattern = i, p
+		}
+		// Keep looking for a lower pattern index at the same position.
+		return false
+	})
+	return
+}
+
+// scan calls f with the matches of the group in s until f returns false, and reports whether it did not.
+func (g *group) scan(s []byte, f func(pos, pattern int) bool) bool {
+	n := g.size
+	if n > len(s) {
+		return true
+	}
+	t, filter := xxhroll.DefaultTable, g.filter
+	h := xxhroll.Sum64(s[:n], t)
+	for i, end := 0, len(s)-n; ; i++ {
+		if filter[uint16(h)/64]&(1<<(h%64)) == 0 {
+			// No pattern has this hash.
+		} else if cands, ok := g.cands[h]; ok {
+			w := s[i : i+n]
+			sum := xxHash32.Checksum(w, 0)
+			stop := false
+			for _, c := range cands {
+				if c.sum == sum && bytes.Equal(c.pattern, w) && !f(i, c.index) {
+					stop = true
+				}
+			}
+			if stop {
+				return false
+			}
+		}
+		if i >= end {
+			return true
+		}
+		h = bits.RotateLeft64(h, 1) ^ bits.RotateLeft64(t[s[i]], n) ^ t[s[i+n]]
+	}
+}
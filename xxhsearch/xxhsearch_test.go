@@ -0,0 +1,95 @@
+package xxhsearch_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/pierrec/xxHash/testvectors"
+	"github.com/pierrec/xxHash/xxhsearch"
+)
+
+func TestIndex(t *testing.T) {
+	data := testvectors.Input(4096)
+	for _, tc := range []struct{ s, sep string }{
+		{"", ""},
+		{"abc", ""},
+		{"", "a"},
+		{"abc", "abcd"},
+		{"abc", "abc"},
+		{"xabcabc", "abc"},
+		{"aaaab", "aab"},
+		{"abcabd", "abd"},
+		{"abc", "x"},
+		{string(data), string(data[1000:1100])},
+		{string(data), string(data[4000:])},
+		{string(data), string(data[:4])},
+		{string(data), "not in there"},
+	} {
+		s, sep := []byte(tc.s), []byte(tc.sep)
+		want := bytes.Index(s, sep)
+		if got := xxhsearch.Index(s, sep); got != want {
+			t.Errorf("Index(%.10q, %.10q) = %d expected %d", tc.s, tc.sep, got, want)
+		}
+		if got, _ := xxhsearch.NewMatcher(sep).Index(s); got != want {
+			t.Errorf("Matcher.Index(%.10q, %.10q) = %d expected %d", tc.s, tc.sep, got, want)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	s := []byte("the quick brown fox jumps over the lazy dog")
+	m := xxhsearch.NewMatcher([]byte("lazy"), []byte("fox"), []byte("the"), []byte("own"), []byte("the quick"))
+	if pos, p := m.Index(s); pos != 0 || p != 2 {
+		t.Errorf("Index = %d, %d expected 0, 2", pos, p)
+	}
+	if pos, p := m.Index(s[1:]); pos != 11 || p != 3 {
+		t.Errorf("Index = %d, %d expected 11, 3", pos, p)
+	}
+	if pos, p := m.Index([]byte("none")); pos != -1 || p != -1 {
+		t.Errorf("Index = %d, %d expected -1, -1", pos, p)
+	}
+
+	var got []string
+	m.FindAll(s, func(pos, pattern int) bool {
+		got = append(got, fmt.Sprint(pos, ":", pattern))
+		return true
+	})
+	// By pattern length, then by position.
+	want := "[0:2 12:3 16:1 31:2 35:0 0:4]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("FindAll = %v expected %v", got, want)
+	}
+
+	n := 0
+	m.FindAll(s, func(pos, pattern int) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("FindAll did not stop: %d calls", n)
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	data := testvectors.Input(1 << 20)
+	patterns := make([][]byte, 32)
+	for i := range patterns {
+		patterns[i] = append(testvectors.Input(64 + i)[i+1:], byte(i))
+	}
+	b.Run("xxhsearch", func(b *testing.B) {
+		m := xxhsearch.NewMatcher(patterns...)
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			m.Index(data)
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			for _, p := range patterns {
+				bytes.Index(data, p)
+			}
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package xxhstruct
+
+import (
+	"hash"
+	"io"
+	"reflect"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// Digest computes the 64bits Hash value of values added one at a time
+// with the canonical encoding used by Hash.
+// It is used by the code generated by cmd/xxhashgen to hash structs without reflection.
+//
+// A struct is added by adding the name and the value of each of its fields in order,
+// which produces the same hash as Hash over the struct:
+//
+//	d := xxhstruct.NewDigest(seed)
+//	d.AddField("Name")
+//	d.AddString(v.Name)
+//	d.AddField("Tags")
+//	d.AddLen(len(v.Tags))
+//	for _, tag := range v.Tags {
+//		d.AddString(tag)
+//	}
+//	h := d.Sum64()
+type Digest struct {
+	w    io.Writer   // receives the encoding
+	h    hash.Hash64 // nil if w is not a hash
+	buf  [8]byte
+	err  error
+	opts Options
+	// visiting holds the pointers, slices and maps being encoded with their depth.
+	visiting map[visit]int
+}
+
+// NewDigest returns a new Digest using seed.
+func NewDigest(seed uint64) *Digest {
+	return NewDigestWith(seed, Options{})
+}
+
+// NewDigestWith returns a new Digest using seed and encoding values according to opts.
+func NewDigestWith(seed uint64, opts Options) *Digest {
+	h := xxHash64.New(seed)
+	return &Digest{w: h, h: h, opts: opts}
+}
+
+// Reset resets the Digest to its initial state.
+func (d *Digest) Reset() {
+	d.h.Reset()
+	d.err = nil
+	d.visiting = nil
+}
+
+// Sum64 returns the 64bits Hash value of the values added so far.
+func (d *Digest) Sum64() uint64 {
+	return d.h.Sum64()
+}
+
+// Err returns the first error encountered by AddValue.
+func (d *Digest) Err() error {
+	return d.err
+}
+
+// Write adds raw bytes to the Digest.
+// Unlike AddBytes, it does not encode the length of b.
+// It never returns an error.
+func (d *Digest) Write(b []byte) (int, error) {
+	return d.w.Write(b)
+}
+
+// AddBool adds a boolean.
+func (d *Digest) AddBool(b bool) {
+	if b {
+		d.byte(1)
+	} else {
+		d.byte(0)
+	}
+}
+
+// AddInt adds a signed integer of any size.
+func (d *Digest) AddInt(v int64) {
+	d.u64(uint64(v))
+}
+
+// AddUint adds an unsigned integer of any size.
+func (d *Digest) AddUint(v uint64) {
+	d.u64(v)
+}
+
+// AddFloat adds a floating point number of any size.
+func (d *Digest) AddFloat(f float64) {
+	d.float(f)
+}
+
+// AddComplex adds a complex number of any size.
+func (d *Digest) AddComplex(c complex128) {
+	d.float(real(c))
+	d.float(imag(c))
+}
+
+// AddString adds a string.
+func (d *Digest) AddString(s string) {
+	d.text(s)
+}
+
+// AddBytes adds a byte slice.
+func (d *Digest) AddBytes(b []byte) {
+	d.u64(uint64(len(b)))
+	d.w.Write(b)
+}
+
+// AddLen adds the length of an array, a slice or a map, to be followed by its elements.
+func (d *Digest) AddLen(n int) {
+	d.u64(uint64(n))
+}
+
+// AddNil adds whether a pointer is nil, to be followed by the value it points to if not.
+func (d *Digest) AddNil(isNil bool) {
+	if isNil {
+		d.byte(0)
+	} else {
+		d.byte(1)
+	}
+}
+
+// AddField adds the name of a struct field, to be followed by its value.
+func (d *Digest) AddField(name string) {
+	d.string(name)
+}
+
+// AddValue adds the value pointed to by ptr using reflection, as Hash does.
+// Errors are reported by Err.
+func (d *Digest) AddValue(ptr any) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		panic("xxhstruct: AddValue requires a non nil pointer")
+	}
+	if err := d.encode(v.Elem()); err != nil && d.err == nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,58 @@
+package xxhstruct_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxhstruct"
+)
+
+func TestDigest(t *testing.T) {
+	type point struct {
+		X, Y  float64
+		Label *string
+		Tags  []string
+		Meta  map[string]int
+		Raw   []byte
+		On    bool
+		N     int8
+	}
+	label := "p"
+	p := point{X: 1, Y: -2, Label: &label, Tags: []string{"a"}, Meta: map[string]int{"m": 1}, Raw: []byte{1}, On: true, N: -3}
+
+	d := xxhstruct.NewDigest(0xCAFE)
+	d.AddField("X")
+	d.AddFloat(p.X)
+	d.AddField("Y")
+	d.AddFloat(p.Y)
+	d.AddField("Label")
+	d.AddNil(p.Label == nil)
+	d.AddString(*p.Label)
+	d.AddField("Tags")
+	d.AddLen(len(p.Tags))
+	for _, tag := range p.Tags {
+		d.AddString(tag)
+	}
+	d.AddField("Meta")
+	d.AddValue(&p.Meta)
+	d.AddField("Raw")
+	d.AddBytes(p.Raw)
+	d.AddField("On")
+	d.AddBool(p.On)
+	d.AddField("N")
+	d.AddInt(int64(p.N))
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.Sum64(), mustHash(t, p); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	d.Reset()
+	f := func() {}
+	d.AddValue(&f)
+	var uerr *xxhstruct.UnsupportedTypeError
+	if !errors.As(d.Err(), &uerr) {
+		t.Errorf("got error %v expected an UnsupportedTypeError", d.Err())
+	}
+}
@@ -0,0 +1,106 @@
+package xxhstruct
+
+import (
+	"math/big"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Hasher is implemented by types defining their own canonical encoding,
+// typically because their state is held in unexported fields.
+// Hash calls HashXX instead of walking the value with reflection.
+//
+// HashXX adds the values making up the canonical encoding with the Add methods of d,
+// and must not call AddValue on its receiver, which would recurse forever.
+// A HashXX method promoted from an embedded field is not used for the embedding struct,
+// whose fields are encoded as usual.
+// Code generated by cmd/xxhashgen implements Hasher.
+type Hasher interface {
+	HashXX(d *Digest)
+}
+
+var hasherType = reflect.TypeOf((*Hasher)(nil)).Elem()
+
+// builtins holds the encodings of standard library types with unexported state.
+var builtins = map[reflect.Type]func(d *Digest, v reflect.Value){
+	reflect.TypeOf(time.Time{}): func(d *Digest, v reflect.Value) {
+		t := v.Interface().(time.Time)
+		d.AddInt(t.Unix())
+		d.AddInt(int64(t.Nanosecond()))
+	},
+	reflect.TypeOf(big.Int{}): func(d *Digest, v reflect.Value) {
+		var x *big.Int
+		if v.CanAddr() {
+			x = v.Addr().Interface().(*big.Int)
+		} else {
+			x = new(big.Int)
+			*x = v.Interface().(big.Int)
+		}
+		d.AddInt(int64(x.Sign()))
+		d.AddBytes(x.Bytes())
+	},
+}
+
+// encodeHook encodes v with its HashXX method or the builtin encoding of its type,
+// reporting whether it did.
+func (d *Digest) encodeHook(v reflect.Value) (bool, error) {
+	t := v.Type()
+	if k := t.Kind(); k == reflect.Pointer || k == reflect.Interface {
+		// Pointers are encoded as their nil flag followed by the value they point to,
+		// which is where the hook applies.
+		return false, nil
+	}
+	if f, ok := builtins[t]; ok {
+		f(d, v)
+		return true, d.err
+	}
+	var h Hasher
+	switch {
+	case !declaresHook(t):
+		return false, nil
+	case t.Implements(hasherType):
+		h = v.Interface().(Hasher)
+	case reflect.PointerTo(t).Implements(hasherType):
+		if !v.CanAddr() {
+			p := reflect.New(t).Elem()
+			p.Set(v)
+			v = p
+		}
+		h = v.Addr().Interface().(Hasher)
+	default:
+		return false, nil
+	}
+	h.HashXX(d)
+	return true, d.err
+}
+
+var hookCache sync.Map // map[reflect.Type]bool
+
+// declaresHook reports whether t or *t has a HashXX method that is not promoted from an embedded field.
+func declaresHook(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		// Only structs have promoted methods.
+		return true
+	}
+	if ok, found := hookCache.Load(t); found {
+		return ok.(bool)
+	}
+	ok := false
+	for _, pt := range []reflect.Type{t, reflect.PointerTo(t)} {
+		m, found := pt.MethodByName("HashXX")
+		if !found {
+			continue
+		}
+		// Promoted methods are compiler generated wrappers, for both t and *t,
+		// while a declared method is the method itself for its receiver type.
+		if f := runtime.FuncForPC(m.Func.Pointer()); f != nil {
+			if file, _ := f.FileLine(f.Entry()); file != "<autogenerated>" {
+				ok = true
+			}
+		}
+	}
+	hookCache.Store(t, ok)
+	return ok
+}
@@ -0,0 +1,119 @@
+package xxhstruct_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pierrec/xxHash/xxhstruct"
+)
+
+// version hashes only its canonical form.
+type version struct {
+	major, minor int
+	raw          string
+}
+
+func (v version) HashXX(d *xxhstruct.Digest) {
+	d.AddInt(int64(v.major))
+	d.AddInt(int64(v.minor))
+}
+
+// secret has a pointer receiver hook.
+type secret struct {
+	key []byte
+}
+
+func (s *secret) HashXX(d *xxhstruct.Digest) {
+	d.AddBytes(s.key)
+}
+
+func TestHasher(t *testing.T) {
+	a := mustHash(t, version{1, 2, "v1.2"})
+	if b := mustHash(t, version{1, 2, "1.2.0"}); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+	if b := mustHash(t, version{1, 3, "v1.3"}); a == b {
+		t.Errorf("different versions: same hash 0x%x", a)
+	}
+
+	d := xxhstruct.NewDigest(0xCAFE)
+	d.AddInt(1)
+	d.AddInt(2)
+	if got := d.Sum64(); got != a {
+		t.Errorf("got 0x%x expected 0x%x", got, a)
+	}
+
+	// Pointer receivers are used for addressable and non addressable values.
+	type holder struct {
+		S secret
+		M map[string]secret
+		P *secret
+	}
+	h1 := holder{S: secret{[]byte("a")}, M: map[string]secret{"k": {[]byte("b")}}, P: &secret{[]byte("c")}}
+	h2 := holder{S: secret{[]byte("a")}, M: map[string]secret{"k": {[]byte("b")}}, P: &secret{[]byte("c")}}
+	if a, b := mustHash(t, h1), mustHash(t, h2); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+	h2.M["k"] = secret{[]byte("x")}
+	if a, b := mustHash(t, h1), mustHash(t, h2); a == b {
+		t.Errorf("different values: same hash 0x%x", a)
+	}
+	if a, b := mustHash(t, secret{[]byte("a")}), mustHash(t, &secret{[]byte("a")}); a == b {
+		t.Errorf("value and pointer: same hash 0x%x", a)
+	}
+}
+
+// outer embeds a Hasher without declaring its own HashXX.
+type outer struct {
+	*secret
+	Extra int
+}
+
+// sealed embeds a Hasher and declares its own HashXX.
+type sealed struct {
+	secret
+	Extra int
+}
+
+func (s sealed) HashXX(d *xxhstruct.Digest) {
+	d.AddBytes(s.key)
+}
+
+func TestHasherEmbedded(t *testing.T) {
+	// The promoted HashXX does not hide the other fields.
+	a := mustHash(t, outer{&secret{[]byte("a")}, 1})
+	if b := mustHash(t, outer{&secret{[]byte("a")}, 2}); a == b {
+		t.Errorf("different fields: same hash 0x%x", a)
+	}
+
+	// The declared HashXX is used.
+	a = mustHash(t, sealed{secret{[]byte("a")}, 1})
+	if b := mustHash(t, sealed{secret{[]byte("a")}, 2}); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	now := time.Now()
+	a := mustHash(t, now)
+	if b := mustHash(t, now.Round(0).In(time.FixedZone("X", 3600))); a != b {
+		t.Errorf("same instant: got 0x%x and 0x%x", a, b)
+	}
+	if b := mustHash(t, now.Add(1)); a == b {
+		t.Errorf("different instants: same hash 0x%x", a)
+	}
+
+	x, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	y := new(big.Int).Neg(x)
+	hx, hy := mustHash(t, x), mustHash(t, y)
+	if hx == hy {
+		t.Errorf("x and -x: same hash 0x%x", hx)
+	}
+	if h := mustHash(t, new(big.Int).Set(x)); h != hx {
+		t.Errorf("got 0x%x expected 0x%x", h, hx)
+	}
+	if a, b := mustHash(t, map[string]big.Int{"x": *x}), mustHash(t, map[string]*big.Int{"x": x}); a == b {
+		t.Errorf("value and pointer: same hash 0x%x", a)
+	}
+}
@@ -0,0 +1,67 @@
+package xxhstruct
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// FloatMode selects how floating point numbers are canonicalized before being encoded.
+type FloatMode int
+
+const (
+	// FloatZero encodes -0 as 0, so that equal numbers have the same encoding.
+	// NaNs are encoded as their bits.
+	FloatZero FloatMode = iota
+	// FloatCanonical is like FloatZero but also encodes all NaNs as the same value.
+	FloatCanonical
+	// FloatExact encodes numbers as their bits: -0 and 0 differ, as do NaNs with different payloads.
+	FloatExact
+)
+
+// Options controls the encoding of values.
+// The zero value is the encoding used by Hash.
+type Options struct {
+	// SkipZero omits the struct fields holding the zero value of their type,
+	// so that adding a field to a struct does not change the hash of the values not setting it.
+	// It does not apply to the fields added by HashXX methods.
+	SkipZero bool
+	// Floats selects the canonicalization of floating point numbers.
+	Floats FloatMode
+	// FoldStrings case folds strings with Folding, so that strings equal under that folding
+	// have the same encoding. Folded strings are encoded as their
+	// xxHash64.ChecksumStringFold with seed 0, so distinct strings may collide.
+	// Struct field names are not folded.
+	FoldStrings bool
+	// Folding is the case folding applied by FoldStrings, xxHash64.FoldASCII by default.
+	Folding xxHash64.Folding
+}
+
+// HashWith is like Hash but encodes v according to opts.
+func HashWith(v any, seed uint64, opts Options) (uint64, error) {
+	d := NewDigestWith(seed, opts)
+	if err := d.encode(reflect.ValueOf(v)); err != nil {
+		return 0, err
+	}
+	return d.Sum64(), nil
+}
+
+// canonicalNaN is the encoding of all NaNs with FloatCanonical.
+const canonicalNaN = 0x7FF8000000000001
+
+func (o *Options) floatBits(f float64) uint64 {
+	switch o.Floats {
+	case FloatExact:
+		return math.Float64bits(f)
+	case FloatCanonical:
+		if f != f {
+			return canonicalNaN
+		}
+	}
+	if f == 0 {
+		// -0 == +0
+		f = 0
+	}
+	return math.Float64bits(f)
+}
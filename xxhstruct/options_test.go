@@ -0,0 +1,94 @@
+package xxhstruct_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhstruct"
+)
+
+func mustHashWith(t *testing.T, v any, opts xxhstruct.Options) uint64 {
+	t.Helper()
+	h, err := xxhstruct.HashWith(v, 0xCAFE, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestOptionsDefault(t *testing.T) {
+	v := config{Name: "a", Tags: []string{"x"}}
+	if got, want := mustHashWith(t, v, xxhstruct.Options{}), mustHash(t, v); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestOptionsSkipZero(t *testing.T) {
+	type v1 struct {
+		Name string
+	}
+	type v2 struct {
+		Name    string
+		Timeout int
+		Tags    []string
+	}
+	opts := xxhstruct.Options{SkipZero: true}
+	a := mustHashWith(t, v1{Name: "a"}, opts)
+	if b := mustHashWith(t, v2{Name: "a"}, opts); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+	if b := mustHashWith(t, v2{Name: "a", Timeout: 1}, opts); a == b {
+		t.Errorf("non zero field: same hash 0x%x", a)
+	}
+	if b := mustHash(t, v2{Name: "a"}); a == b {
+		t.Errorf("without SkipZero: same hash 0x%x", a)
+	}
+}
+
+func TestOptionsFloats(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	nan1 := math.NaN()
+	nan2 := math.Float64frombits(math.Float64bits(nan1) ^ 1)
+	for _, tc := range []struct {
+		mode        xxhstruct.FloatMode
+		zeros, nans bool // whether the values have the same hash
+	}{
+		{xxhstruct.FloatZero, true, false},
+		{xxhstruct.FloatCanonical, true, true},
+		{xxhstruct.FloatExact, false, false},
+	} {
+		opts := xxhstruct.Options{Floats: tc.mode}
+		if same := mustHashWith(t, negZero, opts) == mustHashWith(t, 0.0, opts); same != tc.zeros {
+			t.Errorf("mode %d: got same hash %v for zeros", tc.mode, same)
+		}
+		if same := mustHashWith(t, nan1, opts) == mustHashWith(t, nan2, opts); same != tc.nans {
+			t.Errorf("mode %d: got same hash %v for NaNs", tc.mode, same)
+		}
+	}
+}
+
+func TestOptionsFoldStrings(t *testing.T) {
+	type user struct {
+		Email string
+		Roles map[string]bool
+	}
+	a := user{Email: "Bob@Example.com", Roles: map[string]bool{"Admin": true}}
+	b := user{Email: "bob@example.COM", Roles: map[string]bool{"ADMIN": true}}
+	for _, fold := range []xxHash64.Folding{xxHash64.FoldASCII, xxHash64.FoldUnicode} {
+		opts := xxhstruct.Options{FoldStrings: true, Folding: fold}
+		if ha, hb := mustHashWith(t, a, opts), mustHashWith(t, b, opts); ha != hb {
+			t.Errorf("fold %d: got 0x%x and 0x%x", fold, ha, hb)
+		}
+	}
+	if ha, hb := mustHash(t, a), mustHash(t, b); ha == hb {
+		t.Errorf("without folding: same hash 0x%x", ha)
+	}
+
+	d := xxhstruct.NewDigestWith(0xCAFE, xxhstruct.Options{FoldStrings: true})
+	d.AddString("ABC")
+	want := mustHashWith(t, "abc", xxhstruct.Options{FoldStrings: true})
+	if got := d.Sum64(); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+}
@@ -0,0 +1,311 @@
+// Package xxhstruct computes xxHash64 (https://github.com/Cyan4973/xxHash/) hashes of arbitrary Go values,
+// such as configurations or cache keys, by walking them with reflection
+// and hashing a canonical encoding of their content.
+//
+// The encoding only depends on the values, not on their memory layout,
+// so that hashes are stable across processes and platforms:
+//   - booleans are encoded as one byte, 0 or 1
+//   - integers as 8 little endian bytes, whatever their size
+//   - floats as the 8 little endian bytes of their float64 value, -0 being encoded as 0,
+//     and complex numbers as their real and imaginary parts
+//   - strings as their length followed by their bytes
+//   - arrays and slices as their length followed by their elements, nil slices being empty
+//   - structs as their exported fields, each encoded as its name followed by its value
+//     (see Hash for the field tags)
+//   - pointers and interfaces as 0 if they are nil or 1 followed by the value they point to,
+//     preceded by the name of its type for interfaces
+//   - maps as their length followed by their entries, each encoded as its key followed by its value
+//   - types implementing Hasher, by their HashXX method
+//   - time.Time values as their Unix time in seconds followed by their nanoseconds,
+//     ignoring their location and monotonic clock reading
+//   - big.Int values as their sign followed by the bytes of their absolute value
+//
+// Map entries are written in a canonical order that does not depend on their iteration order:
+// they are sorted by the bytewise order of the encoding of their keys, entries with identically
+// encoded keys (such as NaN float keys) being sorted by the encoding of their values.
+// Hence two maps with the same content always have the same hash, although the
+// order does not follow the natural order of the keys: integers are compared by their
+// little endian encoding and strings by their length first.
+//
+// The encoding of floats, strings and zero struct fields can be changed with Options.
+//
+// Cyclic data structures are supported: a pointer, a slice or a map referring to one of
+// the values containing it is encoded as a back reference instead of the value itself.
+// Back references are encoded as 2 for pointers and as the maximum uint64 length for slices and maps,
+// followed by the number of pointers, slices and maps between the reference and the value it refers to.
+//
+// Channels, functions and unsafe pointers cannot be hashed.
+package xxhstruct
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pierrec/xxHash/xxHash64"
+)
+
+// UnsupportedTypeError is returned by Hash when a value cannot be hashed.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "xxhstruct: unsupported type " + e.Type.String()
+}
+
+// Hash returns the 64bits Hash value of the canonical encoding of v.
+//
+// The hashing of struct fields can be controlled with the "xxhash" field tag:
+//
+//	// Field is ignored.
+//	Field int `xxhash:"-"`
+//	// Field is hashed with the name "other", so that it can be renamed
+//	// without changing the hash.
+//	Field int `xxhash:"other"`
+func Hash(v any, seed uint64) (uint64, error) {
+	return HashWith(v, seed, Options{})
+}
+
+func (d *Digest) byte(b byte) {
+	d.buf[0] = b
+	d.w.Write(d.buf[:1])
+}
+
+func (d *Digest) u64(v uint64) {
+	binary.LittleEndian.PutUint64(d.buf[:], v)
+	d.w.Write(d.buf[:])
+}
+
+func (d *Digest) float(f float64) {
+	d.u64(d.opts.floatBits(f))
+}
+
+func (d *Digest) string(s string) {
+	d.u64(uint64(len(s)))
+	d.w.Write([]byte(s))
+}
+
+// text encodes the string value s, case folded if required.
+func (d *Digest) text(s string) {
+	if d.opts.FoldStrings {
+		d.u64(xxHash64.ChecksumStringFold(s, 0, d.opts.Folding))
+		return
+	}
+	d.string(s)
+}
+
+func (d *Digest) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		// Nil interface.
+		d.byte(0)
+		return nil
+	}
+	if ok, err := d.encodeHook(v); ok {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			d.byte(1)
+		} else {
+			d.byte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.u64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.u64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		d.float(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		d.float(real(c))
+		d.float(imag(c))
+	case reflect.String:
+		d.text(v.String())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			d.u64(uint64(v.Len()))
+			d.w.Write(v.Bytes())
+			return nil
+		}
+		if v.Len() == 0 {
+			d.u64(0)
+			return nil
+		}
+		k := visit{v.Pointer(), v.Type(), v.Len()}
+		if dist, ok := d.enter(k); !ok {
+			d.u64(cycleLen)
+			d.u64(uint64(dist))
+			return nil
+		}
+		err := d.encodeElems(v)
+		d.leave(k)
+		return err
+	case reflect.Array:
+		return d.encodeElems(v)
+	case reflect.Struct:
+		for _, f := range structFields(v.Type()) {
+			fv := v.Field(f.index)
+			if d.opts.SkipZero && fv.IsZero() {
+				continue
+			}
+			d.string(f.name)
+			if err := d.encode(fv); err != nil {
+				return err
+			}
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.byte(0)
+			return nil
+		}
+		k := visit{v.Pointer(), v.Type(), 0}
+		if dist, ok := d.enter(k); !ok {
+			d.byte(2)
+			d.u64(uint64(dist))
+			return nil
+		}
+		d.byte(1)
+		err := d.encode(v.Elem())
+		d.leave(k)
+		return err
+	case reflect.Interface:
+		if v.IsNil() {
+			d.byte(0)
+			return nil
+		}
+		v = v.Elem()
+		d.byte(1)
+		d.string(v.Type().String())
+		return d.encode(v)
+	case reflect.Map:
+		if v.Len() == 0 {
+			d.u64(0)
+			return nil
+		}
+		k := visit{v.Pointer(), v.Type(), 0}
+		if dist, ok := d.enter(k); !ok {
+			d.u64(cycleLen)
+			d.u64(uint64(dist))
+			return nil
+		}
+		err := d.encodeMap(v)
+		d.leave(k)
+		return err
+	default:
+		return &UnsupportedTypeError{v.Type()}
+	}
+	return nil
+}
+
+// encodeElems encodes the length and the elements of the array or slice v.
+func (d *Digest) encodeElems(v reflect.Value) error {
+	d.u64(uint64(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		if err := d.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cycleLen is the length marking a back reference to a slice or a map being encoded.
+const cycleLen = math.MaxUint64
+
+// visit identifies a pointer, a slice or a map being encoded.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter records k as being encoded. If it already is, it returns false
+// along with its distance to the current value, in levels of pointers, slices and maps.
+func (d *Digest) enter(k visit) (int, bool) {
+	if d.visiting == nil {
+		d.visiting = map[visit]int{}
+	}
+	if depth, ok := d.visiting[k]; ok {
+		return len(d.visiting) - depth, false
+	}
+	d.visiting[k] = len(d.visiting)
+	return 0, true
+}
+
+// leave records that k has been encoded.
+func (d *Digest) leave(k visit) {
+	delete(d.visiting, k)
+}
+
+// encodeMap encodes the entries of the map v in their canonical order.
+func (d *Digest) encodeMap(v reflect.Value) error {
+	type entry struct {
+		key, value []byte
+	}
+	entries := make([]entry, 0, v.Len())
+	var buf bytes.Buffer
+	md := &Digest{w: &buf, opts: d.opts, visiting: d.visiting}
+	iter := v.MapRange()
+	for iter.Next() {
+		buf.Reset()
+		if err := md.encode(iter.Key()); err != nil {
+			return err
+		}
+		n := buf.Len()
+		if err := md.encode(iter.Value()); err != nil {
+			return err
+		}
+		b := bytes.Clone(buf.Bytes())
+		entries = append(entries, entry{b[:n:n], b[n:]})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if c := bytes.Compare(entries[i].key, entries[j].key); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(entries[i].value, entries[j].value) < 0
+	})
+
+	d.u64(uint64(len(entries)))
+	for _, ent := range entries {
+		d.w.Write(ent.key)
+		d.w.Write(ent.value)
+	}
+	return nil
+}
+
+// field describes a hashed struct field.
+type field struct {
+	index int
+	name  string
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// structFields returns the hashed fields of the struct type t.
+func structFields(t reflect.Type) []field {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field)
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		switch tag := f.Tag.Get("xxhash"); tag {
+		case "-":
+			continue
+		case "":
+		default:
+			name = tag
+		}
+		fields = append(fields, field{index: i, name: name})
+	}
+	fieldCache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,196 @@
+package xxhstruct_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pierrec/xxHash/xxHash64"
+	"github.com/pierrec/xxHash/xxhstruct"
+)
+
+type config struct {
+	Name    string
+	Port    int
+	Ratio   float64
+	Tags    []string
+	Limits  map[string]int
+	Parent  *config
+	Extra   any
+	private int
+}
+
+func mustHash(t *testing.T, v any) uint64 {
+	t.Helper()
+	h, err := xxhstruct.Hash(v, 0xCAFE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHashEncoding(t *testing.T) {
+	var b []byte
+	b = binary.LittleEndian.AppendUint64(b, 3)
+	b = append(b, "abc"...)
+	if got, want := mustHash(t, "abc"), xxHash64.Checksum(b, 0xCAFE); got != want {
+		t.Errorf("string: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := mustHash(t, int8(-2)), mustHash(t, int64(-2)); got != want {
+		t.Errorf("int8: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := mustHash(t, math.Copysign(0, -1)), mustHash(t, 0.0); got != want {
+		t.Errorf("-0: got 0x%x expected 0x%x", got, want)
+	}
+	if got, want := mustHash(t, []string(nil)), mustHash(t, []string{}); got != want {
+		t.Errorf("nil slice: got 0x%x expected 0x%x", got, want)
+	}
+}
+
+func TestHashStruct(t *testing.T) {
+	newConfig := func() *config {
+		return &config{
+			Name:   "srv",
+			Port:   8080,
+			Ratio:  0.5,
+			Tags:   []string{"a", "b"},
+			Limits: map[string]int{"cpu": 2, "mem": 512, "disk": 10},
+			Parent: &config{Name: "root"},
+			Extra:  []int{1, 2},
+		}
+	}
+	a, b := newConfig(), newConfig()
+	b.private = 1
+	ha := mustHash(t, a)
+	if hb := mustHash(t, b); ha != hb {
+		t.Errorf("equal values: got 0x%x and 0x%x", ha, hb)
+	}
+
+	for i, change := range []func(c *config){
+		func(c *config) { c.Name = "srv2" },
+		func(c *config) { c.Port++ },
+		func(c *config) { c.Tags = c.Tags[:1] },
+		func(c *config) { c.Limits["cpu"] = 3 },
+		func(c *config) { c.Limits["gpu"] = 0 },
+		func(c *config) { c.Parent = nil },
+		func(c *config) { c.Parent.Port = 1 },
+		func(c *config) { c.Extra = []int64{1, 2} },
+		func(c *config) { c.Extra = nil },
+	} {
+		c := newConfig()
+		change(c)
+		if h := mustHash(t, c); h == ha {
+			t.Errorf("change %d: same hash 0x%x", i, h)
+		}
+	}
+}
+
+func TestHashUnsupported(t *testing.T) {
+	type withFunc struct {
+		F func()
+	}
+	_, err := xxhstruct.Hash(withFunc{}, 0)
+	var uerr *xxhstruct.UnsupportedTypeError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("got error %v expected an UnsupportedTypeError", err)
+	}
+	if got, want := uerr.Type.String(), "func()"; got != want {
+		t.Errorf("got type %s expected %s", got, want)
+	}
+}
+
+func TestHashTags(t *testing.T) {
+	type v1 struct {
+		ID      int
+		Updated int64 `xxhash:"-"`
+		Count   int   `xxhash:"-"`
+	}
+	type v2 struct {
+		Key int `xxhash:"ID"`
+	}
+	a := mustHash(t, v1{ID: 1, Updated: 123, Count: 4})
+	if b := mustHash(t, v1{ID: 1, Updated: 456}); a != b {
+		t.Errorf("ignored fields: got 0x%x and 0x%x", a, b)
+	}
+	if b := mustHash(t, v2{Key: 1}); a != b {
+		t.Errorf("renamed field: got 0x%x and 0x%x", a, b)
+	}
+	if b := mustHash(t, v1{ID: 2}); a == b {
+		t.Errorf("different values: same hash 0x%x", a)
+	}
+}
+
+func TestHashMap(t *testing.T) {
+	m := map[string]int{}
+	keys := []string{"a", "bb", "c", "dd", "e", "ff", "g"}
+	for i, k := range keys {
+		m[k] = i
+	}
+	// Same content, different insertion order and internal layout.
+	rm := make(map[string]int, 100)
+	for i := len(keys) - 1; i >= 0; i-- {
+		rm[keys[i]] = i
+	}
+	want := mustHash(t, m)
+	if got := mustHash(t, rm); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	// Entries are encoded in the order of their key encodings: "a" before "bb".
+	var b []byte
+	b = binary.LittleEndian.AppendUint64(b, 2)
+	b = binary.LittleEndian.AppendUint64(b, 1)
+	b = append(b, 'a')
+	b = binary.LittleEndian.AppendUint64(b, 10)
+	b = binary.LittleEndian.AppendUint64(b, 2)
+	b = append(b, "bb"...)
+	b = binary.LittleEndian.AppendUint64(b, 20)
+	if got, want := mustHash(t, map[string]int{"bb": 20, "a": 10}), xxHash64.Checksum(b, 0xCAFE); got != want {
+		t.Errorf("got 0x%x expected 0x%x", got, want)
+	}
+
+	nan := math.NaN()
+	m1 := map[float64]int{nan: 1, nan: 2, 0: 3}
+	m2 := map[float64]int{0: 3, nan: 2, nan: 1}
+	if h1, h2 := mustHash(t, m1), mustHash(t, m2); h1 != h2 {
+		t.Errorf("NaN keys: got 0x%x and 0x%x", h1, h2)
+	}
+}
+
+func TestHashCycles(t *testing.T) {
+	type node struct {
+		Name     string
+		Parent   *node
+		Children []*node
+	}
+	newTree := func(name string) *node {
+		root := &node{Name: "root"}
+		child := &node{Name: name, Parent: root}
+		root.Children = []*node{child}
+		return root
+	}
+	a, b := mustHash(t, newTree("a")), mustHash(t, newTree("a"))
+	if a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+	if c := mustHash(t, newTree("b")); a == c {
+		t.Errorf("different trees: same hash 0x%x", a)
+	}
+
+	// Self referencing slices and maps.
+	s := make([]any, 1)
+	s[0] = s
+	mustHash(t, s)
+	m := map[string]any{}
+	m["self"] = m
+	mustHash(t, m)
+
+	// Shared values that are not cycles are encoded every time.
+	shared := &node{Name: "shared"}
+	pair := [2]*node{shared, shared}
+	copies := [2]*node{{Name: "shared"}, {Name: "shared"}}
+	if a, b := mustHash(t, pair), mustHash(t, copies); a != b {
+		t.Errorf("got 0x%x and 0x%x", a, b)
+	}
+}
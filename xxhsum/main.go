@@ -4,6 +4,10 @@
 // where
 //  mode: hash mode (0=32bits, 1=64bits) (default=1)
 //  seed: seed to be used (default=0)
+//
+// The stats subcommand reports the collisions and the distribution
+// of the keys listed one per line in the given files, or stdin:
+// 	xxHash stats [-seed 123] [-sizes 1024,4096] keys1 [keys2...]
 package main
 
 import (
@@ -18,6 +22,14 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		if err := stats(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	seed := flag.Uint64("seed", 0, "uint32 or uint64 `seed` based on the selected mode (default 0)")
 	mode := flag.Int("mode", 1, "hash mode: 0=32bits, 1=64bits")
 	flag.Parse()
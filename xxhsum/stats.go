@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pierrec/xxHash/quality"
+)
+
+// stats runs the stats subcommand.
+func stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	seed := fs.Uint64("seed", 0, "`seed` of the hashes, truncated to 32 bits for XXH32 (default 0)")
+	sizes := fs.String("sizes", "", "comma separated table `sizes` (default "+join(quality.DefaultTableSizes)+")")
+	fs.Parse(args)
+
+	var tableSizes []int
+	if *sizes != "" {
+		for _, s := range strings.Split(*sizes, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n < 2 {
+				return fmt.Errorf("invalid table size %q", s)
+			}
+			tableSizes = append(tableSizes, n)
+		}
+	}
+
+	var keys [][]byte
+	read := func(r io.Reader) error {
+		k, err := quality.ReadKeys(r)
+		keys = append(keys, k...)
+		return err
+	}
+	if fs.NArg() == 0 {
+		if err := read(os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = read(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "hash\tkeys\tcollisions\tbuckets\tmax load\tskew\tchi2\tscore\t")
+	for _, h := range []quality.Hash{quality.XXH32(uint32(*seed)), quality.XXH64(*seed)} {
+		r := quality.Stats(h, keys, tableSizes...)
+		for _, d := range r.Tables {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.3f\t%.1f\t%.2f\t\n",
+				r.Hash, r.Keys, r.Collisions, d.Buckets, d.MaxLoad, d.Skew, d.ChiSquare, d.Score)
+		}
+	}
+	return w.Flush()
+}
+
+func join(ints []int) string {
+	s := make([]string, len(ints))
+	for i, n := range ints {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}